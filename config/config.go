@@ -21,6 +21,13 @@ type Configuration struct {
 	Google   Google
 	Pricer   Pricer
 	Sentry   Sentry
+	Referral Referral
+	Mpesa    Mpesa
+	Paystack Paystack
+	Payment  Payment
+	Payout   Payout
+	Document Document
+	Courier  Courier
 }
 
 // Env - load env
@@ -43,6 +50,13 @@ func LoadConfig() {
 	configuration.Google = googleConfig()
 	configuration.Pricer = pricerConfig()
 	configuration.Sentry = sentryConfig()
+	configuration.Referral = referralConfig()
+	configuration.Mpesa = mpesaConfig()
+	configuration.Paystack = paystackConfig()
+	configuration.Payment = paymentConfig()
+	configuration.Payout = payoutConfig()
+	configuration.Document = documentConfig()
+	configuration.Courier = courierConfig()
 
 	Config = &configuration
 }
@@ -184,3 +198,151 @@ func sentryConfig() Sentry {
 
 	return config
 }
+
+// referralConfig - get referral rewards config
+func referralConfig() Referral {
+	var config Referral
+
+	Env()
+
+	senderReward, err := strconv.Atoi(strings.TrimSpace(os.Getenv("REFERRAL_SENDER_REWARD")))
+	if err != nil {
+		log.WithError(err).Fatalln("referral sender reward env")
+	}
+
+	courierReward, err := strconv.Atoi(strings.TrimSpace(os.Getenv("REFERRAL_COURIER_REWARD")))
+	if err != nil {
+		log.WithError(err).Fatalln("referral courier reward env")
+	}
+
+	courierTrips, err := strconv.Atoi(strings.TrimSpace(os.Getenv("REFERRAL_COURIER_TRIPS")))
+	if err != nil {
+		log.WithError(err).Fatalln("referral courier trips env")
+	}
+
+	config.SenderReward = senderReward
+	config.CourierReward = courierReward
+	config.CourierTripTarget = courierTrips
+
+	return config
+}
+
+// mpesaConfig - get mpesa daraja config
+func mpesaConfig() Mpesa {
+	var config Mpesa
+
+	Env()
+
+	config.ConsumerKey = strings.TrimSpace(os.Getenv("MPESA_CONSUMER_KEY"))
+	config.ConsumerSecret = strings.TrimSpace(os.Getenv("MPESA_CONSUMER_SECRET"))
+	config.PassKey = strings.TrimSpace(os.Getenv("MPESA_PASS_KEY"))
+	config.BaseApi = strings.TrimSpace(os.Getenv("MPESA_BASE_API"))
+	config.ShortCode = strings.TrimSpace(os.Getenv("MPESA_SHORT_CODE"))
+	config.CallbackUrl = strings.TrimSpace(os.Getenv("MPESA_CALLBACK_URL"))
+	config.CallbackToken = strings.TrimSpace(os.Getenv("MPESA_CALLBACK_TOKEN"))
+	config.B2CShortCode = strings.TrimSpace(os.Getenv("MPESA_B2C_SHORT_CODE"))
+	config.InitiatorName = strings.TrimSpace(os.Getenv("MPESA_INITIATOR_NAME"))
+	config.SecurityCredential = strings.TrimSpace(os.Getenv("MPESA_SECURITY_CREDENTIAL"))
+	config.B2CResultUrl = strings.TrimSpace(os.Getenv("MPESA_B2C_RESULT_URL"))
+	config.B2CTimeoutUrl = strings.TrimSpace(os.Getenv("MPESA_B2C_TIMEOUT_URL"))
+	config.B2CStatusUrl = strings.TrimSpace(os.Getenv("MPESA_B2C_STATUS_URL"))
+
+	return config
+}
+
+// paystackConfig - get paystack config
+func paystackConfig() Paystack {
+	var config Paystack
+
+	Env()
+
+	config.SecretKey = strings.TrimSpace(os.Getenv("PAYSTACK_SECRET_KEY"))
+	config.BaseApi = strings.TrimSpace(os.Getenv("PAYSTACK_BASE_API"))
+	config.CallbackUrl = strings.TrimSpace(os.Getenv("PAYSTACK_CALLBACK_URL"))
+
+	return config
+}
+
+// paymentConfig - get trip payment config
+func paymentConfig() Payment {
+	var config Payment
+
+	Env()
+
+	requirePayment, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("REQUIRE_TRIP_PAYMENT")))
+	if err != nil {
+		log.WithError(err).Fatalln("require trip payment env")
+	}
+
+	codCashLimit, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COD_CASH_LIMIT")))
+	if err != nil {
+		log.WithError(err).Fatalln("cod cash limit env")
+	}
+
+	config.RequireTripPayment = requirePayment
+	config.CodCashLimit = codCashLimit
+
+	return config
+}
+
+// payoutConfig - get courier payout config
+func payoutConfig() Payout {
+	var config Payout
+
+	Env()
+
+	minimum, err := strconv.Atoi(strings.TrimSpace(os.Getenv("PAYOUT_MINIMUM")))
+	if err != nil {
+		log.WithError(err).Fatalln("payout minimum env")
+	}
+
+	maxAttempts, err := strconv.Atoi(strings.TrimSpace(os.Getenv("PAYOUT_MAX_ATTEMPTS")))
+	if err != nil {
+		log.WithError(err).Fatalln("payout max attempts env")
+	}
+
+	config.Schedule = strings.ToUpper(strings.TrimSpace(os.Getenv("PAYOUT_SCHEDULE")))
+	config.Minimum = minimum
+	config.MaxAttempts = maxAttempts
+
+	return config
+}
+
+// documentConfig - get courier document expiry config
+func documentConfig() Document {
+	var config Document
+
+	Env()
+
+	warningDays, err := strconv.Atoi(strings.TrimSpace(os.Getenv("DOCUMENT_EXPIRY_WARNING_DAYS")))
+	if err != nil {
+		log.WithError(err).Fatalln("document expiry warning days env")
+	}
+
+	config.ExpiryWarningDays = warningDays
+
+	return config
+}
+
+// courierConfig - get courier availability config
+func courierConfig() Courier {
+	var config Courier
+
+	Env()
+
+	timeout, err := time.ParseDuration(strings.TrimSpace(os.Getenv("COURIER_HEARTBEAT_TIMEOUT")))
+	if err != nil {
+		log.WithError(err).Fatalln("courier heartbeat timeout parsing")
+	}
+
+	config.HeartbeatTimeout = timeout
+
+	flushInterval, err := time.ParseDuration(strings.TrimSpace(os.Getenv("COURIER_LOCATION_FLUSH_INTERVAL")))
+	if err != nil {
+		log.WithError(err).Fatalln("courier location flush interval parsing")
+	}
+
+	config.LocationFlushInterval = flushInterval
+
+	return config
+}
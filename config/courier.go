@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Courier struct {
+	// Couriers whose GPS pings stop for longer go offline
+	HeartbeatTimeout time.Duration
+	// How often live courier positions are written back to Postgres
+	LocationFlushInterval time.Duration
+}
@@ -0,0 +1,6 @@
+package config
+
+type Document struct {
+	// Days before expiry couriers are reminded to renew a document
+	ExpiryWarningDays int
+}
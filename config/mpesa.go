@@ -0,0 +1,19 @@
+package config
+
+type Mpesa struct {
+	ConsumerKey    string
+	ConsumerSecret string
+	PassKey        string
+	BaseApi        string
+	ShortCode      string
+	CallbackUrl    string
+	// Shared secret daraja echoes back on callback urls
+	CallbackToken string
+	// B2C payouts
+	B2CShortCode       string
+	InitiatorName      string
+	SecurityCredential string
+	B2CResultUrl       string
+	B2CTimeoutUrl      string
+	B2CStatusUrl       string
+}
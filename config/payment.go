@@ -0,0 +1,7 @@
+package config
+
+type Payment struct {
+	RequireTripPayment bool
+	// Cash on delivery a courier can hold before ops follow up
+	CodCashLimit int
+}
@@ -0,0 +1,8 @@
+package config
+
+type Payout struct {
+	// DAILY or WEEKLY
+	Schedule    string
+	Minimum     int
+	MaxAttempts int
+}
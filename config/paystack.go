@@ -0,0 +1,7 @@
+package config
+
+type Paystack struct {
+	SecretKey   string
+	BaseApi     string
+	CallbackUrl string
+}
@@ -0,0 +1,7 @@
+package config
+
+type Referral struct {
+	SenderReward      int
+	CourierReward     int
+	CourierTripTarget int
+}
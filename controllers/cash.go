@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+)
+
+var (
+	cashService CashController
+)
+
+type CashController interface {
+	GetCouriersHoldingCash(minimum int) ([]*model.CourierCashBalance, error)
+}
+
+type cashClient struct {
+	r *r.CashRepository
+}
+
+func NewCashController(q *sqlc.Queries) {
+	cr := &r.CashRepository{}
+	cr.Init(q)
+	cashService = &cashClient{cr}
+}
+
+func GetCashController() CashController {
+	return cashService
+}
+
+func (c *cashClient) GetCouriersHoldingCash(minimum int) ([]*model.CourierCashBalance, error) {
+	return c.r.GetCouriersHoldingCash(minimum)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/edwinlomolo/uzi-api/config"
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
 	"github.com/edwinlomolo/uzi-api/repository"
@@ -19,6 +21,9 @@ var (
 	cService               CourierController
 )
 
+// heartbeatInterval - how often couriers with stale GPS pings are taken offline
+const heartbeatInterval = 30 * time.Second
+
 type CourierController interface {
 	FindOrCreate(userID uuid.UUID) (*model.Courier, error)
 	IsCourier(userID uuid.UUID) (bool, error)
@@ -26,8 +31,12 @@ type CourierController interface {
 	GetCourierByUserID(userID uuid.UUID) (*model.Courier, error)
 	GetCourierByID(courierID uuid.UUID) (*model.Courier, error)
 	TrackCourierLocation(userID uuid.UUID, input model.GpsInput) error
-	UpdateCourierStatus(userID uuid.UUID, status model.CourierStatus) (bool, error)
+	TrackCourierLocations(userID uuid.UUID, points []*model.GpsPointInput) error
+	UpdateCourierStatus(userID uuid.UUID, status model.CourierStatus) (*model.CourierStatusUpdate, error)
 	GetCourierProduct(productID uuid.UUID) (*model.Product, error)
+	ScheduleLocationFlush()
+	ScheduleBreadcrumbPartitions()
+	ScheduleHeartbeatChecks()
 }
 
 type courierClient struct {
@@ -66,57 +75,73 @@ func (c *courierClient) GetCourierByUserID(userID uuid.UUID) (*model.Courier, er
 	return c.r.GetCourierByUserID(userID)
 }
 
+// TrackCourierLocation - record the ping and pass it on to the customer
+// when the courier is heading to pickup
 func (c *courierClient) TrackCourierLocation(userID uuid.UUID, input model.GpsInput) error {
-	if err := c.r.TrackCourierLocation(userID, input); err != nil {
+	ping, err := c.r.TrackCourierLocation(userID, input)
+	if err != nil {
 		return err
 	}
 
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		courier, err := c.r.GetCourierByUserID(userID)
-		if err != nil {
-			return
-		}
+	c.publishCourierLocation(ping)
+	return nil
+}
 
-		t, err := c.r.GetCourierTrip(courier.ID)
-		if err != nil && errors.Is(err, ErrCourierTripNotFound) {
-			c.log.WithError(ErrCourierTripNotFound).Errorf("is courier tripping")
-			return
-		} else if err != nil {
+// TrackCourierLocations - record points buffered while the courier was
+// offline. Only the freshest point reaches the customer.
+func (c *courierClient) TrackCourierLocations(userID uuid.UUID, points []*model.GpsPointInput) error {
+	ping, err := c.r.TrackCourierLocations(userID, points)
+	if err != nil {
+		return err
+	}
+
+	c.publishCourierLocation(ping)
+	return nil
+}
+
+// publishCourierLocation - trip update for the customer when the live position
+// moved while the courier is arriving or en route
+func (c *courierClient) publishCourierLocation(ping *internal.CourierPing) {
+	if ping == nil || ping.TripID == nil {
+		return
+	}
+
+	t, err := c.r.GetCourierTrip(ping.CourierID, *ping.TripID)
+	if err != nil && errors.Is(err, repository.ErrCourierTripNotFound) {
+		c.log.WithError(ErrCourierTripNotFound).Errorf("is courier tripping")
+		return
+	} else if err != nil {
+		return
+	}
+
+	if t.Status == model.TripStatusCourierEnRoute || t.Status == model.TripStatusCourierArriving {
+		tripUpdate := model.TripUpdate{
+			ID:     t.ID,
+			Status: model.TripStatus(t.Status),
+			Location: &model.Gps{
+				Lat: ping.Lat,
+				Lng: ping.Lng,
+			},
+		}
+		u, marshalErr := json.Marshal(tripUpdate)
+		if marshalErr != nil {
+			c.log.WithError(marshalErr).Errorf("courier service: marshal courier arriving/enroute trip update")
 			return
 		}
-
-		if t != nil && (t.Status == model.TripStatusCourierEnRoute || t.Status == model.TripStatusCourierArriving) {
-			tripUpdate := model.TripUpdate{
-				ID:     t.ID,
-				Status: model.TripStatus(t.Status),
-				Location: &model.Gps{
-					Lat: input.Lat,
-					Lng: input.Lng,
-				},
-			}
-			u, marshalErr := json.Marshal(tripUpdate)
-			if marshalErr != nil {
-				c.log.WithError(marshalErr).Errorf("courier service: marshal courier arriving/enroute trip update")
-				return
-			}
-			tripUpdateErr := c.cache.GetRedis().Publish(context.Background(), internal.TRIP_UPDATES_CHANNEL, u).Err()
-			if tripUpdateErr != nil {
-				c.log.WithFields(logrus.Fields{
-					"status":  t.Status,
-					"trip_id": t.ID,
-				}).WithError(tripUpdateErr).Errorf("courier service: publish courier arriving/enroute trip update")
-				return
-			}
+		tripUpdateErr := c.cache.GetRedis().Publish(context.Background(), internal.TRIP_UPDATES_CHANNEL, u).Err()
+		if tripUpdateErr != nil {
+			c.log.WithFields(logrus.Fields{
+				"status":  t.Status,
+				"trip_id": t.ID,
+			}).WithError(tripUpdateErr).Errorf("courier service: publish courier arriving/enroute trip update")
 		}
-	}()
-	<-done
-
-	return nil
+	}
 }
 
-func (c *courierClient) UpdateCourierStatus(userID uuid.UUID, status model.CourierStatus) (bool, error) {
+func (c *courierClient) UpdateCourierStatus(
+	userID uuid.UUID,
+	status model.CourierStatus,
+) (*model.CourierStatusUpdate, error) {
 	return c.r.UpdateCourierStatus(userID, status)
 }
 
@@ -127,3 +152,38 @@ func (c *courierClient) GetCourierProduct(productID uuid.UUID) (*model.Product,
 func (c *courierClient) GetCourierByID(courierID uuid.UUID) (*model.Courier, error) {
 	return c.r.GetCourierByID(courierID)
 }
+
+// ScheduleLocationFlush - write live courier positions to Postgres until the
+// process exits
+func (c *courierClient) ScheduleLocationFlush() {
+	ticker := time.NewTicker(config.Config.Courier.LocationFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		c.r.FlushCourierLocations(time.Now())
+		<-ticker.C
+	}
+}
+
+// ScheduleBreadcrumbPartitions - keep next month's GPS history partition ready
+// until the process exits
+func (c *courierClient) ScheduleBreadcrumbPartitions() {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		c.r.EnsureBreadcrumbPartitions(time.Now())
+		<-ticker.C
+	}
+}
+
+// ScheduleHeartbeatChecks - take silent couriers offline until the process exits
+func (c *courierClient) ScheduleHeartbeatChecks() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		c.r.SetStaleCouriersOffline(time.Now())
+		<-ticker.C
+	}
+}
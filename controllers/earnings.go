@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	earningsService EarningsController
+)
+
+type EarningsController interface {
+	RecordTripEarnings(trip model.Trip) error
+	AdjustCourierEarnings(input model.CourierEarningsAdjustmentInput) error
+	GetCourierEarnings(courierUserID uuid.UUID, period model.EarningsPeriod) (*model.CourierEarnings, error)
+}
+
+type earningsClient struct {
+	r *r.EarningsRepository
+}
+
+func NewEarningsController(q *sqlc.Queries) {
+	er := &r.EarningsRepository{}
+	er.Init(q)
+	earningsService = &earningsClient{er}
+}
+
+func GetEarningsController() EarningsController {
+	return earningsService
+}
+
+func (e *earningsClient) RecordTripEarnings(trip model.Trip) error {
+	return e.r.RecordTripEarnings(trip)
+}
+
+func (e *earningsClient) AdjustCourierEarnings(input model.CourierEarningsAdjustmentInput) error {
+	return e.r.AdjustCourierEarnings(input)
+}
+
+func (e *earningsClient) GetCourierEarnings(
+	courierUserID uuid.UUID,
+	period model.EarningsPeriod,
+) (*model.CourierEarnings, error) {
+	return e.r.GetCourierEarnings(courierUserID, period)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	fleetService FleetController
+)
+
+type FleetController interface {
+	CreateFleet(input model.FleetInput) (*model.Fleet, error)
+	SetFleetRiderShare(fleetID uuid.UUID, riderShare int) (*model.Fleet, error)
+	AddFleetManager(fleetID, userID uuid.UUID) error
+	IsFleetManager(fleetID, userID uuid.UUID) (bool, error)
+	OwnsFleet(userID uuid.UUID) (bool, error)
+	GetManagedFleets(userID uuid.UUID) ([]*model.Fleet, error)
+	InviteFleetCourier(managerID, fleetID uuid.UUID, phone string) error
+	GetFleetInvitations(userID uuid.UUID) ([]*model.FleetInvitation, error)
+	AcceptFleetInvitation(userID, invitationID uuid.UUID) error
+	DeclineFleetInvitation(userID, invitationID uuid.UUID) error
+	RemoveFleetCourier(fleetID, courierID uuid.UUID) error
+	GetFleetRiders(fleetID uuid.UUID) ([]*model.FleetRider, error)
+	GetFleetTrips(fleetID uuid.UUID, limit, offset int) ([]*model.Trip, error)
+	GetFleetPendingVehicles(fleetID uuid.UUID) ([]*model.Vehicle, error)
+	ApproveFleetVehicle(managerID, fleetID, vehicleID uuid.UUID) (*model.Vehicle, error)
+	RejectFleetVehicle(managerID, fleetID, vehicleID uuid.UUID, reason string) (*model.Vehicle, error)
+}
+
+type fleetClient struct {
+	r *r.FleetRepository
+}
+
+func NewFleetController(q *sqlc.Queries) {
+	fr := &r.FleetRepository{}
+	fr.Init(q)
+	fleetService = &fleetClient{fr}
+}
+
+func GetFleetController() FleetController {
+	return fleetService
+}
+
+func (f *fleetClient) CreateFleet(input model.FleetInput) (*model.Fleet, error) {
+	return f.r.CreateFleet(input)
+}
+
+func (f *fleetClient) SetFleetRiderShare(fleetID uuid.UUID, riderShare int) (*model.Fleet, error) {
+	return f.r.SetFleetRiderShare(fleetID, riderShare)
+}
+
+func (f *fleetClient) AddFleetManager(fleetID, userID uuid.UUID) error {
+	return f.r.AddFleetManager(fleetID, userID)
+}
+
+func (f *fleetClient) IsFleetManager(fleetID, userID uuid.UUID) (bool, error) {
+	return f.r.IsFleetManager(fleetID, userID)
+}
+
+func (f *fleetClient) OwnsFleet(userID uuid.UUID) (bool, error) {
+	return f.r.OwnsFleet(userID)
+}
+
+func (f *fleetClient) GetManagedFleets(userID uuid.UUID) ([]*model.Fleet, error) {
+	return f.r.GetManagedFleets(userID)
+}
+
+func (f *fleetClient) InviteFleetCourier(managerID, fleetID uuid.UUID, phone string) error {
+	return f.r.InviteFleetCourier(managerID, fleetID, phone)
+}
+
+func (f *fleetClient) GetFleetInvitations(userID uuid.UUID) ([]*model.FleetInvitation, error) {
+	return f.r.GetFleetInvitations(userID)
+}
+
+func (f *fleetClient) AcceptFleetInvitation(userID, invitationID uuid.UUID) error {
+	return f.r.RespondFleetInvitation(userID, invitationID, true)
+}
+
+func (f *fleetClient) DeclineFleetInvitation(userID, invitationID uuid.UUID) error {
+	return f.r.RespondFleetInvitation(userID, invitationID, false)
+}
+
+func (f *fleetClient) RemoveFleetCourier(fleetID, courierID uuid.UUID) error {
+	return f.r.RemoveFleetCourier(fleetID, courierID)
+}
+
+func (f *fleetClient) GetFleetRiders(fleetID uuid.UUID) ([]*model.FleetRider, error) {
+	return f.r.GetFleetRiders(fleetID)
+}
+
+func (f *fleetClient) GetFleetTrips(fleetID uuid.UUID, limit, offset int) ([]*model.Trip, error) {
+	return f.r.GetFleetTrips(fleetID, limit, offset)
+}
+
+func (f *fleetClient) GetFleetPendingVehicles(fleetID uuid.UUID) ([]*model.Vehicle, error) {
+	return f.r.GetFleetPendingVehicles(fleetID)
+}
+
+func (f *fleetClient) ApproveFleetVehicle(managerID, fleetID, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	return f.r.ReviewFleetVehicle(managerID, fleetID, vehicleID, model.VehicleStatusApproved, "")
+}
+
+func (f *fleetClient) RejectFleetVehicle(managerID, fleetID, vehicleID uuid.UUID, reason string) (*model.Vehicle, error) {
+	return f.r.ReviewFleetVehicle(managerID, fleetID, vehicleID, model.VehicleStatusRejected, reason)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	onboardingService OnboardingController
+)
+
+type OnboardingController interface {
+	GetCourierOnboarding(courierID uuid.UUID) (*model.CourierOnboarding, error)
+}
+
+type onboardingClient struct {
+	r *r.OnboardingRepository
+}
+
+func NewOnboardingController(q *sqlc.Queries) {
+	or := &r.OnboardingRepository{}
+	or.Init(q)
+	onboardingService = &onboardingClient{or}
+}
+
+func GetOnboardingController() OnboardingController {
+	return onboardingService
+}
+
+func (o *onboardingClient) GetCourierOnboarding(courierID uuid.UUID) (*model.CourierOnboarding, error) {
+	return o.r.GetCourierOnboarding(courierID)
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	paymentService PaymentController
+)
+
+type PaymentController interface {
+	PayTripWithMpesa(userID uuid.UUID, input model.MpesaPaymentInput) (*model.Payment, error)
+	MpesaCallback(callback internal.StkCallback) error
+	PayTripWithCard(userID uuid.UUID, input model.CardPaymentInput) (*model.Payment, error)
+	PaystackWebhook(event internal.PaystackEvent) error
+	RefundTripPayment(userID, tripID uuid.UUID) (*model.Payment, error)
+	PayTripWithWallet(userID, tripID uuid.UUID) (*model.Payment, error)
+	TopUpWallet(userID uuid.UUID, input model.WalletTopUpInput) (*model.Payment, error)
+	RemitCodCash(userID uuid.UUID, phone string) (*model.Payment, error)
+	TipCourier(userID, tripID uuid.UUID, amount int) (*model.Payment, error)
+	GetTripPayment(userID, tripID uuid.UUID, admin bool) (*model.Payment, error)
+	GetPaymentCards(userID uuid.UUID) ([]*model.PaymentCard, error)
+}
+
+type paymentClient struct {
+	r    *r.PaymentRepository
+	log  *logrus.Logger
+	trip TripController
+}
+
+func NewPaymentController(q *sqlc.Queries) {
+	pr := &r.PaymentRepository{}
+	pr.Init(q)
+	paymentService = &paymentClient{
+		pr,
+		internal.GetLogger(),
+		GetTripController(),
+	}
+}
+
+func GetPaymentController() PaymentController {
+	return paymentService
+}
+
+func (p *paymentClient) PayTripWithMpesa(
+	userID uuid.UUID,
+	input model.MpesaPaymentInput,
+) (*model.Payment, error) {
+	trip, err := p.trip.GetTripDetails(input.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	phone := ""
+	if input.Phone != nil {
+		phone = *input.Phone
+	}
+
+	return p.r.CreateMpesaPayment(userID, *trip, phone)
+}
+
+func (p *paymentClient) MpesaCallback(callback internal.StkCallback) error {
+	payment, settled, err := p.r.CompleteMpesaPayment(callback)
+	if err != nil {
+		return err
+	}
+
+	if !settled {
+		return nil
+	}
+
+	p.notifyTip(payment)
+
+	return p.startPaidTrip(payment)
+}
+
+func (p *paymentClient) PayTripWithCard(
+	userID uuid.UUID,
+	input model.CardPaymentInput,
+) (*model.Payment, error) {
+	trip, err := p.trip.GetTripDetails(input.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := p.r.CreateCardPayment(userID, *trip, input.CardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Saved card charges can settle right away
+	if err := p.startPaidTrip(payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (p *paymentClient) PaystackWebhook(event internal.PaystackEvent) error {
+	payment, settled, err := p.r.CompletePaystackEvent(event)
+	if err != nil {
+		return err
+	}
+
+	if !settled {
+		return nil
+	}
+
+	p.notifyTip(payment)
+
+	return p.startPaidTrip(payment)
+}
+
+func (p *paymentClient) RefundTripPayment(userID, tripID uuid.UUID) (*model.Payment, error) {
+	trip, err := p.trip.GetTripDetails(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.r.RefundTripPayment(userID, *trip)
+}
+
+func (p *paymentClient) PayTripWithWallet(userID, tripID uuid.UUID) (*model.Payment, error) {
+	trip, err := p.trip.GetTripDetails(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := p.r.CreateWalletPayment(userID, *trip)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.startPaidTrip(payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (p *paymentClient) TopUpWallet(
+	userID uuid.UUID,
+	input model.WalletTopUpInput,
+) (*model.Payment, error) {
+	switch input.Provider {
+	case model.PaymentProviderMpesa:
+		phone := ""
+		if input.Phone != nil {
+			phone = *input.Phone
+		}
+		return p.r.CreateMpesaTopUp(userID, input.Amount, phone)
+	case model.PaymentProviderPaystack:
+		return p.r.CreateCardTopUp(userID, input.Amount, input.CardID)
+	default:
+		return nil, r.ErrTopUpProvider
+	}
+}
+
+func (p *paymentClient) RemitCodCash(userID uuid.UUID, phone string) (*model.Payment, error) {
+	return p.r.CreateMpesaRemittance(userID, phone)
+}
+
+func (p *paymentClient) TipCourier(userID, tripID uuid.UUID, amount int) (*model.Payment, error) {
+	trip, err := p.trip.GetTripDetails(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := p.r.CreateTip(userID, *trip, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wallet and saved card tips settle right away
+	p.notifyTip(payment)
+
+	return payment, nil
+}
+
+func (p *paymentClient) GetPaymentCards(userID uuid.UUID) ([]*model.PaymentCard, error) {
+	return p.r.GetUserPaymentCards(userID)
+}
+
+// startPaidTrip - start matching trips that were held for payment
+func (p *paymentClient) startPaidTrip(payment *model.Payment) error {
+	if payment == nil ||
+		payment.TripID == nil ||
+		payment.Status != model.PaymentStatusPaid {
+		return nil
+	}
+
+	trip, err := p.trip.GetTripDetails(*payment.TripID)
+	if err != nil {
+		return err
+	}
+
+	if trip.Status == model.TripStatusAwaitingPayment {
+		p.trip.MatchCourier(trip.ID, model.TripInput{
+			Location: &model.GpsInput{
+				Lat: trip.StartLocation.Lat,
+				Lng: trip.StartLocation.Lng,
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *paymentClient) notifyTip(payment *model.Payment) {
+	if payment == nil ||
+		payment.TripID == nil ||
+		payment.Purpose != model.PaymentPurposeTip ||
+		payment.Status != model.PaymentStatusPaid {
+		return
+	}
+
+	if err := p.trip.NotifyCourierTip(*payment.TripID, payment.Amount); err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"trip_id":    *payment.TripID,
+		}).WithError(err).Errorf("notify courier tip")
+	}
+}
+
+func (p *paymentClient) GetTripPayment(userID, tripID uuid.UUID, admin bool) (*model.Payment, error) {
+	return p.r.GetTripPayment(userID, tripID, admin)
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+)
+
+var (
+	payoutService PayoutController
+)
+
+// payoutInterval - how often settled earnings are batched and retries are sent
+const payoutInterval = 15 * time.Minute
+
+type PayoutController interface {
+	SchedulePayouts()
+	B2CResult(result internal.B2CResult) error
+	B2CStatus(result internal.TransactionStatusResult) error
+	GetFailedPayouts(limit, offset int) ([]*model.Payout, error)
+}
+
+type payoutClient struct {
+	r *r.PayoutRepository
+}
+
+func NewPayoutController(q *sqlc.Queries) {
+	pr := &r.PayoutRepository{}
+	pr.Init(q)
+	payoutService = &payoutClient{pr}
+}
+
+func GetPayoutController() PayoutController {
+	return payoutService
+}
+
+// SchedulePayouts - batch and submit courier payouts until the process exits
+func (p *payoutClient) SchedulePayouts() {
+	ticker := time.NewTicker(payoutInterval)
+	defer ticker.Stop()
+
+	for {
+		p.runPayouts()
+		<-ticker.C
+	}
+}
+
+func (p *payoutClient) runPayouts() {
+	if err := p.r.BatchPayouts(time.Now()); err != nil {
+		return
+	}
+
+	p.r.SubmitDuePayouts()
+}
+
+func (p *payoutClient) B2CResult(result internal.B2CResult) error {
+	return p.r.CompleteB2CResult(result)
+}
+
+func (p *payoutClient) B2CStatus(result internal.TransactionStatusResult) error {
+	return p.r.CompleteB2CStatus(result)
+}
+
+func (p *payoutClient) GetFailedPayouts(limit, offset int) ([]*model.Payout, error) {
+	return p.r.GetFailedPayouts(limit, offset)
+}
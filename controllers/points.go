@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	pointsService PointsController
+)
+
+type PointsController interface {
+	AwardTripPoints(tripID uuid.UUID) error
+	DeductCancellation(courierID, tripID uuid.UUID) error
+	GetCourierPerformance(courierID uuid.UUID) (*model.CourierPerformance, error)
+}
+
+type pointsClient struct {
+	r *r.PointsRepository
+}
+
+func NewPointsController(q *sqlc.Queries) {
+	pr := &r.PointsRepository{}
+	pr.Init(q)
+	pointsService = &pointsClient{pr}
+}
+
+func GetPointsController() PointsController {
+	return pointsService
+}
+
+func (p *pointsClient) AwardTripPoints(tripID uuid.UUID) error {
+	return p.r.AwardTripPoints(tripID)
+}
+
+func (p *pointsClient) DeductCancellation(courierID, tripID uuid.UUID) error {
+	return p.r.DeductCancellation(courierID, tripID)
+}
+
+func (p *pointsClient) GetCourierPerformance(courierID uuid.UUID) (*model.CourierPerformance, error) {
+	return p.r.GetCourierPerformance(courierID)
+}
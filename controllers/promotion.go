@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	promoService PromotionController
+)
+
+type PromotionController interface {
+	ApplyPromoCode(userID uuid.UUID, input model.ApplyPromoCodeInput) (*model.PromoQuote, error)
+	RedeemTripPromotion(trip model.Trip, price int) (int, error)
+}
+
+type promotionClient struct {
+	r *r.PromotionRepository
+}
+
+func NewPromotionController(q *sqlc.Queries) {
+	pr := &r.PromotionRepository{}
+	pr.Init(q)
+	promoService = &promotionClient{pr}
+}
+
+func GetPromotionController() PromotionController {
+	return promoService
+}
+
+func (p *promotionClient) ApplyPromoCode(
+	userID uuid.UUID,
+	input model.ApplyPromoCodeInput,
+) (*model.PromoQuote, error) {
+	return p.r.ApplyPromoCode(userID, input)
+}
+
+func (p *promotionClient) RedeemTripPromotion(trip model.Trip, price int) (int, error) {
+	if trip.PromotionID == nil {
+		return 0, nil
+	}
+
+	return p.r.RedeemPromotion(*trip.PromotionID, trip, price)
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	questService QuestController
+)
+
+type QuestController interface {
+	CreateQuest(input model.QuestInput) (*model.Quest, error)
+	RecordTripProgress(trip model.Trip) error
+	GetCourierQuests(courierID uuid.UUID) ([]*model.CourierQuest, error)
+}
+
+type questClient struct {
+	r *r.QuestRepository
+}
+
+func NewQuestController(q *sqlc.Queries) {
+	qr := &r.QuestRepository{}
+	qr.Init(q)
+	questService = &questClient{qr}
+}
+
+func GetQuestController() QuestController {
+	return questService
+}
+
+func (q *questClient) CreateQuest(input model.QuestInput) (*model.Quest, error) {
+	return q.r.CreateQuest(input)
+}
+
+func (q *questClient) RecordTripProgress(trip model.Trip) error {
+	return q.r.RecordTripProgress(trip)
+}
+
+func (q *questClient) GetCourierQuests(courierID uuid.UUID) ([]*model.CourierQuest, error) {
+	return q.r.GetCourierQuests(courierID)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	ratingService RatingController
+)
+
+type RatingController interface {
+	RateTrip(userID uuid.UUID, input model.TripRatingInput) (*model.TripRating, error)
+	GetTripRatings(userID, tripID uuid.UUID) ([]*model.TripRating, error)
+}
+
+type ratingClient struct {
+	r *r.RatingRepository
+}
+
+func NewRatingController(q *sqlc.Queries) {
+	rr := &r.RatingRepository{}
+	rr.Init(q)
+	ratingService = &ratingClient{rr}
+}
+
+func GetRatingController() RatingController {
+	return ratingService
+}
+
+func (r *ratingClient) RateTrip(userID uuid.UUID, input model.TripRatingInput) (*model.TripRating, error) {
+	return r.r.RateTrip(userID, input)
+}
+
+func (r *ratingClient) GetTripRatings(userID, tripID uuid.UUID) ([]*model.TripRating, error) {
+	return r.r.GetTripRatings(userID, tripID)
+}
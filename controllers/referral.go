@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+)
+
+var (
+	referralService ReferralController
+)
+
+type ReferralController interface {
+	RewardTripReferrals(trip model.Trip) error
+	ApplyPromoBalance(trip model.Trip, cost int) (int, error)
+}
+
+type referralClient struct {
+	r *r.ReferralRepository
+}
+
+func NewReferralController(q *sqlc.Queries) {
+	rr := &r.ReferralRepository{}
+	rr.Init(q)
+	referralService = &referralClient{rr}
+}
+
+func GetReferralController() ReferralController {
+	return referralService
+}
+
+func (rc *referralClient) RewardTripReferrals(trip model.Trip) error {
+	return rc.r.RewardTripReferrals(trip)
+}
+
+func (rc *referralClient) ApplyPromoBalance(trip model.Trip, cost int) (int, error) {
+	return rc.r.ApplyPromoBalance(trip, cost)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	shiftService ShiftController
+)
+
+// shiftSettlementInterval - how often ended shifts are settled
+const shiftSettlementInterval = 15 * time.Minute
+
+type ShiftController interface {
+	CreateShift(input model.ShiftInput) (*model.Shift, error)
+	GetUpcomingShifts(zoneID *uuid.UUID) ([]*model.Shift, error)
+	GetCourierShiftBookings(courierID uuid.UUID, limit, offset int) ([]*model.ShiftBooking, error)
+	BookShift(courierID, shiftID uuid.UUID) (*model.ShiftBooking, error)
+	CancelShiftBooking(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error)
+	CheckInShift(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error)
+	ScheduleShiftSettlement()
+}
+
+type shiftClient struct {
+	r *r.ShiftRepository
+}
+
+func NewShiftController(q *sqlc.Queries) {
+	sr := &r.ShiftRepository{}
+	sr.Init(q)
+	shiftService = &shiftClient{sr}
+}
+
+func GetShiftController() ShiftController {
+	return shiftService
+}
+
+func (s *shiftClient) CreateShift(input model.ShiftInput) (*model.Shift, error) {
+	return s.r.CreateShift(input)
+}
+
+func (s *shiftClient) GetUpcomingShifts(zoneID *uuid.UUID) ([]*model.Shift, error) {
+	return s.r.GetUpcomingShifts(zoneID)
+}
+
+func (s *shiftClient) GetCourierShiftBookings(courierID uuid.UUID, limit, offset int) ([]*model.ShiftBooking, error) {
+	return s.r.GetCourierShiftBookings(courierID, limit, offset)
+}
+
+func (s *shiftClient) BookShift(courierID, shiftID uuid.UUID) (*model.ShiftBooking, error) {
+	return s.r.BookShift(courierID, shiftID)
+}
+
+func (s *shiftClient) CancelShiftBooking(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	return s.r.CancelShiftBooking(courierID, bookingID)
+}
+
+func (s *shiftClient) CheckInShift(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	return s.r.CheckInShift(courierID, bookingID)
+}
+
+// ScheduleShiftSettlement - settle ended shifts until the process exits
+func (s *shiftClient) ScheduleShiftSettlement() {
+	ticker := time.NewTicker(shiftSettlementInterval)
+	defer ticker.Stop()
+
+	for {
+		s.r.SettleEndedShifts(time.Now())
+		<-ticker.C
+	}
+}
@@ -25,29 +25,41 @@ var (
 )
 
 type TripController interface {
-	FindAvailableCourier(pickup model.GpsInput) (*model.Courier, error)
+	FindAvailableCourier(pickup model.GpsInput, productID uuid.UUID) (*model.Courier, error)
 	GetCourierNearPickupPoint(pickup model.GpsInput) ([]*model.Courier, error)
 	AssignCourierToTrip(tripID, courierID uuid.UUID) error
-	UnassignTrip(courierID uuid.UUID) error
+	UnassignTrip(tripID, courierID uuid.UUID) error
 	CreateTrip(sqlStore.CreateTripParams) (*model.Trip, error)
+	QuoteTripCost(productID uuid.UUID, pickup, dropoff model.Geocode) (int, error)
 	SetTripStatus(tripID uuid.UUID, status model.TripStatus) error
 	MatchCourier(tripID uuid.UUID, pickup model.TripInput)
 	CreateTripRecipient(tripID uuid.UUID, input model.TripRecipientInput) error
 	GetTripRecipient(tripID uuid.UUID) (*model.Recipient, error)
 	GetTripDetails(tripID uuid.UUID) (*model.Trip, error)
+	GetTripPath(userID, tripID uuid.UUID, admin bool) (*model.TripPath, error)
 	GetCourierAssignedTrip(courierID uuid.UUID) error
 	GetTripCourier(courierID uuid.UUID) (*model.Courier, error)
 	ReportTripStatus(tripID uuid.UUID, status model.TripStatus) error
 	ComputeTripRoute(input model.TripRouteInput) (*model.TripRoute, error)
 	ParsePickupDropoff(input model.TripInput) (*model.Geocode, error)
+	AwaitTripPayment(tripID uuid.UUID) (*model.Trip, error)
+	NotifyCourierTip(tripID uuid.UUID, amount int) error
+	CourierCancelTrip(courierID, tripID uuid.UUID) error
+	CourierCompleteTrip(courierID, tripID uuid.UUID, collected *int) error
 }
 
 type tripClient struct {
-	r     *r.TripRepository
-	mu    sync.Mutex
-	log   *logrus.Logger
-	cache internal.Cache
-	p     internal.Pricing
+	r        *r.TripRepository
+	mu       sync.Mutex
+	log      *logrus.Logger
+	cache    internal.Cache
+	p        internal.Pricing
+	promo    PromotionController
+	referral ReferralController
+	wallet   WalletController
+	earnings EarningsController
+	points   PointsController
+	quests   QuestController
 }
 
 func NewTripController(q *sqlc.Queries) {
@@ -59,6 +71,12 @@ func NewTripController(q *sqlc.Queries) {
 		internal.GetLogger(),
 		internal.GetCache(),
 		internal.GetPricer(),
+		GetPromotionController(),
+		GetReferralController(),
+		GetWalletController(),
+		GetEarningsController(),
+		GetPointsController(),
+		GetQuestController(),
 	}
 }
 
@@ -115,6 +133,8 @@ func (t *tripClient) computeRoute(pickup, dropoff model.Geocode) (*model.TripRou
 
 		tripRoute.Polyline = routeRes.Routes[0].Polyline.EncodedPolyline
 		tripRoute.Distance = routeRes.Routes[0].Distance
+		tripRoute.Duration = parseRouteDuration(routeRes.Routes[0].Duration)
+		tripRoute.StaticDuration = parseRouteDuration(routeRes.Routes[0].StaticDuration)
 
 		// Short-circuit google route api with cache here not to super-charge in dev
 		if isDev() {
@@ -126,6 +146,8 @@ func (t *tripClient) computeRoute(pickup, dropoff model.Geocode) (*model.TripRou
 		route := (tripInfo).(*model.TripRoute)
 		tripRoute.Polyline = route.Polyline
 		tripRoute.Distance = route.Distance
+		tripRoute.Duration = route.Duration
+		tripRoute.StaticDuration = route.StaticDuration
 	}
 
 	nearbyParams := sqlc.GetNearbyAvailableCourierProductsParams{
@@ -138,7 +160,8 @@ func (t *tripClient) computeRoute(pickup, dropoff model.Geocode) (*model.TripRou
 	}
 	nearbyProducts, nearbyErr := t.r.GetNearbyAvailableProducts(
 		nearbyParams,
-		tripRoute.Distance,
+		pickup.Location,
+		*tripRoute,
 	)
 	if nearbyErr != nil {
 		return nil, nearbyErr
@@ -194,11 +217,11 @@ func (t *tripClient) googleRoutesApi(routeParams routerequest, routeResponse *ro
 	return routeResponse, nil
 }
 
-func (t *tripClient) FindAvailableCourier(pickup model.GpsInput) (*model.Courier, error) {
+func (t *tripClient) FindAvailableCourier(pickup model.GpsInput, productID uuid.UUID) (*model.Courier, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	return t.r.FindAvailableCourier(pickup)
+	return t.r.FindAvailableCourier(pickup, productID)
 }
 
 func (t *tripClient) AssignCourierToTrip(tripID, courierID uuid.UUID) error {
@@ -211,18 +234,29 @@ func (t *tripClient) AssignCourierToTrip(tripID, courierID uuid.UUID) error {
 	return t.r.AssignCourierToTrip(tripID, courierID)
 }
 
-func (t *tripClient) UnassignTrip(courierID uuid.UUID) error {
+func (t *tripClient) UnassignTrip(tripID, courierID uuid.UUID) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	return t.r.UnassignTrip(courierID)
+	return t.r.UnassignTrip(tripID, courierID)
 }
 
 func (t *tripClient) CreateTrip(args sqlStore.CreateTripParams) (*model.Trip, error) {
 	t.mu.Lock()
-	defer t.mu.Unlock()
+	trip, err := t.r.CreateTrip(args)
+	t.mu.Unlock()
+	if err != nil || !args.PromotionID.Valid {
+		return trip, err
+	}
+
+	// Redeem the promotion now so the customer never pays full price for a
+	// discounted quote
+	if err := t.createTripCost(trip.ID); err != nil {
+		t.SetTripStatus(trip.ID, model.TripStatusCancelled)
+		return nil, err
+	}
 
-	return t.r.CreateTrip(args)
+	return t.r.GetTrip(trip.ID)
 }
 
 func (t *tripClient) SetTripStatus(tripID uuid.UUID, status model.TripStatus) error {
@@ -250,40 +284,115 @@ func (t *tripClient) CreateTripRecipient(
 	return t.r.CreateTripRecipient(tripID, input)
 }
 
+// AwaitTripPayment - price trip upfront and hold matching until it is paid
+func (t *tripClient) AwaitTripPayment(tripID uuid.UUID) (*model.Trip, error) {
+	if err := t.createTripCost(tripID); err != nil {
+		return nil, err
+	}
+
+	trip, err := t.r.GetTrip(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fully discounted trips have nothing to pay
+	if trip.Cost-trip.Discount > 0 {
+		if err := t.SetTripStatus(tripID, model.TripStatusAwaitingPayment); err != nil {
+			return nil, err
+		}
+		trip.Status = model.TripStatusAwaitingPayment
+	}
+
+	return trip, nil
+}
+
 func (t *tripClient) createTripCost(tripID uuid.UUID) error {
 	trip, err := t.r.GetTrip(tripID)
 	if err != nil {
 		return err
 	}
 
-	pickup := trip.StartLocation
-	dropoff := trip.EndLocation
-	routeRes, routeErr := t.computeRoute(
-		model.Geocode{
-			Location: *pickup,
-		},
-		model.Geocode{
-			Location: *dropoff,
-		},
+	// Prepaid trips are priced before matching
+	if trip.Cost > 0 {
+		return nil
+	}
+
+	// Price at booking time so the final cost matches what was quoted
+	cost, routeRes, err := t.tripCost(
+		trip.ProductID,
+		model.Geocode{Location: *trip.StartLocation},
+		model.Geocode{Location: *trip.EndLocation},
+		*trip.CreatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := t.r.CreateTripCost(tripID, cost, *routeRes); err != nil {
+		return err
+	}
+
+	if _, err := t.promo.RedeemTripPromotion(*trip, cost); err != nil {
+		return err
+	}
+
+	if _, err := t.referral.ApplyPromoBalance(*trip, cost); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("trip cost: apply promo balance")
+	}
+
+	return nil
+}
+
+// QuoteTripCost - what the trip would cost if booked now
+func (t *tripClient) QuoteTripCost(productID uuid.UUID, pickup, dropoff model.Geocode) (int, error) {
+	cost, _, err := t.tripCost(productID, pickup, dropoff, time.Now())
+	return cost, err
+}
+
+func (t *tripClient) tripCost(
+	productID uuid.UUID,
+	pickup, dropoff model.Geocode,
+	at time.Time,
+) (int, *model.TripRoute, error) {
+	routeRes, routeErr := t.computeRoute(pickup, dropoff)
 	if routeErr != nil {
-		return routeErr
+		return 0, nil, routeErr
 	}
 
-	product, err := t.r.GetTripProduct(trip.ProductID)
+	product, err := t.r.GetTripProduct(productID)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
+	if product == nil {
+		return 0, nil, r.ErrProductNotFound
+	}
+
+	cost := t.p.CalculateTripCost(
+		product.WeightClass,
+		routeRes.Distance,
+		routeRes.Duration,
+		routeRes.StaticDuration,
+		product.Name != "UziX",
+	)
 
-	cost := t.p.CalculateTripCost(product.WeightClass, routeRes.Distance, product.Name != "UziX")
+	multiplier, err := t.r.GetPricingMultiplier(productID, pickup.Location, at)
+	if err != nil {
+		return 0, nil, err
+	}
 
-	return t.r.CreateTripCost(tripID, cost)
+	return t.p.ApplyMultiplier(cost, multiplier), routeRes, nil
 }
 
 func (t *tripClient) GetTripRecipient(tripID uuid.UUID) (*model.Recipient, error) {
 	return t.r.GetTripRecipient(tripID)
 }
 
+func (t *tripClient) GetTripPath(userID, tripID uuid.UUID, admin bool) (*model.TripPath, error) {
+	return t.r.GetTripPath(userID, tripID, admin)
+}
+
 func (t *tripClient) GetTripDetails(tripID uuid.UUID) (*model.Trip, error) {
 	trip, err := t.r.GetTrip(tripID)
 	if err != nil {
@@ -376,7 +485,7 @@ func (t *tripClient) MatchCourier(tripID uuid.UUID, pickup model.TripInput) {
 				courier, err := t.r.FindAvailableCourier(model.GpsInput{
 					Lat: pkp.Location.Lat,
 					Lng: pkp.Location.Lng,
-				})
+				}, trip.ProductID)
 				if err != nil {
 					return
 				}
@@ -410,7 +519,14 @@ func (t *tripClient) ReportTripStatus(tripID uuid.UUID, status model.TripStatus)
 		// Check courier hasn't been assigned yet
 		if trip.CourierID.String() == internal.ZERO_UUID {
 			t.publishTripUpdate(tripID, model.TripStatusCancelled, getTripStatusChannel(status))
+			t.cancelTrip(tripID)
 		}
+	case model.TripStatusCourierNotFound:
+		t.publishTripUpdate(tripID, status, getTripStatusChannel(status))
+		t.cancelTrip(tripID)
+	case model.TripStatusComplete:
+		// Completion pays out so only the courier can do it
+		return r.ErrTripCompleteNotAllowed
 	default:
 		t.publishTripUpdate(tripID, status, getTripStatusChannel(status))
 	}
@@ -418,6 +534,117 @@ func (t *tripClient) ReportTripStatus(tripID uuid.UUID, status model.TripStatus)
 	return nil
 }
 
+// CourierCompleteTrip - courier drops off the trip they are carrying.
+// Cash on delivery trips complete once the courier confirms the cash.
+func (t *tripClient) CourierCompleteTrip(courierID, tripID uuid.UUID, collected *int) error {
+	if err := t.r.CompleteCourierTrip(tripID, courierID, collected); err != nil {
+		return err
+	}
+
+	t.publishTripUpdate(tripID, model.TripStatusComplete, getTripStatusChannel(model.TripStatusComplete))
+	t.completeTrip(tripID)
+
+	return nil
+}
+
+// completeTrip - settle everything that hangs off a completed trip
+func (t *tripClient) completeTrip(tripID uuid.UUID) {
+	trip, err := t.r.GetTrip(tripID)
+	if err != nil {
+		return
+	}
+
+	if trip.CourierID != nil && *trip.CourierID != uuid.Nil {
+		if err := t.UnassignTrip(tripID, *trip.CourierID); err != nil {
+			t.log.WithFields(logrus.Fields{
+				"trip_id":    tripID,
+				"courier_id": trip.CourierID,
+			}).WithError(err).Errorf("complete trip: release courier")
+		}
+	}
+
+	if err := t.wallet.CaptureTripHold(*trip); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("complete trip: capture wallet hold")
+	}
+
+	if err := t.earnings.RecordTripEarnings(*trip); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("complete trip: record courier earnings")
+	}
+
+	if err := t.referral.RewardTripReferrals(*trip); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("complete trip: reward referrals")
+	}
+
+	if err := t.points.AwardTripPoints(tripID); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("complete trip: award courier points")
+	}
+
+	if err := t.quests.RecordTripProgress(*trip); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("complete trip: record quest progress")
+	}
+}
+
+// CourierCancelTrip - courier drops a trip before pickup. They lose points
+// and the trip goes back to matching.
+func (t *tripClient) CourierCancelTrip(courierID, tripID uuid.UUID) error {
+	trip, err := t.r.GetTrip(tripID)
+	if err != nil {
+		return err
+	}
+
+	switch trip.Status {
+	case model.TripStatusCourierFound,
+		model.TripStatusCourierAssigned,
+		model.TripStatusCourierArriving:
+	default:
+		return r.ErrTripCancelNotAllowed
+	}
+
+	if err := t.r.ReleaseCourierTrip(tripID, courierID); err != nil {
+		return err
+	}
+
+	if err := t.points.DeductCancellation(courierID, tripID); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id":    tripID,
+			"courier_id": courierID,
+		}).WithError(err).Errorf("courier cancel trip: deduct points")
+	}
+
+	t.MatchCourier(tripID, model.TripInput{
+		Location: &model.GpsInput{
+			Lat: trip.StartLocation.Lat,
+			Lng: trip.StartLocation.Lng,
+		},
+	})
+
+	return nil
+}
+
+// cancelTrip - undo booking side effects for trips that never got a courier
+func (t *tripClient) cancelTrip(tripID uuid.UUID) {
+	trip, err := t.r.GetTrip(tripID)
+	if err != nil {
+		return
+	}
+
+	if err := t.wallet.ReleaseTripHold(*trip); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("cancel trip: release wallet hold")
+	}
+}
+
 // determine communication channels
 func getTripStatusChannel(status model.TripStatus) []string {
 	switch status {
@@ -487,6 +714,34 @@ func (t *tripClient) publishTripUpdate(tripID uuid.UUID, status model.TripStatus
 	return nil
 }
 
+// NotifyCourierTip - let the trip courier know they got a tip
+func (t *tripClient) NotifyCourierTip(tripID uuid.UUID, amount int) error {
+	trip, err := t.r.GetTrip(tripID)
+	if err != nil {
+		return err
+	}
+
+	u, err := json.Marshal(model.TripUpdate{
+		ID:        trip.ID,
+		Status:    trip.Status,
+		CourierID: trip.CourierID,
+		Tip:       &amount,
+	})
+	if err != nil {
+		t.log.WithError(err).Errorf("notify courier tip: marshal trip update")
+		return err
+	}
+
+	if err := t.cache.GetRedis().Publish(context.Background(), internal.ASSIGN_TRIP_CHANNEL, u).Err(); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("notify courier tip: publish")
+		return err
+	}
+
+	return nil
+}
+
 func createRouteRequest(pickup, dropoff latlng) routerequest {
 	return routerequest{
 		origin: origin{
@@ -514,6 +769,16 @@ func createRouteRequest(pickup, dropoff latlng) routerequest {
 	}
 }
 
+// Google routes durations are formatted as seconds e.g "165s"
+func parseRouteDuration(duration string) int {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0
+	}
+
+	return int(d.Seconds())
+}
+
 func base64Key(key interface{}) string {
 	keyString, err := json.Marshal(key)
 	if err != nil {
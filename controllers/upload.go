@@ -1,30 +1,49 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"time"
+
 	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
 	r "github.com/edwinlomolo/uzi-api/repository"
 	"github.com/edwinlomolo/uzi-api/store/sqlc"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
 var (
 	upldService UploadController
 )
 
+// documentExpiryInterval - how often courier document expiry is checked
+const documentExpiryInterval = 24 * time.Hour
+
 type UploadController interface {
 	CreateCourierUpload(reason, uri string, courierID uuid.UUID) error
 	CreateUserUpload(reason, uri string, userID uuid.UUID) error
 	GetCourierUploads(courierID uuid.UUID) ([]*model.Uploads, error)
+	GetPendingCourierUploads(limit, offset int) ([]*model.Uploads, error)
+	ApproveCourierUpload(reviewerID, uploadID uuid.UUID, expiresAt *time.Time) (*model.Uploads, error)
+	RejectCourierUpload(reviewerID, uploadID uuid.UUID, reason string) (*model.Uploads, error)
+	ScheduleExpiryChecks()
 }
 
 type uploadClient struct {
-	r r.UploadRepository
+	r     r.UploadRepository
+	log   *logrus.Logger
+	cache internal.Cache
 }
 
 func NewUploadController(q *sqlc.Queries) {
 	ur := r.UploadRepository{}
 	ur.Init(q)
-	upldService = &uploadClient{ur}
+	upldService = &uploadClient{
+		ur,
+		internal.GetLogger(),
+		internal.GetCache(),
+	}
 }
 
 func GetUploadController() UploadController {
@@ -42,3 +61,84 @@ func (u *uploadClient) CreateUserUpload(reason, uri string, id uuid.UUID) error
 func (u *uploadClient) GetCourierUploads(courierID uuid.UUID) ([]*model.Uploads, error) {
 	return u.r.GetCourierUploads(courierID)
 }
+
+func (u *uploadClient) GetPendingCourierUploads(limit, offset int) ([]*model.Uploads, error) {
+	return u.r.GetPendingCourierUploads(limit, offset)
+}
+
+func (u *uploadClient) ApproveCourierUpload(
+	reviewerID, uploadID uuid.UUID,
+	expiresAt *time.Time,
+) (*model.Uploads, error) {
+	return u.reviewCourierUpload(reviewerID, uploadID, model.UploadVerificationStatusVerified, "", expiresAt)
+}
+
+func (u *uploadClient) RejectCourierUpload(
+	reviewerID, uploadID uuid.UUID,
+	reason string,
+) (*model.Uploads, error) {
+	return u.reviewCourierUpload(reviewerID, uploadID, model.UploadVerificationStatusRejected, reason, nil)
+}
+
+func (u *uploadClient) reviewCourierUpload(
+	reviewerID, uploadID uuid.UUID,
+	status model.UploadVerificationStatus,
+	reason string,
+	expiresAt *time.Time,
+) (*model.Uploads, error) {
+	upload, err := u.r.ReviewCourierUpload(reviewerID, uploadID, status, reason, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyCourier(upload)
+
+	return upload, nil
+}
+
+// ScheduleExpiryChecks - remind couriers of expiring documents and reject
+// expired ones until the process exits
+func (u *uploadClient) ScheduleExpiryChecks() {
+	ticker := time.NewTicker(documentExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		u.runExpiryChecks()
+		<-ticker.C
+	}
+}
+
+func (u *uploadClient) runExpiryChecks() {
+	now := time.Now()
+
+	if expiring, err := u.r.GetExpiringCourierUploads(now); err == nil {
+		for _, upload := range expiring {
+			u.notifyCourier(upload)
+		}
+	}
+
+	if expired, err := u.r.ExpireCourierUploads(now); err == nil {
+		for _, upload := range expired {
+			u.notifyCourier(upload)
+		}
+	}
+}
+
+// notifyCourier - let the courier know how their document review went
+func (u *uploadClient) notifyCourier(upload *model.Uploads) {
+	payload, err := json.Marshal(upload)
+	if err != nil {
+		u.log.WithError(err).Errorf("notify courier document: marshal upload")
+		return
+	}
+
+	if err := u.cache.GetRedis().Publish(
+		context.Background(),
+		internal.COURIER_DOCUMENTS_CHANNEL,
+		payload,
+	).Err(); err != nil {
+		u.log.WithFields(logrus.Fields{
+			"upload_id": upload.ID,
+		}).WithError(err).Errorf("notify courier document: publish")
+	}
+}
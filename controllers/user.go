@@ -18,6 +18,7 @@ type UserController interface {
 	FindUserByID(id uuid.UUID) (*model.User, error)
 	SignIn(signin r.SigninInput, ip, userAgent string) (*model.Session, error)
 	SoftDelete(phone string) error
+	IsAdmin(userID uuid.UUID) (bool, error)
 }
 
 type userClient struct {
@@ -61,3 +62,7 @@ func (u *userClient) OnboardUser(user r.SigninInput) (*model.User, error) {
 func (u *userClient) SoftDelete(phone string) error {
 	return nil
 }
+
+func (u *userClient) IsAdmin(userID uuid.UUID) (bool, error) {
+	return u.r.IsAdmin(userID)
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	vehicleService VehicleController
+)
+
+type VehicleController interface {
+	RegisterVehicle(courierID uuid.UUID, input model.VehicleInput) (*model.Vehicle, error)
+	SwitchVehicle(courierID, vehicleID uuid.UUID) (*model.Vehicle, error)
+	ApproveVehicle(reviewerID, vehicleID uuid.UUID) (*model.Vehicle, error)
+	RejectVehicle(reviewerID, vehicleID uuid.UUID, reason string) (*model.Vehicle, error)
+	GetVehicle(vehicleID uuid.UUID) (*model.Vehicle, error)
+	GetCourierVehicles(courierID uuid.UUID) ([]*model.Vehicle, error)
+	GetPendingVehicles(limit, offset int) ([]*model.Vehicle, error)
+}
+
+type vehicleClient struct {
+	r *r.VehicleRepository
+}
+
+func NewVehicleController(q *sqlc.Queries) {
+	vr := &r.VehicleRepository{}
+	vr.Init(q)
+	vehicleService = &vehicleClient{vr}
+}
+
+func GetVehicleController() VehicleController {
+	return vehicleService
+}
+
+func (v *vehicleClient) RegisterVehicle(courierID uuid.UUID, input model.VehicleInput) (*model.Vehicle, error) {
+	return v.r.RegisterVehicle(courierID, input)
+}
+
+func (v *vehicleClient) SwitchVehicle(courierID, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	return v.r.SwitchVehicle(courierID, vehicleID)
+}
+
+func (v *vehicleClient) ApproveVehicle(reviewerID, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	return v.r.ReviewVehicle(reviewerID, vehicleID, model.VehicleStatusApproved, "")
+}
+
+func (v *vehicleClient) RejectVehicle(reviewerID, vehicleID uuid.UUID, reason string) (*model.Vehicle, error) {
+	return v.r.ReviewVehicle(reviewerID, vehicleID, model.VehicleStatusRejected, reason)
+}
+
+func (v *vehicleClient) GetVehicle(vehicleID uuid.UUID) (*model.Vehicle, error) {
+	return v.r.GetVehicle(vehicleID)
+}
+
+func (v *vehicleClient) GetCourierVehicles(courierID uuid.UUID) ([]*model.Vehicle, error) {
+	return v.r.GetCourierVehicles(courierID)
+}
+
+func (v *vehicleClient) GetPendingVehicles(limit, offset int) ([]*model.Vehicle, error) {
+	return v.r.GetPendingVehicles(limit, offset)
+}
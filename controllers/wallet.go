@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	r "github.com/edwinlomolo/uzi-api/repository"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+var (
+	walletService WalletController
+)
+
+type WalletController interface {
+	GetWallet(userID uuid.UUID) (*model.Wallet, error)
+	GetWalletStatement(userID uuid.UUID, limit, offset int) ([]*model.WalletEntry, error)
+	CaptureTripHold(trip model.Trip) error
+	ReleaseTripHold(trip model.Trip) error
+}
+
+type walletClient struct {
+	r *r.WalletRepository
+}
+
+func NewWalletController(q *sqlc.Queries) {
+	wr := &r.WalletRepository{}
+	wr.Init(q)
+	walletService = &walletClient{wr}
+}
+
+func GetWalletController() WalletController {
+	return walletService
+}
+
+func (w *walletClient) GetWallet(userID uuid.UUID) (*model.Wallet, error) {
+	return w.r.GetWallet(userID)
+}
+
+func (w *walletClient) GetWalletStatement(
+	userID uuid.UUID,
+	limit, offset int,
+) ([]*model.WalletEntry, error) {
+	return w.r.GetWalletStatement(userID, limit, offset)
+}
+
+func (w *walletClient) CaptureTripHold(trip model.Trip) error {
+	return w.r.CaptureTripHold(trip)
+}
+
+func (w *walletClient) ReleaseTripHold(trip model.Trip) error {
+	return w.r.ReleaseTripHold(trip)
+}
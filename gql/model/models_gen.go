@@ -11,6 +11,18 @@ import (
 	"github.com/google/uuid"
 )
 
+type ApplyPromoCodeInput struct {
+	Code      string    `json:"code"`
+	ProductID uuid.UUID `json:"productId"`
+	Pickup    *GpsInput `json:"pickup"`
+	Price     int       `json:"price"`
+}
+
+type CardPaymentInput struct {
+	TripID uuid.UUID  `json:"tripId"`
+	CardID *uuid.UUID `json:"cardId,omitempty"`
+}
+
 type Courier struct {
 	ID             uuid.UUID     `json:"id"`
 	UserID         uuid.UUID     `json:"user_id"`
@@ -24,13 +36,89 @@ type Courier struct {
 	Trip           *Trip         `json:"trip,omitempty"`
 	ProductID      uuid.UUID     `json:"product_id"`
 	Product        *Product      `json:"product"`
+	VehicleID      *uuid.UUID    `json:"vehicle_id,omitempty"`
+	Vehicle        *Vehicle      `json:"vehicle,omitempty"`
 	CompletedTrips int           `json:"completedTrips"`
 	Points         int           `json:"points"`
+	Tier           CourierTier   `json:"tier"`
 	UploadID       *uuid.UUID    `json:"upload_id,omitempty"`
 	CreatedAt      *time.Time    `json:"created_at,omitempty"`
 	UpdatedAt      *time.Time    `json:"updated_at,omitempty"`
 }
 
+type CourierCashBalance struct {
+	CourierID uuid.UUID  `json:"courier_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Name      string     `json:"name"`
+	Phone     string     `json:"phone"`
+	CashHeld  int        `json:"cash_held"`
+	Earnings  int        `json:"earnings"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type CourierEarnings struct {
+	Period  EarningsPeriod   `json:"period"`
+	Start   time.Time        `json:"start"`
+	End     time.Time        `json:"end"`
+	Balance int              `json:"balance"`
+	Total   *EarningsTotal   `json:"total"`
+	Daily   []*EarningsTotal `json:"daily"`
+	Weekly  []*EarningsTotal `json:"weekly"`
+	Trips   []*TripEarnings  `json:"trips"`
+}
+
+type CourierEarningsAdjustmentInput struct {
+	CourierID uuid.UUID          `json:"courierId"`
+	Kind      EarningsAdjustment `json:"kind"`
+	Amount    int                `json:"amount"`
+	TripID    *uuid.UUID         `json:"tripId,omitempty"`
+	Reason    string             `json:"reason"`
+}
+
+type CourierOnboarding struct {
+	CourierID      uuid.UUID         `json:"courier_id"`
+	Status         CourierStatus     `json:"status"`
+	Verified       bool              `json:"verified"`
+	ProductID      *uuid.UUID        `json:"product_id,omitempty"`
+	Steps          []*OnboardingStep `json:"steps"`
+	CompletedSteps int               `json:"completed_steps"`
+	TotalSteps     int               `json:"total_steps"`
+	Products       []*Product        `json:"products"`
+}
+
+type CourierPerformance struct {
+	CourierID        uuid.UUID             `json:"courier_id"`
+	Points           int                   `json:"points"`
+	Tier             CourierTier           `json:"tier"`
+	NextTier         *CourierTier          `json:"next_tier,omitempty"`
+	PointsToNextTier int                   `json:"points_to_next_tier"`
+	TierBonusPercent int                   `json:"tier_bonus_percent"`
+	Rating           *float64              `json:"rating,omitempty"`
+	CompletedTrips   int                   `json:"completed_trips"`
+	Recent           []*CourierPointsEntry `json:"recent"`
+}
+
+type CourierPointsEntry struct {
+	ID        uuid.UUID    `json:"id"`
+	TripID    *uuid.UUID   `json:"trip_id,omitempty"`
+	Reason    PointsReason `json:"reason"`
+	Points    int          `json:"points"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+}
+
+type CourierQuest struct {
+	Quest       *Quest     `json:"quest"`
+	Trips       int        `json:"trips"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type CourierStatusUpdate struct {
+	Status  CourierStatus          `json:"status"`
+	Updated bool                   `json:"updated"`
+	Reasons []CourierIneligibility `json:"reasons"`
+}
+
 type CourierUploadInput struct {
 	Type UploadFile `json:"type"`
 	URI  string     `json:"uri"`
@@ -41,6 +129,59 @@ type CreateTripInput struct {
 	TripProductID   string              `json:"tripProductId"`
 	Recipient       *TripRecipientInput `json:"recipient"`
 	ConfirmedPickup *TripInput          `json:"confirmedPickup"`
+	PromoCode       *string             `json:"promoCode,omitempty"`
+	PayWithWallet   *bool               `json:"payWithWallet,omitempty"`
+	CollectAmount   *int                `json:"collectAmount,omitempty"`
+}
+
+type EarningsTotal struct {
+	Start       time.Time `json:"start"`
+	Fares       int       `json:"fares"`
+	Commission  int       `json:"commission"`
+	FleetShare  int       `json:"fleet_share"`
+	FleetIncome int       `json:"fleet_income"`
+	Tips        int       `json:"tips"`
+	Bonuses     int       `json:"bonuses"`
+	Penalties   int       `json:"penalties"`
+	Net         int       `json:"net"`
+	Payouts     int       `json:"payouts"`
+	CashSettled int       `json:"cash_settled"`
+}
+
+type Fleet struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	OwnerID    uuid.UUID  `json:"owner_id"`
+	RiderShare int        `json:"rider_share"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+type FleetInput struct {
+	Name       string    `json:"name"`
+	OwnerID    uuid.UUID `json:"ownerId"`
+	RiderShare int       `json:"riderShare"`
+}
+
+type FleetInvitation struct {
+	ID         uuid.UUID             `json:"id"`
+	FleetID    uuid.UUID             `json:"fleet_id"`
+	FleetName  string                `json:"fleet_name"`
+	RiderShare int                   `json:"rider_share"`
+	Status     FleetInvitationStatus `json:"status"`
+	CreatedAt  *time.Time            `json:"created_at,omitempty"`
+}
+
+type FleetRider struct {
+	CourierID  uuid.UUID     `json:"courier_id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Name       string        `json:"name"`
+	Phone      string        `json:"phone"`
+	Status     CourierStatus `json:"status"`
+	Location   *Gps          `json:"location,omitempty"`
+	LastSeenAt *time.Time    `json:"last_seen_at,omitempty"`
+	TripID     *uuid.UUID    `json:"trip_id,omitempty"`
+	VehicleID  *uuid.UUID    `json:"vehicle_id,omitempty"`
 }
 
 type Gps struct {
@@ -49,13 +190,78 @@ type Gps struct {
 }
 
 type GpsInput struct {
-	Lat float64 `json:"lat"`
-	Lng float64 `json:"lng"`
+	Lat      float64  `json:"lat"`
+	Lng      float64  `json:"lng"`
+	Accuracy *float64 `json:"accuracy,omitempty"`
+	Speed    *float64 `json:"speed,omitempty"`
+	Heading  *float64 `json:"heading,omitempty"`
+}
+
+type GpsPointInput struct {
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Accuracy   *float64  `json:"accuracy,omitempty"`
+	Speed      *float64  `json:"speed,omitempty"`
+	Heading    *float64  `json:"heading,omitempty"`
+}
+
+type MpesaPaymentInput struct {
+	TripID uuid.UUID `json:"tripId"`
+	Phone  *string   `json:"phone,omitempty"`
 }
 
 type Mutation struct {
 }
 
+type OnboardingStep struct {
+	Type            UploadFile           `json:"type"`
+	Status          OnboardingStepStatus `json:"status"`
+	Upload          *Uploads             `json:"upload,omitempty"`
+	RejectionReason *string              `json:"rejection_reason,omitempty"`
+}
+
+type Payment struct {
+	ID               uuid.UUID       `json:"id"`
+	TripID           *uuid.UUID      `json:"trip_id,omitempty"`
+	Provider         PaymentProvider `json:"provider"`
+	Purpose          PaymentPurpose  `json:"purpose"`
+	Amount           int             `json:"amount"`
+	Phone            *string         `json:"phone,omitempty"`
+	Status           PaymentStatus   `json:"status"`
+	Receipt          *string         `json:"receipt,omitempty"`
+	ResultDesc       *string         `json:"result_desc,omitempty"`
+	Reference        *string         `json:"reference,omitempty"`
+	AuthorizationURL *string         `json:"authorization_url,omitempty"`
+	RefundedAmount   int             `json:"refunded_amount"`
+	CreatedAt        *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time      `json:"updated_at,omitempty"`
+}
+
+type PaymentCard struct {
+	ID        uuid.UUID  `json:"id"`
+	CardType  string     `json:"card_type"`
+	Last4     string     `json:"last4"`
+	ExpMonth  string     `json:"exp_month"`
+	ExpYear   string     `json:"exp_year"`
+	Bank      *string    `json:"bank,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type Payout struct {
+	ID         uuid.UUID    `json:"id"`
+	CourierID  *uuid.UUID   `json:"courier_id,omitempty"`
+	UserID     uuid.UUID    `json:"user_id"`
+	Amount     int          `json:"amount"`
+	Phone      string       `json:"phone"`
+	Status     PayoutStatus `json:"status"`
+	Attempts   int          `json:"attempts"`
+	Receipt    *string      `json:"receipt,omitempty"`
+	ResultDesc *string      `json:"result_desc,omitempty"`
+	CreatedAt  *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time   `json:"updated_at,omitempty"`
+}
+
 type Place struct {
 	ID            string `json:"id"`
 	MainText      string `json:"mainText"`
@@ -73,9 +279,43 @@ type Product struct {
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 }
 
+type PromoQuote struct {
+	PromotionID uuid.UUID      `json:"promotion_id"`
+	Code        string         `json:"code"`
+	Description string         `json:"description"`
+	Discount    int            `json:"discount"`
+	Price       int            `json:"price"`
+	FundedBy    DiscountFunder `json:"funded_by"`
+}
+
 type Query struct {
 }
 
+type Quest struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	TargetTrips int          `json:"target_trips"`
+	Reward      int          `json:"reward"`
+	ZoneID      *uuid.UUID   `json:"zone_id,omitempty"`
+	ProductID   *uuid.UUID   `json:"product_id,omitempty"`
+	Tier        *CourierTier `json:"tier,omitempty"`
+	StartsAt    time.Time    `json:"starts_at"`
+	EndsAt      time.Time    `json:"ends_at"`
+}
+
+type QuestInput struct {
+	Name        string       `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	TargetTrips int          `json:"targetTrips"`
+	Reward      int          `json:"reward"`
+	ZoneID      *uuid.UUID   `json:"zoneId,omitempty"`
+	ProductID   *uuid.UUID   `json:"productId,omitempty"`
+	Tier        *CourierTier `json:"tier,omitempty"`
+	StartsAt    time.Time    `json:"startsAt"`
+	EndsAt      time.Time    `json:"endsAt"`
+}
+
 type Recipient struct {
 	ID           uuid.UUID  `json:"id"`
 	Name         string     `json:"name"`
@@ -107,27 +347,79 @@ type Session struct {
 	IsCourier     bool           `json:"isCourier"`
 	Onboarding    bool           `json:"onboarding"`
 	Phone         string         `json:"phone"`
+	ReferralCode  *string        `json:"referralCode,omitempty"`
 	CourierStatus *CourierStatus `json:"courierStatus,omitempty"`
 }
 
+type Shift struct {
+	ID        uuid.UUID `json:"id"`
+	ZoneID    uuid.UUID `json:"zone_id"`
+	ZoneName  string    `json:"zone_name"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int       `json:"capacity"`
+	Booked    int       `json:"booked"`
+	Guarantee int       `json:"guarantee"`
+}
+
+type ShiftBooking struct {
+	ID            uuid.UUID          `json:"id"`
+	CourierID     uuid.UUID          `json:"courier_id"`
+	Shift         *Shift             `json:"shift"`
+	Status        ShiftBookingStatus `json:"status"`
+	CheckedInAt   *time.Time         `json:"checked_in_at,omitempty"`
+	Earned        int                `json:"earned"`
+	GuaranteePaid int                `json:"guarantee_paid"`
+	CreatedAt     *time.Time         `json:"created_at,omitempty"`
+}
+
+type ShiftInput struct {
+	ZoneID    uuid.UUID `json:"zoneId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Capacity  int       `json:"capacity"`
+	Guarantee *int      `json:"guarantee,omitempty"`
+}
+
 type Subscription struct {
 }
 
 type Trip struct {
-	ID              uuid.UUID  `json:"id"`
-	CourierID       *uuid.UUID `json:"courier_id,omitempty"`
-	Courier         *Courier   `json:"courier,omitempty"`
-	UserID          uuid.UUID  `json:"user_id"`
-	StartLocation   *Gps       `json:"start_location,omitempty"`
-	EndLocation     *Gps       `json:"end_location,omitempty"`
-	ConfirmedPickup *Gps       `json:"confirmed_pickup,omitempty"`
-	Status          TripStatus `json:"status"`
-	ProductID       uuid.UUID  `json:"product_id"`
-	Cost            int        `json:"cost"`
-	Route           *TripRoute `json:"route,omitempty"`
-	Recipient       *Recipient `json:"recipient"`
-	CreatedAt       *time.Time `json:"created_at,omitempty"`
-	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+	ID               uuid.UUID       `json:"id"`
+	CourierID        *uuid.UUID      `json:"courier_id,omitempty"`
+	Courier          *Courier        `json:"courier,omitempty"`
+	UserID           uuid.UUID       `json:"user_id"`
+	StartLocation    *Gps            `json:"start_location,omitempty"`
+	EndLocation      *Gps            `json:"end_location,omitempty"`
+	ConfirmedPickup  *Gps            `json:"confirmed_pickup,omitempty"`
+	Status           TripStatus      `json:"status"`
+	ProductID        uuid.UUID       `json:"product_id"`
+	Cost             int             `json:"cost"`
+	Distance         int             `json:"distance"`
+	ActualDistance   *int            `json:"actual_distance,omitempty"`
+	Duration         int             `json:"duration"`
+	PromotionID      *uuid.UUID      `json:"promotion_id,omitempty"`
+	Discount         int             `json:"discount"`
+	DiscountFundedBy *DiscountFunder `json:"discount_funded_by,omitempty"`
+	CollectAmount    int             `json:"collect_amount"`
+	CollectedAmount  *int            `json:"collected_amount,omitempty"`
+	Route            *TripRoute      `json:"route,omitempty"`
+	Recipient        *Recipient      `json:"recipient"`
+	CreatedAt        *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time      `json:"updated_at,omitempty"`
+}
+
+type TripEarnings struct {
+	TripID      uuid.UUID  `json:"trip_id"`
+	Fare        int        `json:"fare"`
+	Commission  int        `json:"commission"`
+	FleetShare  int        `json:"fleet_share"`
+	FleetIncome int        `json:"fleet_income"`
+	Tips        int        `json:"tips"`
+	Bonuses     int        `json:"bonuses"`
+	Penalties   int        `json:"penalties"`
+	Net         int        `json:"net"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
 
 type TripInput struct {
@@ -136,6 +428,31 @@ type TripInput struct {
 	Location         *GpsInput `json:"location"`
 }
 
+type TripPath struct {
+	TripID   uuid.UUID `json:"trip_id"`
+	Points   int       `json:"points"`
+	Distance int       `json:"distance"`
+	Polyline string    `json:"polyline"`
+	Geojson  string    `json:"geojson"`
+}
+
+type TripRating struct {
+	ID        uuid.UUID    `json:"id"`
+	TripID    uuid.UUID    `json:"trip_id"`
+	Target    RatingTarget `json:"target"`
+	Rating    int          `json:"rating"`
+	Tags      []string     `json:"tags"`
+	Comment   *string      `json:"comment,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+}
+
+type TripRatingInput struct {
+	TripID  uuid.UUID `json:"tripId"`
+	Rating  int       `json:"rating"`
+	Tags    []string  `json:"tags,omitempty"`
+	Comment *string   `json:"comment,omitempty"`
+}
+
 type TripRecipientInput struct {
 	Name         string  `json:"name"`
 	BuildingName *string `json:"building_name,omitempty"`
@@ -147,6 +464,8 @@ type TripRecipientInput struct {
 type TripRoute struct {
 	Polyline          string     `json:"polyline"`
 	Distance          int        `json:"distance"`
+	Duration          int        `json:"duration"`
+	StaticDuration    int        `json:"staticDuration"`
 	AvailableProducts []*Product `json:"availableProducts"`
 }
 
@@ -160,28 +479,136 @@ type TripUpdate struct {
 	Status    TripStatus `json:"status"`
 	CourierID *uuid.UUID `json:"courierId,omitempty"`
 	Location  *Gps       `json:"location,omitempty"`
+	Tip       *int       `json:"tip,omitempty"`
 }
 
 type Uploads struct {
-	ID           uuid.UUID                `json:"ID"`
-	Type         string                   `json:"type"`
-	URI          string                   `json:"uri"`
-	Verification UploadVerificationStatus `json:"verification"`
-	CourierID    *uuid.UUID               `json:"courier_id,omitempty"`
-	UserID       *uuid.UUID               `json:"user_id,omitempty"`
-	CreatedAt    *time.Time               `json:"created_at,omitempty"`
-	UpdatedAt    *time.Time               `json:"updated_at,omitempty"`
+	ID              uuid.UUID                `json:"ID"`
+	Type            string                   `json:"type"`
+	URI             string                   `json:"uri"`
+	Verification    UploadVerificationStatus `json:"verification"`
+	RejectionReason *string                  `json:"rejection_reason,omitempty"`
+	ExpiresAt       *time.Time               `json:"expires_at,omitempty"`
+	CourierID       *uuid.UUID               `json:"courier_id,omitempty"`
+	UserID          *uuid.UUID               `json:"user_id,omitempty"`
+	CreatedAt       *time.Time               `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time               `json:"updated_at,omitempty"`
 }
 
 type User struct {
-	ID        uuid.UUID  `json:"id"`
-	FirstName string     `json:"first_name"`
-	LastName  string     `json:"last_name"`
-	Phone     string     `json:"phone"`
-	CourierID *uuid.UUID `json:"courier_id,omitempty"`
-	Courier   *Courier   `json:"courier,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID           uuid.UUID  `json:"id"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	Phone        string     `json:"phone"`
+	ReferralCode *string    `json:"referral_code,omitempty"`
+	PromoBalance int        `json:"promo_balance"`
+	Rating       *float64   `json:"rating,omitempty"`
+	CourierID    *uuid.UUID `json:"courier_id,omitempty"`
+	Courier      *Courier   `json:"courier,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type Vehicle struct {
+	ID              uuid.UUID     `json:"id"`
+	CourierID       uuid.UUID     `json:"courier_id"`
+	ProductID       uuid.UUID     `json:"product_id"`
+	Type            VehicleType   `json:"type"`
+	Plate           string        `json:"plate"`
+	Make            string        `json:"make"`
+	Colour          string        `json:"colour"`
+	Capacity        int           `json:"capacity"`
+	Status          VehicleStatus `json:"status"`
+	RejectionReason *string       `json:"rejection_reason,omitempty"`
+	Active          bool          `json:"active"`
+	FleetID         *uuid.UUID    `json:"fleet_id,omitempty"`
+	FleetApprovedAt *time.Time    `json:"fleet_approved_at,omitempty"`
+	CreatedAt       *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time    `json:"updated_at,omitempty"`
+}
+
+type VehicleInput struct {
+	Type      VehicleType `json:"type"`
+	Plate     string      `json:"plate"`
+	Make      string      `json:"make"`
+	Colour    string      `json:"colour"`
+	Capacity  int         `json:"capacity"`
+	ProductID uuid.UUID   `json:"productId"`
+}
+
+type Wallet struct {
+	Balance      int `json:"balance"`
+	Held         int `json:"held"`
+	PromoBalance int `json:"promo_balance"`
+	Earnings     int `json:"earnings"`
+	CashHeld     int `json:"cash_held"`
+}
+
+type WalletEntry struct {
+	ID          uuid.UUID             `json:"id"`
+	Account     WalletAccount         `json:"account"`
+	Kind        LedgerTransactionKind `json:"kind"`
+	Amount      int                   `json:"amount"`
+	Balance     int                   `json:"balance"`
+	Description string                `json:"description"`
+	TripID      *uuid.UUID            `json:"trip_id,omitempty"`
+	CreatedAt   *time.Time            `json:"created_at,omitempty"`
+}
+
+type WalletTopUpInput struct {
+	Amount   int             `json:"amount"`
+	Provider PaymentProvider `json:"provider"`
+	Phone    *string         `json:"phone,omitempty"`
+	CardID   *uuid.UUID      `json:"cardId,omitempty"`
+}
+
+type CourierIneligibility string
+
+const (
+	CourierIneligibilityNotVerified        CourierIneligibility = "NOT_VERIFIED"
+	CourierIneligibilityNoVehicle          CourierIneligibility = "NO_VEHICLE"
+	CourierIneligibilityVehicleNotApproved CourierIneligibility = "VEHICLE_NOT_APPROVED"
+	CourierIneligibilityDocumentsExpired   CourierIneligibility = "DOCUMENTS_EXPIRED"
+	CourierIneligibilityNoLocation         CourierIneligibility = "NO_LOCATION"
+	CourierIneligibilityStaleLocation      CourierIneligibility = "STALE_LOCATION"
+)
+
+var AllCourierIneligibility = []CourierIneligibility{
+	CourierIneligibilityNotVerified,
+	CourierIneligibilityNoVehicle,
+	CourierIneligibilityVehicleNotApproved,
+	CourierIneligibilityDocumentsExpired,
+	CourierIneligibilityNoLocation,
+	CourierIneligibilityStaleLocation,
+}
+
+func (e CourierIneligibility) IsValid() bool {
+	switch e {
+	case CourierIneligibilityNotVerified, CourierIneligibilityNoVehicle, CourierIneligibilityVehicleNotApproved, CourierIneligibilityDocumentsExpired, CourierIneligibilityNoLocation, CourierIneligibilityStaleLocation:
+		return true
+	}
+	return false
+}
+
+func (e CourierIneligibility) String() string {
+	return string(e)
+}
+
+func (e *CourierIneligibility) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourierIneligibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourierIneligibility", str)
+	}
+	return nil
+}
+
+func (e CourierIneligibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
 type CourierStatus string
@@ -190,17 +617,21 @@ const (
 	CourierStatusOffline    CourierStatus = "OFFLINE"
 	CourierStatusOnline     CourierStatus = "ONLINE"
 	CourierStatusOnboarding CourierStatus = "ONBOARDING"
+	CourierStatusOnBreak    CourierStatus = "ON_BREAK"
+	CourierStatusBusy       CourierStatus = "BUSY"
 )
 
 var AllCourierStatus = []CourierStatus{
 	CourierStatusOffline,
 	CourierStatusOnline,
 	CourierStatusOnboarding,
+	CourierStatusOnBreak,
+	CourierStatusBusy,
 }
 
 func (e CourierStatus) IsValid() bool {
 	switch e {
-	case CourierStatusOffline, CourierStatusOnline, CourierStatusOnboarding:
+	case CourierStatusOffline, CourierStatusOnline, CourierStatusOnboarding, CourierStatusOnBreak, CourierStatusBusy:
 		return true
 	}
 	return false
@@ -227,147 +658,977 @@ func (e CourierStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type TripStatus string
+type CourierTier string
 
 const (
-	TripStatusCourierEnRoute  TripStatus = "COURIER_EN_ROUTE"
-	TripStatusCancelled       TripStatus = "CANCELLED"
-	TripStatusComplete        TripStatus = "COMPLETE"
-	TripStatusCourierAssigned TripStatus = "COURIER_ASSIGNED"
-	TripStatusCourierArriving TripStatus = "COURIER_ARRIVING"
-	TripStatusCourierFound    TripStatus = "COURIER_FOUND"
-	TripStatusCourierNotFound TripStatus = "COURIER_NOT_FOUND"
+	CourierTierBronze CourierTier = "BRONZE"
+	CourierTierSilver CourierTier = "SILVER"
+	CourierTierGold   CourierTier = "GOLD"
 )
 
-var AllTripStatus = []TripStatus{
-	TripStatusCourierEnRoute,
-	TripStatusCancelled,
-	TripStatusComplete,
-	TripStatusCourierAssigned,
-	TripStatusCourierArriving,
-	TripStatusCourierFound,
-	TripStatusCourierNotFound,
+var AllCourierTier = []CourierTier{
+	CourierTierBronze,
+	CourierTierSilver,
+	CourierTierGold,
 }
 
-func (e TripStatus) IsValid() bool {
+func (e CourierTier) IsValid() bool {
 	switch e {
-	case TripStatusCourierEnRoute, TripStatusCancelled, TripStatusComplete, TripStatusCourierAssigned, TripStatusCourierArriving, TripStatusCourierFound, TripStatusCourierNotFound:
+	case CourierTierBronze, CourierTierSilver, CourierTierGold:
 		return true
 	}
 	return false
 }
 
-func (e TripStatus) String() string {
+func (e CourierTier) String() string {
 	return string(e)
 }
 
-func (e *TripStatus) UnmarshalGQL(v interface{}) error {
+func (e *CourierTier) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = TripStatus(str)
+	*e = CourierTier(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid TripStatus", str)
+		return fmt.Errorf("%s is not a valid CourierTier", str)
 	}
 	return nil
 }
 
-func (e TripStatus) MarshalGQL(w io.Writer) {
+func (e CourierTier) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type UploadFile string
+type DiscountFunder string
 
 const (
-	UploadFileDp  UploadFile = "DP"
-	UploadFileMcr UploadFile = "MCR"
-	UploadFileID  UploadFile = "ID"
-	UploadFilePc  UploadFile = "PC"
-	UploadFileLb  UploadFile = "LB"
-	UploadFileVi  UploadFile = "VI"
+	DiscountFunderPlatform DiscountFunder = "PLATFORM"
+	DiscountFunderPartner  DiscountFunder = "PARTNER"
 )
 
-var AllUploadFile = []UploadFile{
-	UploadFileDp,
-	UploadFileMcr,
-	UploadFileID,
-	UploadFilePc,
-	UploadFileLb,
-	UploadFileVi,
+var AllDiscountFunder = []DiscountFunder{
+	DiscountFunderPlatform,
+	DiscountFunderPartner,
 }
 
-func (e UploadFile) IsValid() bool {
+func (e DiscountFunder) IsValid() bool {
 	switch e {
-	case UploadFileDp, UploadFileMcr, UploadFileID, UploadFilePc, UploadFileLb, UploadFileVi:
+	case DiscountFunderPlatform, DiscountFunderPartner:
 		return true
 	}
 	return false
 }
 
-func (e UploadFile) String() string {
+func (e DiscountFunder) String() string {
 	return string(e)
 }
 
-func (e *UploadFile) UnmarshalGQL(v interface{}) error {
+func (e *DiscountFunder) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = UploadFile(str)
+	*e = DiscountFunder(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid UploadFile", str)
+		return fmt.Errorf("%s is not a valid DiscountFunder", str)
 	}
 	return nil
 }
 
-func (e UploadFile) MarshalGQL(w io.Writer) {
+func (e DiscountFunder) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type UploadVerificationStatus string
+type DiscountType string
 
 const (
-	UploadVerificationStatusVerifying  UploadVerificationStatus = "VERIFYING"
-	UploadVerificationStatusVerified   UploadVerificationStatus = "VERIFIED"
-	UploadVerificationStatusRejected   UploadVerificationStatus = "REJECTED"
-	UploadVerificationStatusOnboarding UploadVerificationStatus = "ONBOARDING"
+	DiscountTypePercent DiscountType = "PERCENT"
+	DiscountTypeFixed   DiscountType = "FIXED"
 )
 
-var AllUploadVerificationStatus = []UploadVerificationStatus{
-	UploadVerificationStatusVerifying,
-	UploadVerificationStatusVerified,
-	UploadVerificationStatusRejected,
-	UploadVerificationStatusOnboarding,
+var AllDiscountType = []DiscountType{
+	DiscountTypePercent,
+	DiscountTypeFixed,
 }
 
-func (e UploadVerificationStatus) IsValid() bool {
+func (e DiscountType) IsValid() bool {
 	switch e {
-	case UploadVerificationStatusVerifying, UploadVerificationStatusVerified, UploadVerificationStatusRejected, UploadVerificationStatusOnboarding:
+	case DiscountTypePercent, DiscountTypeFixed:
 		return true
 	}
 	return false
 }
 
-func (e UploadVerificationStatus) String() string {
+func (e DiscountType) String() string {
 	return string(e)
 }
 
-func (e *UploadVerificationStatus) UnmarshalGQL(v interface{}) error {
+func (e *DiscountType) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = UploadVerificationStatus(str)
+	*e = DiscountType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid UploadVerificationStatus", str)
+		return fmt.Errorf("%s is not a valid DiscountType", str)
 	}
 	return nil
 }
 
-func (e UploadVerificationStatus) MarshalGQL(w io.Writer) {
+func (e DiscountType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EarningsAdjustment string
+
+const (
+	EarningsAdjustmentBonus   EarningsAdjustment = "BONUS"
+	EarningsAdjustmentPenalty EarningsAdjustment = "PENALTY"
+)
+
+var AllEarningsAdjustment = []EarningsAdjustment{
+	EarningsAdjustmentBonus,
+	EarningsAdjustmentPenalty,
+}
+
+func (e EarningsAdjustment) IsValid() bool {
+	switch e {
+	case EarningsAdjustmentBonus, EarningsAdjustmentPenalty:
+		return true
+	}
+	return false
+}
+
+func (e EarningsAdjustment) String() string {
+	return string(e)
+}
+
+func (e *EarningsAdjustment) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EarningsAdjustment(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EarningsAdjustment", str)
+	}
+	return nil
+}
+
+func (e EarningsAdjustment) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EarningsPeriod string
+
+const (
+	EarningsPeriodDay   EarningsPeriod = "DAY"
+	EarningsPeriodWeek  EarningsPeriod = "WEEK"
+	EarningsPeriodMonth EarningsPeriod = "MONTH"
+)
+
+var AllEarningsPeriod = []EarningsPeriod{
+	EarningsPeriodDay,
+	EarningsPeriodWeek,
+	EarningsPeriodMonth,
+}
+
+func (e EarningsPeriod) IsValid() bool {
+	switch e {
+	case EarningsPeriodDay, EarningsPeriodWeek, EarningsPeriodMonth:
+		return true
+	}
+	return false
+}
+
+func (e EarningsPeriod) String() string {
+	return string(e)
+}
+
+func (e *EarningsPeriod) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EarningsPeriod(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EarningsPeriod", str)
+	}
+	return nil
+}
+
+func (e EarningsPeriod) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FleetInvitationStatus string
+
+const (
+	FleetInvitationStatusPending  FleetInvitationStatus = "PENDING"
+	FleetInvitationStatusAccepted FleetInvitationStatus = "ACCEPTED"
+	FleetInvitationStatusDeclined FleetInvitationStatus = "DECLINED"
+)
+
+var AllFleetInvitationStatus = []FleetInvitationStatus{
+	FleetInvitationStatusPending,
+	FleetInvitationStatusAccepted,
+	FleetInvitationStatusDeclined,
+}
+
+func (e FleetInvitationStatus) IsValid() bool {
+	switch e {
+	case FleetInvitationStatusPending, FleetInvitationStatusAccepted, FleetInvitationStatusDeclined:
+		return true
+	}
+	return false
+}
+
+func (e FleetInvitationStatus) String() string {
+	return string(e)
+}
+
+func (e *FleetInvitationStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FleetInvitationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FleetInvitationStatus", str)
+	}
+	return nil
+}
+
+func (e FleetInvitationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type LedgerTransactionKind string
+
+const (
+	LedgerTransactionKindTopup          LedgerTransactionKind = "TOPUP"
+	LedgerTransactionKindTripCharge     LedgerTransactionKind = "TRIP_CHARGE"
+	LedgerTransactionKindRefund         LedgerTransactionKind = "REFUND"
+	LedgerTransactionKindPromoCredit    LedgerTransactionKind = "PROMO_CREDIT"
+	LedgerTransactionKindReferralReward LedgerTransactionKind = "REFERRAL_REWARD"
+	LedgerTransactionKindHold           LedgerTransactionKind = "HOLD"
+	LedgerTransactionKindHoldRelease    LedgerTransactionKind = "HOLD_RELEASE"
+	LedgerTransactionKindTripFare       LedgerTransactionKind = "TRIP_FARE"
+	LedgerTransactionKindCommission     LedgerTransactionKind = "COMMISSION"
+	LedgerTransactionKindFleetShare     LedgerTransactionKind = "FLEET_SHARE"
+	LedgerTransactionKindTip            LedgerTransactionKind = "TIP"
+	LedgerTransactionKindBonus          LedgerTransactionKind = "BONUS"
+	LedgerTransactionKindPenalty        LedgerTransactionKind = "PENALTY"
+	LedgerTransactionKindPayout         LedgerTransactionKind = "PAYOUT"
+	LedgerTransactionKindPayoutReversal LedgerTransactionKind = "PAYOUT_REVERSAL"
+	LedgerTransactionKindCodCollected   LedgerTransactionKind = "COD_COLLECTED"
+	LedgerTransactionKindCodSettlement  LedgerTransactionKind = "COD_SETTLEMENT"
+	LedgerTransactionKindCodRemittance  LedgerTransactionKind = "COD_REMITTANCE"
+)
+
+var AllLedgerTransactionKind = []LedgerTransactionKind{
+	LedgerTransactionKindTopup,
+	LedgerTransactionKindTripCharge,
+	LedgerTransactionKindRefund,
+	LedgerTransactionKindPromoCredit,
+	LedgerTransactionKindReferralReward,
+	LedgerTransactionKindHold,
+	LedgerTransactionKindHoldRelease,
+	LedgerTransactionKindTripFare,
+	LedgerTransactionKindCommission,
+	LedgerTransactionKindFleetShare,
+	LedgerTransactionKindTip,
+	LedgerTransactionKindBonus,
+	LedgerTransactionKindPenalty,
+	LedgerTransactionKindPayout,
+	LedgerTransactionKindPayoutReversal,
+	LedgerTransactionKindCodCollected,
+	LedgerTransactionKindCodSettlement,
+	LedgerTransactionKindCodRemittance,
+}
+
+func (e LedgerTransactionKind) IsValid() bool {
+	switch e {
+	case LedgerTransactionKindTopup, LedgerTransactionKindTripCharge, LedgerTransactionKindRefund, LedgerTransactionKindPromoCredit, LedgerTransactionKindReferralReward, LedgerTransactionKindHold, LedgerTransactionKindHoldRelease, LedgerTransactionKindTripFare, LedgerTransactionKindCommission, LedgerTransactionKindFleetShare, LedgerTransactionKindTip, LedgerTransactionKindBonus, LedgerTransactionKindPenalty, LedgerTransactionKindPayout, LedgerTransactionKindPayoutReversal, LedgerTransactionKindCodCollected, LedgerTransactionKindCodSettlement, LedgerTransactionKindCodRemittance:
+		return true
+	}
+	return false
+}
+
+func (e LedgerTransactionKind) String() string {
+	return string(e)
+}
+
+func (e *LedgerTransactionKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LedgerTransactionKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LedgerTransactionKind", str)
+	}
+	return nil
+}
+
+func (e LedgerTransactionKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OnboardingStepStatus string
+
+const (
+	OnboardingStepStatusMissing   OnboardingStepStatus = "MISSING"
+	OnboardingStepStatusVerifying OnboardingStepStatus = "VERIFYING"
+	OnboardingStepStatusVerified  OnboardingStepStatus = "VERIFIED"
+	OnboardingStepStatusRejected  OnboardingStepStatus = "REJECTED"
+)
+
+var AllOnboardingStepStatus = []OnboardingStepStatus{
+	OnboardingStepStatusMissing,
+	OnboardingStepStatusVerifying,
+	OnboardingStepStatusVerified,
+	OnboardingStepStatusRejected,
+}
+
+func (e OnboardingStepStatus) IsValid() bool {
+	switch e {
+	case OnboardingStepStatusMissing, OnboardingStepStatusVerifying, OnboardingStepStatusVerified, OnboardingStepStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e OnboardingStepStatus) String() string {
+	return string(e)
+}
+
+func (e *OnboardingStepStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OnboardingStepStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OnboardingStepStatus", str)
+	}
+	return nil
+}
+
+func (e OnboardingStepStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PaymentProvider string
+
+const (
+	PaymentProviderMpesa    PaymentProvider = "MPESA"
+	PaymentProviderPaystack PaymentProvider = "PAYSTACK"
+	PaymentProviderWallet   PaymentProvider = "WALLET"
+)
+
+var AllPaymentProvider = []PaymentProvider{
+	PaymentProviderMpesa,
+	PaymentProviderPaystack,
+	PaymentProviderWallet,
+}
+
+func (e PaymentProvider) IsValid() bool {
+	switch e {
+	case PaymentProviderMpesa, PaymentProviderPaystack, PaymentProviderWallet:
+		return true
+	}
+	return false
+}
+
+func (e PaymentProvider) String() string {
+	return string(e)
+}
+
+func (e *PaymentProvider) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentProvider(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentProvider", str)
+	}
+	return nil
+}
+
+func (e PaymentProvider) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PaymentPurpose string
+
+const (
+	PaymentPurposeTrip       PaymentPurpose = "TRIP"
+	PaymentPurposeTopup      PaymentPurpose = "TOPUP"
+	PaymentPurposeRemittance PaymentPurpose = "REMITTANCE"
+	PaymentPurposeTip        PaymentPurpose = "TIP"
+)
+
+var AllPaymentPurpose = []PaymentPurpose{
+	PaymentPurposeTrip,
+	PaymentPurposeTopup,
+	PaymentPurposeRemittance,
+	PaymentPurposeTip,
+}
+
+func (e PaymentPurpose) IsValid() bool {
+	switch e {
+	case PaymentPurposeTrip, PaymentPurposeTopup, PaymentPurposeRemittance, PaymentPurposeTip:
+		return true
+	}
+	return false
+}
+
+func (e PaymentPurpose) String() string {
+	return string(e)
+}
+
+func (e *PaymentPurpose) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentPurpose(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentPurpose", str)
+	}
+	return nil
+}
+
+func (e PaymentPurpose) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "PENDING"
+	PaymentStatusPaid      PaymentStatus = "PAID"
+	PaymentStatusFailed    PaymentStatus = "FAILED"
+	PaymentStatusCancelled PaymentStatus = "CANCELLED"
+	PaymentStatusRefunding PaymentStatus = "REFUNDING"
+	PaymentStatusRefunded  PaymentStatus = "REFUNDED"
+)
+
+var AllPaymentStatus = []PaymentStatus{
+	PaymentStatusPending,
+	PaymentStatusPaid,
+	PaymentStatusFailed,
+	PaymentStatusCancelled,
+	PaymentStatusRefunding,
+	PaymentStatusRefunded,
+}
+
+func (e PaymentStatus) IsValid() bool {
+	switch e {
+	case PaymentStatusPending, PaymentStatusPaid, PaymentStatusFailed, PaymentStatusCancelled, PaymentStatusRefunding, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (e PaymentStatus) String() string {
+	return string(e)
+}
+
+func (e *PaymentStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentStatus", str)
+	}
+	return nil
+}
+
+func (e PaymentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending    PayoutStatus = "PENDING"
+	PayoutStatusProcessing PayoutStatus = "PROCESSING"
+	PayoutStatusVerifying  PayoutStatus = "VERIFYING"
+	PayoutStatusPaid       PayoutStatus = "PAID"
+	PayoutStatusFailed     PayoutStatus = "FAILED"
+)
+
+var AllPayoutStatus = []PayoutStatus{
+	PayoutStatusPending,
+	PayoutStatusProcessing,
+	PayoutStatusVerifying,
+	PayoutStatusPaid,
+	PayoutStatusFailed,
+}
+
+func (e PayoutStatus) IsValid() bool {
+	switch e {
+	case PayoutStatusPending, PayoutStatusProcessing, PayoutStatusVerifying, PayoutStatusPaid, PayoutStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e PayoutStatus) String() string {
+	return string(e)
+}
+
+func (e *PayoutStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PayoutStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PayoutStatus", str)
+	}
+	return nil
+}
+
+func (e PayoutStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PointsReason string
+
+const (
+	PointsReasonTripCompleted  PointsReason = "TRIP_COMPLETED"
+	PointsReasonOnTimeDelivery PointsReason = "ON_TIME_DELIVERY"
+	PointsReasonHighRating     PointsReason = "HIGH_RATING"
+	PointsReasonLowRating      PointsReason = "LOW_RATING"
+	PointsReasonCancellation   PointsReason = "CANCELLATION"
+	PointsReasonLateArrival    PointsReason = "LATE_ARRIVAL"
+)
+
+var AllPointsReason = []PointsReason{
+	PointsReasonTripCompleted,
+	PointsReasonOnTimeDelivery,
+	PointsReasonHighRating,
+	PointsReasonLowRating,
+	PointsReasonCancellation,
+	PointsReasonLateArrival,
+}
+
+func (e PointsReason) IsValid() bool {
+	switch e {
+	case PointsReasonTripCompleted, PointsReasonOnTimeDelivery, PointsReasonHighRating, PointsReasonLowRating, PointsReasonCancellation, PointsReasonLateArrival:
+		return true
+	}
+	return false
+}
+
+func (e PointsReason) String() string {
+	return string(e)
+}
+
+func (e *PointsReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PointsReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PointsReason", str)
+	}
+	return nil
+}
+
+func (e PointsReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RatingTarget string
+
+const (
+	RatingTargetCourier RatingTarget = "COURIER"
+	RatingTargetSender  RatingTarget = "SENDER"
+)
+
+var AllRatingTarget = []RatingTarget{
+	RatingTargetCourier,
+	RatingTargetSender,
+}
+
+func (e RatingTarget) IsValid() bool {
+	switch e {
+	case RatingTargetCourier, RatingTargetSender:
+		return true
+	}
+	return false
+}
+
+func (e RatingTarget) String() string {
+	return string(e)
+}
+
+func (e *RatingTarget) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RatingTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RatingTarget", str)
+	}
+	return nil
+}
+
+func (e RatingTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ShiftBookingStatus string
+
+const (
+	ShiftBookingStatusBooked    ShiftBookingStatus = "BOOKED"
+	ShiftBookingStatusCancelled ShiftBookingStatus = "CANCELLED"
+	ShiftBookingStatusCheckedIn ShiftBookingStatus = "CHECKED_IN"
+	ShiftBookingStatusCompleted ShiftBookingStatus = "COMPLETED"
+	ShiftBookingStatusNoShow    ShiftBookingStatus = "NO_SHOW"
+)
+
+var AllShiftBookingStatus = []ShiftBookingStatus{
+	ShiftBookingStatusBooked,
+	ShiftBookingStatusCancelled,
+	ShiftBookingStatusCheckedIn,
+	ShiftBookingStatusCompleted,
+	ShiftBookingStatusNoShow,
+}
+
+func (e ShiftBookingStatus) IsValid() bool {
+	switch e {
+	case ShiftBookingStatusBooked, ShiftBookingStatusCancelled, ShiftBookingStatusCheckedIn, ShiftBookingStatusCompleted, ShiftBookingStatusNoShow:
+		return true
+	}
+	return false
+}
+
+func (e ShiftBookingStatus) String() string {
+	return string(e)
+}
+
+func (e *ShiftBookingStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ShiftBookingStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ShiftBookingStatus", str)
+	}
+	return nil
+}
+
+func (e ShiftBookingStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TripStatus string
+
+const (
+	TripStatusCourierEnRoute  TripStatus = "COURIER_EN_ROUTE"
+	TripStatusCancelled       TripStatus = "CANCELLED"
+	TripStatusComplete        TripStatus = "COMPLETE"
+	TripStatusCourierAssigned TripStatus = "COURIER_ASSIGNED"
+	TripStatusCourierArriving TripStatus = "COURIER_ARRIVING"
+	TripStatusCourierFound    TripStatus = "COURIER_FOUND"
+	TripStatusCourierNotFound TripStatus = "COURIER_NOT_FOUND"
+	TripStatusAwaitingPayment TripStatus = "AWAITING_PAYMENT"
+)
+
+var AllTripStatus = []TripStatus{
+	TripStatusCourierEnRoute,
+	TripStatusCancelled,
+	TripStatusComplete,
+	TripStatusCourierAssigned,
+	TripStatusCourierArriving,
+	TripStatusCourierFound,
+	TripStatusCourierNotFound,
+	TripStatusAwaitingPayment,
+}
+
+func (e TripStatus) IsValid() bool {
+	switch e {
+	case TripStatusCourierEnRoute, TripStatusCancelled, TripStatusComplete, TripStatusCourierAssigned, TripStatusCourierArriving, TripStatusCourierFound, TripStatusCourierNotFound, TripStatusAwaitingPayment:
+		return true
+	}
+	return false
+}
+
+func (e TripStatus) String() string {
+	return string(e)
+}
+
+func (e *TripStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TripStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TripStatus", str)
+	}
+	return nil
+}
+
+func (e TripStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type UploadFile string
+
+const (
+	UploadFileDp  UploadFile = "DP"
+	UploadFileMcr UploadFile = "MCR"
+	UploadFileID  UploadFile = "ID"
+	UploadFilePc  UploadFile = "PC"
+	UploadFileLb  UploadFile = "LB"
+	UploadFileVi  UploadFile = "VI"
+)
+
+var AllUploadFile = []UploadFile{
+	UploadFileDp,
+	UploadFileMcr,
+	UploadFileID,
+	UploadFilePc,
+	UploadFileLb,
+	UploadFileVi,
+}
+
+func (e UploadFile) IsValid() bool {
+	switch e {
+	case UploadFileDp, UploadFileMcr, UploadFileID, UploadFilePc, UploadFileLb, UploadFileVi:
+		return true
+	}
+	return false
+}
+
+func (e UploadFile) String() string {
+	return string(e)
+}
+
+func (e *UploadFile) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UploadFile(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UploadFile", str)
+	}
+	return nil
+}
+
+func (e UploadFile) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type UploadVerificationStatus string
+
+const (
+	UploadVerificationStatusVerifying  UploadVerificationStatus = "VERIFYING"
+	UploadVerificationStatusVerified   UploadVerificationStatus = "VERIFIED"
+	UploadVerificationStatusRejected   UploadVerificationStatus = "REJECTED"
+	UploadVerificationStatusOnboarding UploadVerificationStatus = "ONBOARDING"
+)
+
+var AllUploadVerificationStatus = []UploadVerificationStatus{
+	UploadVerificationStatusVerifying,
+	UploadVerificationStatusVerified,
+	UploadVerificationStatusRejected,
+	UploadVerificationStatusOnboarding,
+}
+
+func (e UploadVerificationStatus) IsValid() bool {
+	switch e {
+	case UploadVerificationStatusVerifying, UploadVerificationStatusVerified, UploadVerificationStatusRejected, UploadVerificationStatusOnboarding:
+		return true
+	}
+	return false
+}
+
+func (e UploadVerificationStatus) String() string {
+	return string(e)
+}
+
+func (e *UploadVerificationStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UploadVerificationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UploadVerificationStatus", str)
+	}
+	return nil
+}
+
+func (e UploadVerificationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type VehicleStatus string
+
+const (
+	VehicleStatusPending  VehicleStatus = "PENDING"
+	VehicleStatusApproved VehicleStatus = "APPROVED"
+	VehicleStatusRejected VehicleStatus = "REJECTED"
+)
+
+var AllVehicleStatus = []VehicleStatus{
+	VehicleStatusPending,
+	VehicleStatusApproved,
+	VehicleStatusRejected,
+}
+
+func (e VehicleStatus) IsValid() bool {
+	switch e {
+	case VehicleStatusPending, VehicleStatusApproved, VehicleStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e VehicleStatus) String() string {
+	return string(e)
+}
+
+func (e *VehicleStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = VehicleStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid VehicleStatus", str)
+	}
+	return nil
+}
+
+func (e VehicleStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type VehicleType string
+
+const (
+	VehicleTypeBicycle   VehicleType = "BICYCLE"
+	VehicleTypeMotorbike VehicleType = "MOTORBIKE"
+	VehicleTypeCar       VehicleType = "CAR"
+	VehicleTypeVan       VehicleType = "VAN"
+	VehicleTypeTruck     VehicleType = "TRUCK"
+)
+
+var AllVehicleType = []VehicleType{
+	VehicleTypeBicycle,
+	VehicleTypeMotorbike,
+	VehicleTypeCar,
+	VehicleTypeVan,
+	VehicleTypeTruck,
+}
+
+func (e VehicleType) IsValid() bool {
+	switch e {
+	case VehicleTypeBicycle, VehicleTypeMotorbike, VehicleTypeCar, VehicleTypeVan, VehicleTypeTruck:
+		return true
+	}
+	return false
+}
+
+func (e VehicleType) String() string {
+	return string(e)
+}
+
+func (e *VehicleType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = VehicleType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid VehicleType", str)
+	}
+	return nil
+}
+
+func (e VehicleType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WalletAccount string
+
+const (
+	WalletAccountCash     WalletAccount = "CASH"
+	WalletAccountHold     WalletAccount = "HOLD"
+	WalletAccountPromo    WalletAccount = "PROMO"
+	WalletAccountEarnings WalletAccount = "EARNINGS"
+	WalletAccountCashHeld WalletAccount = "CASH_HELD"
+)
+
+var AllWalletAccount = []WalletAccount{
+	WalletAccountCash,
+	WalletAccountHold,
+	WalletAccountPromo,
+	WalletAccountEarnings,
+	WalletAccountCashHeld,
+}
+
+func (e WalletAccount) IsValid() bool {
+	switch e {
+	case WalletAccountCash, WalletAccountHold, WalletAccountPromo, WalletAccountEarnings, WalletAccountCashHeld:
+		return true
+	}
+	return false
+}
+
+func (e WalletAccount) String() string {
+	return string(e)
+}
+
+func (e *WalletAccount) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WalletAccount(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WalletAccount", str)
+	}
+	return nil
+}
+
+func (e WalletAccount) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
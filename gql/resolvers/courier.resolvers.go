@@ -26,6 +26,15 @@ func (r *courierResolver) Product(ctx context.Context, obj *model.Courier) (*mod
 	return r.GetCourierProduct(obj.ProductID)
 }
 
+// Vehicle is the resolver for the vehicle field.
+func (r *courierResolver) Vehicle(ctx context.Context, obj *model.Courier) (*model.Vehicle, error) {
+	if obj.VehicleID == nil {
+		return nil, nil
+	}
+
+	return r.vehicleController.GetVehicle(*obj.VehicleID)
+}
+
 // Courier returns gql.CourierResolver implementation.
 func (r *Resolver) Courier() gql.CourierResolver { return &courierResolver{r} }
 
@@ -20,9 +20,22 @@ type Resolver struct {
 	controllers.UploadController
 	controllers.CourierController
 	internal.LocationController
-	tripController controllers.TripController
-	userController controllers.UserController
-	redisClient    *redis.Client
+	tripController       controllers.TripController
+	userController       controllers.UserController
+	promotionController  controllers.PromotionController
+	paymentController    controllers.PaymentController
+	walletController     controllers.WalletController
+	earningsController   controllers.EarningsController
+	payoutController     controllers.PayoutController
+	cashController       controllers.CashController
+	ratingController     controllers.RatingController
+	pointsController     controllers.PointsController
+	questController      controllers.QuestController
+	onboardingController controllers.OnboardingController
+	vehicleController    controllers.VehicleController
+	shiftController      controllers.ShiftController
+	fleetController      controllers.FleetController
+	redisClient          *redis.Client
 }
 
 func New(q *sqlc.Queries) gql.Config {
@@ -30,7 +43,21 @@ func New(q *sqlc.Queries) gql.Config {
 	controllers.NewUserController(q)
 	controllers.NewUploadController(q)
 	controllers.NewCourierController(q)
+	controllers.NewPromotionController(q)
+	controllers.NewReferralController(q)
+	controllers.NewWalletController(q)
+	controllers.NewEarningsController(q)
+	controllers.NewPointsController(q)
+	controllers.NewQuestController(q)
 	controllers.NewTripController(q)
+	controllers.NewPaymentController(q)
+	controllers.NewPayoutController(q)
+	controllers.NewCashController(q)
+	controllers.NewRatingController(q)
+	controllers.NewOnboardingController(q)
+	controllers.NewVehicleController(q)
+	controllers.NewShiftController(q)
+	controllers.NewFleetController(q)
 	internal.NewLocationController()
 
 	c := gql.Config{Resolvers: &Resolver{
@@ -39,6 +66,19 @@ func New(q *sqlc.Queries) gql.Config {
 		internal.GetLocationController(),
 		controllers.GetTripController(),
 		controllers.GetUserController(),
+		controllers.GetPromotionController(),
+		controllers.GetPaymentController(),
+		controllers.GetWalletController(),
+		controllers.GetEarningsController(),
+		controllers.GetPayoutController(),
+		controllers.GetCashController(),
+		controllers.GetRatingController(),
+		controllers.GetPointsController(),
+		controllers.GetQuestController(),
+		controllers.GetOnboardingController(),
+		controllers.GetVehicleController(),
+		controllers.GetShiftController(),
+		controllers.GetFleetController(),
 		internal.GetCache().GetRedis(),
 	}}
 
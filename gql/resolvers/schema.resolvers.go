@@ -8,7 +8,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/controllers"
 	"github.com/edwinlomolo/uzi-api/gql"
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
@@ -39,12 +42,22 @@ func (r *mutationResolver) TrackCourierGps(ctx context.Context, input model.GpsI
 	return true, nil
 }
 
+// TrackCourierGpsBatch is the resolver for the trackCourierGpsBatch field.
+func (r *mutationResolver) TrackCourierGpsBatch(ctx context.Context, points []*model.GpsPointInput) (bool, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	if err := r.TrackCourierLocations(userID, points); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // SetCourierStatus is the resolver for the setCourierStatus field.
-func (r *mutationResolver) SetCourierStatus(ctx context.Context, status string) (bool, error) {
+func (r *mutationResolver) SetCourierStatus(ctx context.Context, status model.CourierStatus) (*model.CourierStatusUpdate, error) {
 	userID := stringToUUID(ctx.Value("userID").(string))
 
-	s := model.CourierStatus(status)
-	return r.UpdateCourierStatus(userID, s)
+	return r.UpdateCourierStatus(userID, status)
 }
 
 // CreateTrip is the resolver for the createTrip field.
@@ -89,7 +102,35 @@ func (r *mutationResolver) CreateTrip(ctx context.Context, input model.CreateTri
 		dropoff.Location.Lat,
 	)
 
+	if input.PromoCode != nil && len(*input.PromoCode) > 0 {
+		cost, costErr := r.tripController.QuoteTripCost(params.ProductID, *pickup, *dropoff)
+		if costErr != nil {
+			return nil, costErr
+		}
+
+		promo, promoErr := r.promotionController.ApplyPromoCode(userID, model.ApplyPromoCodeInput{
+			Code:      *input.PromoCode,
+			ProductID: params.ProductID,
+			Pickup: &model.GpsInput{
+				Lat: pickup.Location.Lat,
+				Lng: pickup.Location.Lng,
+			},
+			Price: cost,
+		})
+		if promoErr != nil {
+			return nil, promoErr
+		}
+		params.PromotionID = uuid.NullUUID{UUID: promo.PromotionID, Valid: true}
+	}
+
+	if input.CollectAmount != nil {
+		params.CollectAmount = int32(*input.CollectAmount)
+	}
+
 	trip, err := r.tripController.CreateTrip(params)
+	if err != nil {
+		return nil, err
+	}
 
 	go func() {
 		err := r.tripController.CreateTripRecipient(trip.ID, *input.Recipient)
@@ -98,13 +139,75 @@ func (r *mutationResolver) CreateTrip(ctx context.Context, input model.CreateTri
 		}
 	}()
 
+	// Hold matching until the trip is paid for
+	payWithWallet := input.PayWithWallet != nil && *input.PayWithWallet
+	if config.Config.Payment.RequireTripPayment || payWithWallet {
+		trip, err = r.tripController.AwaitTripPayment(trip.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if trip.Status == model.TripStatusAwaitingPayment {
+			if !payWithWallet {
+				return trip, nil
+			}
+
+			// Wallet payment starts matching once the hold is placed
+			if _, err := r.paymentController.PayTripWithWallet(userID, trip.ID); err != nil {
+				r.tripController.SetTripStatus(trip.ID, model.TripStatusCancelled)
+				return nil, err
+			}
+
+			return trip, nil
+		}
+	}
+
 	r.tripController.MatchCourier(trip.ID, *input.TripInput.Pickup)
 
-	return trip, err
+	return trip, nil
 }
 
 // ReportTripStatus is the resolver for the reportTripStatus field.
-func (r *mutationResolver) ReportTripStatus(ctx context.Context, tripID uuid.UUID, status model.TripStatus) (bool, error) {
+func (r *mutationResolver) ReportTripStatus(ctx context.Context, tripID uuid.UUID, status model.TripStatus, collectedAmount *int) (bool, error) {
+	// Assigned courier dropping the trip
+	if status == model.TripStatusCancelled {
+		userID := stringToUUID(ctx.Value("userID").(string))
+		courier, err := r.GetCourierByUserID(userID)
+		if err != nil {
+			return false, err
+		}
+
+		trip, err := r.tripController.GetTripDetails(tripID)
+		if err != nil {
+			return false, err
+		}
+
+		if courier != nil && trip.CourierID != nil && *trip.CourierID == courier.ID {
+			if err := r.tripController.CourierCancelTrip(courier.ID, tripID); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	// Assigned courier dropping off the trip
+	if status == model.TripStatusComplete {
+		userID := stringToUUID(ctx.Value("userID").(string))
+		courier, err := r.GetCourierByUserID(userID)
+		if err != nil {
+			return false, err
+		}
+		if courier == nil {
+			return false, ErrCourierRequired
+		}
+
+		if err := r.tripController.CourierCompleteTrip(courier.ID, tripID, collectedAmount); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
 	err := r.tripController.ReportTripStatus(tripID, status)
 	if err != nil {
 		return false, err
@@ -113,6 +216,330 @@ func (r *mutationResolver) ReportTripStatus(ctx context.Context, tripID uuid.UUI
 	return true, nil
 }
 
+// ApplyPromoCode is the resolver for the applyPromoCode field.
+func (r *mutationResolver) ApplyPromoCode(ctx context.Context, input model.ApplyPromoCodeInput) (*model.PromoQuote, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.promotionController.ApplyPromoCode(userID, input)
+}
+
+// PayTripWithMpesa is the resolver for the payTripWithMpesa field.
+func (r *mutationResolver) PayTripWithMpesa(ctx context.Context, input model.MpesaPaymentInput) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.PayTripWithMpesa(userID, input)
+}
+
+// PayTripWithCard is the resolver for the payTripWithCard field.
+func (r *mutationResolver) PayTripWithCard(ctx context.Context, input model.CardPaymentInput) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.PayTripWithCard(userID, input)
+}
+
+// RefundTripPayment is the resolver for the refundTripPayment field.
+func (r *mutationResolver) RefundTripPayment(ctx context.Context, tripID uuid.UUID) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.RefundTripPayment(userID, tripID)
+}
+
+// PayTripWithWallet is the resolver for the payTripWithWallet field.
+func (r *mutationResolver) PayTripWithWallet(ctx context.Context, tripID uuid.UUID) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.PayTripWithWallet(userID, tripID)
+}
+
+// TopUpWallet is the resolver for the topUpWallet field.
+func (r *mutationResolver) TopUpWallet(ctx context.Context, input model.WalletTopUpInput) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.TopUpWallet(userID, input)
+}
+
+// AdjustCourierEarnings is the resolver for the adjustCourierEarnings field.
+func (r *mutationResolver) AdjustCourierEarnings(ctx context.Context, input model.CourierEarningsAdjustmentInput) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	if err := r.earningsController.AdjustCourierEarnings(input); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemitCodCash is the resolver for the remitCodCash field.
+func (r *mutationResolver) RemitCodCash(ctx context.Context, phone *string) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	p := ""
+	if phone != nil {
+		p = *phone
+	}
+
+	return r.paymentController.RemitCodCash(userID, p)
+}
+
+// TipCourier is the resolver for the tipCourier field.
+func (r *mutationResolver) TipCourier(ctx context.Context, tripID uuid.UUID, amount int) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.TipCourier(userID, tripID, amount)
+}
+
+// RateTrip is the resolver for the rateTrip field.
+func (r *mutationResolver) RateTrip(ctx context.Context, input model.TripRatingInput) (*model.TripRating, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.ratingController.RateTrip(userID, input)
+}
+
+// CreateQuest is the resolver for the createQuest field.
+func (r *mutationResolver) CreateQuest(ctx context.Context, input model.QuestInput) (*model.Quest, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.questController.CreateQuest(input)
+}
+
+// ApproveCourierDocument is the resolver for the approveCourierDocument field.
+func (r *mutationResolver) ApproveCourierDocument(ctx context.Context, uploadID uuid.UUID, expiresAt *time.Time) (*model.Uploads, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	reviewerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.ApproveCourierUpload(reviewerID, uploadID, expiresAt)
+}
+
+// RejectCourierDocument is the resolver for the rejectCourierDocument field.
+func (r *mutationResolver) RejectCourierDocument(ctx context.Context, uploadID uuid.UUID, reason string) (*model.Uploads, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	reviewerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.RejectCourierUpload(reviewerID, uploadID, reason)
+}
+
+// RegisterVehicle is the resolver for the registerVehicle field.
+func (r *mutationResolver) RegisterVehicle(ctx context.Context, input model.VehicleInput) (*model.Vehicle, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.vehicleController.RegisterVehicle(courier.ID, input)
+}
+
+// SwitchVehicle is the resolver for the switchVehicle field.
+func (r *mutationResolver) SwitchVehicle(ctx context.Context, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.vehicleController.SwitchVehicle(courier.ID, vehicleID)
+}
+
+// ApproveVehicle is the resolver for the approveVehicle field.
+func (r *mutationResolver) ApproveVehicle(ctx context.Context, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	reviewerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.vehicleController.ApproveVehicle(reviewerID, vehicleID)
+}
+
+// RejectVehicle is the resolver for the rejectVehicle field.
+func (r *mutationResolver) RejectVehicle(ctx context.Context, vehicleID uuid.UUID, reason string) (*model.Vehicle, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	reviewerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.vehicleController.RejectVehicle(reviewerID, vehicleID, reason)
+}
+
+// CreateShift is the resolver for the createShift field.
+func (r *mutationResolver) CreateShift(ctx context.Context, input model.ShiftInput) (*model.Shift, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.shiftController.CreateShift(input)
+}
+
+// BookShift is the resolver for the bookShift field.
+func (r *mutationResolver) BookShift(ctx context.Context, shiftID uuid.UUID) (*model.ShiftBooking, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.shiftController.BookShift(courier.ID, shiftID)
+}
+
+// CancelShiftBooking is the resolver for the cancelShiftBooking field.
+func (r *mutationResolver) CancelShiftBooking(ctx context.Context, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.shiftController.CancelShiftBooking(courier.ID, bookingID)
+}
+
+// CheckInShift is the resolver for the checkInShift field.
+func (r *mutationResolver) CheckInShift(ctx context.Context, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.shiftController.CheckInShift(courier.ID, bookingID)
+}
+
+// CreateFleet is the resolver for the createFleet field.
+func (r *mutationResolver) CreateFleet(ctx context.Context, input model.FleetInput) (*model.Fleet, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.fleetController.CreateFleet(input)
+}
+
+// SetFleetRiderShare is the resolver for the setFleetRiderShare field.
+func (r *mutationResolver) SetFleetRiderShare(ctx context.Context, fleetID uuid.UUID, riderShare int) (*model.Fleet, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.fleetController.SetFleetRiderShare(fleetID, riderShare)
+}
+
+// AddFleetManager is the resolver for the addFleetManager field.
+func (r *mutationResolver) AddFleetManager(ctx context.Context, fleetID uuid.UUID, userID uuid.UUID) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	if err := r.fleetController.AddFleetManager(fleetID, userID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// InviteFleetCourier is the resolver for the inviteFleetCourier field.
+func (r *mutationResolver) InviteFleetCourier(ctx context.Context, fleetID uuid.UUID, phone string) (bool, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return false, err
+	}
+
+	managerID := stringToUUID(ctx.Value("userID").(string))
+	if err := r.fleetController.InviteFleetCourier(managerID, fleetID, phone); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AcceptFleetInvitation is the resolver for the acceptFleetInvitation field.
+func (r *mutationResolver) AcceptFleetInvitation(ctx context.Context, invitationID uuid.UUID) (bool, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+	if err := r.fleetController.AcceptFleetInvitation(userID, invitationID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeclineFleetInvitation is the resolver for the declineFleetInvitation field.
+func (r *mutationResolver) DeclineFleetInvitation(ctx context.Context, invitationID uuid.UUID) (bool, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+	if err := r.fleetController.DeclineFleetInvitation(userID, invitationID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemoveFleetCourier is the resolver for the removeFleetCourier field.
+func (r *mutationResolver) RemoveFleetCourier(ctx context.Context, fleetID uuid.UUID, courierID uuid.UUID) (bool, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return false, err
+	}
+
+	if err := r.fleetController.RemoveFleetCourier(fleetID, courierID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ApproveFleetVehicle is the resolver for the approveFleetVehicle field.
+func (r *mutationResolver) ApproveFleetVehicle(ctx context.Context, fleetID uuid.UUID, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return nil, err
+	}
+	managerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.fleetController.ApproveFleetVehicle(managerID, fleetID, vehicleID)
+}
+
+// RejectFleetVehicle is the resolver for the rejectFleetVehicle field.
+func (r *mutationResolver) RejectFleetVehicle(ctx context.Context, fleetID uuid.UUID, vehicleID uuid.UUID, reason string) (*model.Vehicle, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return nil, err
+	}
+	managerID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.fleetController.RejectFleetVehicle(managerID, fleetID, vehicleID, reason)
+}
+
 // Hello is the resolver for the hello field.
 func (r *queryResolver) Hello(ctx context.Context) (string, error) {
 	return "Hello, world!", nil
@@ -155,6 +582,288 @@ func (r *queryResolver) GetTripDetails(ctx context.Context, tripID uuid.UUID) (*
 	return r.tripController.GetTripDetails(tripID)
 }
 
+// GetTripPayment is the resolver for the getTripPayment field.
+func (r *queryResolver) GetTripPayment(ctx context.Context, tripID uuid.UUID) (*model.Payment, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	isAdmin, err := r.userController.IsAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.paymentController.GetTripPayment(userID, tripID, isAdmin)
+}
+
+// GetPaymentCards is the resolver for the getPaymentCards field.
+func (r *queryResolver) GetPaymentCards(ctx context.Context) ([]*model.PaymentCard, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.paymentController.GetPaymentCards(userID)
+}
+
+// GetWallet is the resolver for the getWallet field.
+func (r *queryResolver) GetWallet(ctx context.Context) (*model.Wallet, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.walletController.GetWallet(userID)
+}
+
+// GetWalletStatement is the resolver for the getWalletStatement field.
+func (r *queryResolver) GetWalletStatement(ctx context.Context, limit *int, offset *int) ([]*model.WalletEntry, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.walletController.GetWalletStatement(userID, l, o)
+}
+
+// CourierEarnings is the resolver for the courierEarnings field.
+func (r *queryResolver) CourierEarnings(ctx context.Context, period model.EarningsPeriod) (*model.CourierEarnings, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	// Fleet owners who don't ride see the fleet share they earn
+	if courier == nil {
+		owner, err := r.fleetController.OwnsFleet(userID)
+		if err != nil {
+			return nil, err
+		}
+		if !owner {
+			return nil, controllers.ErrNoCourierErr
+		}
+	}
+
+	return r.earningsController.GetCourierEarnings(userID, period)
+}
+
+// GetFailedPayouts is the resolver for the getFailedPayouts field.
+func (r *queryResolver) GetFailedPayouts(ctx context.Context, limit *int, offset *int) ([]*model.Payout, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.payoutController.GetFailedPayouts(l, o)
+}
+
+// GetCouriersHoldingCash is the resolver for the getCouriersHoldingCash field.
+func (r *queryResolver) GetCouriersHoldingCash(ctx context.Context, minimum *int) ([]*model.CourierCashBalance, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	m := config.Config.Payment.CodCashLimit
+	if minimum != nil {
+		m = *minimum
+	}
+
+	return r.cashController.GetCouriersHoldingCash(m)
+}
+
+// GetTripRatings is the resolver for the getTripRatings field.
+func (r *queryResolver) GetTripRatings(ctx context.Context, tripID uuid.UUID) ([]*model.TripRating, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.ratingController.GetTripRatings(userID, tripID)
+}
+
+// GetTripPath is the resolver for the getTripPath field.
+func (r *queryResolver) GetTripPath(ctx context.Context, tripID uuid.UUID) (*model.TripPath, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	isAdmin, err := r.userController.IsAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.tripController.GetTripPath(userID, tripID, isAdmin)
+}
+
+// CourierPerformance is the resolver for the courierPerformance field.
+func (r *queryResolver) CourierPerformance(ctx context.Context) (*model.CourierPerformance, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.pointsController.GetCourierPerformance(courier.ID)
+}
+
+// CourierQuests is the resolver for the courierQuests field.
+func (r *queryResolver) CourierQuests(ctx context.Context) ([]*model.CourierQuest, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.questController.GetCourierQuests(courier.ID)
+}
+
+// GetPendingCourierDocuments is the resolver for the getPendingCourierDocuments field.
+func (r *queryResolver) GetPendingCourierDocuments(ctx context.Context, limit *int, offset *int) ([]*model.Uploads, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.GetPendingCourierUploads(l, o)
+}
+
+// CourierOnboarding is the resolver for the courierOnboarding field.
+func (r *queryResolver) CourierOnboarding(ctx context.Context) (*model.CourierOnboarding, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.onboardingController.GetCourierOnboarding(courier.ID)
+}
+
+// GetCourierVehicles is the resolver for the getCourierVehicles field.
+func (r *queryResolver) GetCourierVehicles(ctx context.Context) ([]*model.Vehicle, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	return r.vehicleController.GetCourierVehicles(courier.ID)
+}
+
+// GetPendingVehicles is the resolver for the getPendingVehicles field.
+func (r *queryResolver) GetPendingVehicles(ctx context.Context, limit *int, offset *int) ([]*model.Vehicle, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.vehicleController.GetPendingVehicles(l, o)
+}
+
+// AvailableShifts is the resolver for the availableShifts field.
+func (r *queryResolver) AvailableShifts(ctx context.Context, zoneID *uuid.UUID) ([]*model.Shift, error) {
+	return r.shiftController.GetUpcomingShifts(zoneID)
+}
+
+// CourierShifts is the resolver for the courierShifts field.
+func (r *queryResolver) CourierShifts(ctx context.Context, limit *int, offset *int) ([]*model.ShiftBooking, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	courier, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if courier == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.shiftController.GetCourierShiftBookings(courier.ID, l, o)
+}
+
+// MyFleets is the resolver for the myFleets field.
+func (r *queryResolver) MyFleets(ctx context.Context) ([]*model.Fleet, error) {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	return r.fleetController.GetManagedFleets(userID)
+}
+
+// FleetRiders is the resolver for the fleetRiders field.
+func (r *queryResolver) FleetRiders(ctx context.Context, fleetID uuid.UUID) ([]*model.FleetRider, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return nil, err
+	}
+
+	return r.fleetController.GetFleetRiders(fleetID)
+}
+
+// FleetTrips is the resolver for the fleetTrips field.
+func (r *queryResolver) FleetTrips(ctx context.Context, fleetID uuid.UUID, limit *int, offset *int) ([]*model.Trip, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return nil, err
+	}
+
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	return r.fleetController.GetFleetTrips(fleetID, l, o)
+}
+
+// FleetPendingVehicles is the resolver for the fleetPendingVehicles field.
+func (r *queryResolver) FleetPendingVehicles(ctx context.Context, fleetID uuid.UUID) ([]*model.Vehicle, error) {
+	if err := requireFleetManager(ctx, fleetID); err != nil {
+		return nil, err
+	}
+
+	return r.fleetController.GetFleetPendingVehicles(fleetID)
+}
+
+// FleetInvitations is the resolver for the fleetInvitations field.
+func (r *queryResolver) FleetInvitations(ctx context.Context) ([]*model.FleetInvitation, error) {
+	return r.fleetController.GetFleetInvitations(stringToUUID(ctx.Value("userID").(string)))
+}
+
 // TripUpdates is the resolver for the tripUpdates field.
 func (r *subscriptionResolver) TripUpdates(ctx context.Context, tripID uuid.UUID) (<-chan *model.TripUpdate, error) {
 	pubsub := r.redisClient.Subscribe(context.Background(), internal.TRIP_UPDATES_CHANNEL)
@@ -204,6 +913,36 @@ func (r *subscriptionResolver) AssignTrip(ctx context.Context, userID uuid.UUID)
 	return ch, nil
 }
 
+// CourierDocumentUpdates is the resolver for the courierDocumentUpdates field.
+func (r *subscriptionResolver) CourierDocumentUpdates(ctx context.Context, userID uuid.UUID) (<-chan *model.Uploads, error) {
+	c, err := r.GetCourierByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, controllers.ErrNoCourierErr
+	}
+
+	pubsub := r.redisClient.Subscribe(context.Background(), internal.COURIER_DOCUMENTS_CHANNEL)
+
+	ch := make(chan *model.Uploads)
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var upload *model.Uploads
+			if err := json.Unmarshal([]byte(msg.Payload), &upload); err != nil {
+				log.WithError(err).Errorf("unmarshal redis courier document payload")
+				return
+			}
+			if upload.CourierID != nil && *upload.CourierID == c.ID {
+				ch <- upload
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 // Mutation returns gql.MutationResolver implementation.
 func (r *Resolver) Mutation() gql.MutationResolver { return &mutationResolver{r} }
 
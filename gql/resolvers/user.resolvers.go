@@ -0,0 +1,32 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.44
+
+import (
+	"context"
+
+	"github.com/edwinlomolo/uzi-api/gql"
+	"github.com/edwinlomolo/uzi-api/gql/model"
+)
+
+// PromoBalance is the resolver for the promo_balance field.
+func (r *userResolver) PromoBalance(ctx context.Context, obj *model.User) (int, error) {
+	// Only the signed in user gets to see their balance
+	if obj.ID != stringToUUID(ctx.Value("userID").(string)) {
+		return 0, nil
+	}
+
+	wallet, err := r.walletController.GetWallet(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return wallet.PromoBalance, nil
+}
+
+// User returns gql.UserResolver implementation.
+func (r *Resolver) User() gql.UserResolver { return &userResolver{r} }
+
+type userResolver struct{ *Resolver }
@@ -2,11 +2,18 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 
 	"github.com/edwinlomolo/uzi-api/controllers"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrAdminRequired        = errors.New("resolver: admin access required")
+	ErrFleetManagerRequired = errors.New("resolver: fleet manager access required")
+	ErrCourierRequired      = errors.New("resolver: courier access required")
+)
+
 func getCourierIDFromResolverContext(ctx context.Context) uuid.UUID {
 	userID := ctx.Value("userID").(string)
 
@@ -27,3 +34,39 @@ func stringToUUID(id string) uuid.UUID {
 
 	return uid
 }
+
+func requireAdmin(ctx context.Context) error {
+	isAdmin, err := controllers.GetUserController().IsAdmin(stringToUUID(ctx.Value("userID").(string)))
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return ErrAdminRequired
+	}
+
+	return nil
+}
+
+// requireFleetManager - admins manage every fleet
+func requireFleetManager(ctx context.Context, fleetID uuid.UUID) error {
+	userID := stringToUUID(ctx.Value("userID").(string))
+
+	isManager, err := controllers.GetFleetController().IsFleetManager(fleetID, userID)
+	if err != nil {
+		return err
+	}
+	if isManager {
+		return nil
+	}
+
+	isAdmin, err := controllers.GetUserController().IsAdmin(userID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrFleetManagerRequired
+	}
+
+	return nil
+}
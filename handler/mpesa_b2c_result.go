@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/edwinlomolo/uzi-api/controllers"
+	"github.com/edwinlomolo/uzi-api/internal"
+	repo "github.com/edwinlomolo/uzi-api/repository"
+)
+
+// MpesaB2CResult - daraja posts b2c results and queue timeouts in the same shape
+func MpesaB2CResult() http.HandlerFunc {
+	payoutController := controllers.GetPayoutController()
+	mpesa := internal.GetMpesa()
+	log := internal.GetLogger()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result internal.B2CResult
+
+		if !mpesa.VerifyCallback(r.URL.Query().Get("token")) {
+			log.Warnln("handler: mpesa b2c result token mismatch")
+			http.Error(w, "invalid callback token", http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			log.WithError(err).Errorf("handler: unmarshal mpesa b2c result body")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := payoutController.B2CResult(result); err != nil {
+			if errors.Is(err, repo.ErrPayoutNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ResultCode":0,"ResultDesc":"Accepted"}`))
+	})
+}
+
+// MpesaB2CStatus - daraja posts what became of a payout we asked about
+func MpesaB2CStatus() http.HandlerFunc {
+	payoutController := controllers.GetPayoutController()
+	mpesa := internal.GetMpesa()
+	log := internal.GetLogger()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result internal.TransactionStatusResult
+
+		if !mpesa.VerifyCallback(r.URL.Query().Get("token")) {
+			log.Warnln("handler: mpesa b2c status token mismatch")
+			http.Error(w, "invalid callback token", http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			log.WithError(err).Errorf("handler: unmarshal mpesa b2c status body")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := payoutController.B2CStatus(result); err != nil {
+			if errors.Is(err, repo.ErrPayoutNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ResultCode":0,"ResultDesc":"Accepted"}`))
+	})
+}
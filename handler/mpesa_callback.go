@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/edwinlomolo/uzi-api/controllers"
+	"github.com/edwinlomolo/uzi-api/internal"
+	repo "github.com/edwinlomolo/uzi-api/repository"
+)
+
+func MpesaCallback() http.HandlerFunc {
+	paymentController := controllers.GetPaymentController()
+	mpesa := internal.GetMpesa()
+	log := internal.GetLogger()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callback internal.StkCallback
+
+		if !mpesa.VerifyCallback(r.URL.Query().Get("token")) {
+			log.Warnln("handler: mpesa callback token mismatch")
+			http.Error(w, "invalid callback token", http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+			log.WithError(err).Errorf("handler: unmarshal mpesa callback body")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := paymentController.MpesaCallback(callback); err != nil {
+			if errors.Is(err, repo.ErrPaymentNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ResultCode":0,"ResultDesc":"Accepted"}`))
+	})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/internal"
+)
+
+func TestMpesaCallbacksRejectForgedRequests(t *testing.T) {
+	config.Config = &config.Configuration{
+		Mpesa: config.Mpesa{CallbackToken: "callback-secret"},
+	}
+	internal.NewMpesa()
+
+	handlers := map[string]http.HandlerFunc{
+		"stk callback": MpesaCallback(),
+		"b2c result":   MpesaB2CResult(),
+		"b2c status":   MpesaB2CStatus(),
+	}
+
+	for name, h := range handlers {
+		for _, target := range []string{"/webhooks/mpesa", "/webhooks/mpesa?token=forged"} {
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{}`)))
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("%s %s: status = %d, want %d", name, target, w.Code, http.StatusUnauthorized)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/edwinlomolo/uzi-api/controllers"
+	"github.com/edwinlomolo/uzi-api/internal"
+	repo "github.com/edwinlomolo/uzi-api/repository"
+)
+
+func PaystackWebhook() http.HandlerFunc {
+	paymentController := controllers.GetPaymentController()
+	paystack := internal.GetPaystack()
+	log := internal.GetLogger()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event internal.PaystackEvent
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			log.WithError(bodyErr).Errorf("handler: reading paystack webhook body")
+			http.Error(w, bodyErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if !paystack.VerifySignature(body, r.Header.Get("x-paystack-signature")) {
+			log.Warnln("handler: paystack webhook signature mismatch")
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			log.WithError(err).Errorf("handler: unmarshal paystack webhook body")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := paymentController.PaystackWebhook(event); err != nil {
+			if errors.Is(err, repo.ErrPaymentNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
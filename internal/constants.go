@@ -1,8 +1,10 @@
 package internal
 
 const (
-	ZERO_UUID            = "00000000-0000-0000-0000-000000000000"
-	TRIP_UPDATES_CHANNEL = "trip_updates"
-	ASSIGN_TRIP_CHANNEL  = "assign_trip"
-	ComputeRouteApi      = "https://routes.googleapis.com/directions/v2:computeRoutes"
+	ZERO_UUID                 = "00000000-0000-0000-0000-000000000000"
+	TRIP_UPDATES_CHANNEL      = "trip_updates"
+	ASSIGN_TRIP_CHANNEL       = "assign_trip"
+	COURIER_DOCUMENTS_CHANNEL = "courier_documents"
+	ComputeRouteApi           = "https://routes.googleapis.com/directions/v2:computeRoutes"
+	TIMEZONE                  = "Africa/Nairobi"
 )
@@ -0,0 +1,261 @@
+// Package darajatest is a local daraja stand-in for tests. It issues tokens,
+// accepts stk pushes, b2c payments and transaction status queries and posts
+// the result to the request callback url shortly after, the same way
+// safaricom would.
+package darajatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/google/uuid"
+)
+
+const (
+	// CancelPhone - stk push to this msisdn is declined by the "customer"
+	// and b2c payments to it fail
+	CancelPhone = "254700000000"
+	// B2CFailure - result code for failed b2c payments
+	B2CFailure = 2001
+	// callbackDelay - daraja answers before it calls back
+	callbackDelay = 100 * time.Millisecond
+)
+
+type stkPushRequest struct {
+	Amount      int    `json:"Amount"`
+	PhoneNumber string `json:"PhoneNumber"`
+	CallBackURL string `json:"CallBackURL"`
+}
+
+type b2cRequest struct {
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+	Amount                   int    `json:"Amount"`
+	PartyB                   string `json:"PartyB"`
+	ResultURL                string `json:"ResultURL"`
+}
+
+type transactionStatusRequest struct {
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+	ResultURL                string `json:"ResultURL"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   string `json:"expires_in"`
+}
+
+// NewServer - start a fake daraja. Close it when done.
+func NewServer() *httptest.Server {
+	mux := http.NewServeMux()
+	// What became of each b2c payment, for status queries
+	var (
+		mu       sync.Mutex
+		payments = make(map[string]internal.B2CResult)
+	)
+
+	mux.HandleFunc("/oauth/v1/generate", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			http.Error(w, "missing basic auth", http.StatusUnauthorized)
+			return
+		}
+
+		writeJson(w, http.StatusOK, accessTokenResponse{
+			AccessToken: uuid.NewString(),
+			ExpiresIn:   "3599",
+		})
+	})
+
+	mux.HandleFunc("/mpesa/stkpush/v1/processrequest", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		var req stkPushRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
+			writeJson(w, http.StatusBadRequest, internal.StkPushResponse{
+				ErrorCode:    "400.002.02",
+				ErrorMessage: "Bad Request - Invalid Amount",
+			})
+			return
+		}
+
+		res := internal.StkPushResponse{
+			MerchantRequestID:   uuid.NewString(),
+			CheckoutRequestID:   fmt.Sprintf("ws_CO_%s", uuid.NewString()),
+			ResponseCode:        "0",
+			ResponseDescription: "Success. Request accepted for processing",
+			CustomerMessage:     "Success. Request accepted for processing",
+		}
+		writeJson(w, http.StatusOK, res)
+
+		go postResult(req.CallBackURL, stkCallback(req, res))
+	})
+
+	mux.HandleFunc("/mpesa/b2c/v3/paymentrequest", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		var req b2cRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
+			writeJson(w, http.StatusBadRequest, internal.B2CResponse{
+				ErrorCode:    "400.002.02",
+				ErrorMessage: "Bad Request - Invalid Amount",
+			})
+			return
+		}
+
+		res := internal.B2CResponse{
+			ConversationID:           conversationID(),
+			OriginatorConversationID: req.OriginatorConversationID,
+			ResponseCode:             "0",
+			ResponseDescription:      "Accept the service request successfully.",
+		}
+		writeJson(w, http.StatusOK, res)
+
+		result := b2cResult(req, res)
+		mu.Lock()
+		payments[req.OriginatorConversationID] = result
+		mu.Unlock()
+
+		go postResult(req.ResultURL, result)
+	})
+
+	mux.HandleFunc("/mpesa/transactionstatus/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		var req transactionStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		res := internal.B2CResponse{
+			ConversationID:           conversationID(),
+			OriginatorConversationID: uuid.NewString(),
+			ResponseCode:             "0",
+			ResponseDescription:      "Accept the service request successfully.",
+		}
+		writeJson(w, http.StatusOK, res)
+
+		mu.Lock()
+		payment, found := payments[req.OriginatorConversationID]
+		mu.Unlock()
+
+		go postResult(req.ResultURL, statusResult(res, payment, found))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func stkCallback(req stkPushRequest, res internal.StkPushResponse) interface{} {
+	callback := map[string]interface{}{
+		"MerchantRequestID": res.MerchantRequestID,
+		"CheckoutRequestID": res.CheckoutRequestID,
+		"ResultCode":        internal.MpesaResultSuccess,
+		"ResultDesc":        "The service request is processed successfully.",
+		"CallbackMetadata": map[string]interface{}{
+			"Item": []map[string]interface{}{
+				{"Name": "Amount", "Value": req.Amount},
+				{"Name": "MpesaReceiptNumber", "Value": receipt()},
+				{"Name": "TransactionDate", "Value": time.Now().Format("20060102150405")},
+				{"Name": "PhoneNumber", "Value": req.PhoneNumber},
+			},
+		},
+	}
+	if req.PhoneNumber == CancelPhone {
+		callback["ResultCode"] = internal.MpesaResultCancelled
+		callback["ResultDesc"] = "Request cancelled by user"
+		delete(callback, "CallbackMetadata")
+	}
+
+	return map[string]interface{}{
+		"Body": map[string]interface{}{"stkCallback": callback},
+	}
+}
+
+func b2cResult(req b2cRequest, res internal.B2CResponse) internal.B2CResult {
+	var result internal.B2CResult
+	result.Result.OriginatorConversationID = res.OriginatorConversationID
+	result.Result.ConversationID = res.ConversationID
+	result.Result.ResultCode = internal.MpesaResultSuccess
+	result.Result.ResultDesc = "The service request is processed successfully."
+	result.Result.TransactionID = receipt()
+	if req.PartyB == CancelPhone {
+		result.Result.ResultCode = B2CFailure
+		result.Result.ResultDesc = "The initiator information is invalid."
+		result.Result.TransactionID = ""
+	}
+
+	return result
+}
+
+func statusResult(
+	res internal.B2CResponse,
+	payment internal.B2CResult,
+	found bool,
+) internal.TransactionStatusResult {
+	var result internal.TransactionStatusResult
+	result.Result.OriginatorConversationID = res.OriginatorConversationID
+	result.Result.ConversationID = res.ConversationID
+	result.Result.ResultCode = internal.MpesaResultSuccess
+	result.Result.ResultDesc = "The service request is processed successfully."
+
+	status := "Failed"
+	if found && payment.Result.ResultCode == internal.MpesaResultSuccess {
+		status = "Completed"
+	}
+	result.Result.ResultParameters.ResultParameter = []struct {
+		Key   string      `json:"Key"`
+		Value interface{} `json:"Value,omitempty"`
+	}{
+		{Key: "TransactionStatus", Value: status},
+		{Key: "ReceiptNo", Value: payment.Result.TransactionID},
+	}
+
+	return result
+}
+
+func postResult(url string, result interface{}) {
+	if url == "" {
+		return
+	}
+	time.Sleep(callbackDelay)
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	res, err := http.Post(url, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return
+	}
+	res.Body.Close()
+}
+
+func conversationID() string {
+	return fmt.Sprintf("AG_%s", strings.ReplaceAll(uuid.NewString(), "-", "")[:20])
+}
+
+func receipt() string {
+	return strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:10])
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
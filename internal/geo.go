@@ -0,0 +1,447 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// GEO set of courier positions
+	courierLocationsKey = "courier_locations"
+	// Sorted set of when each courier last pinged
+	courierSeenKey = "courier_seen"
+	// Latest ping per courier not yet written to Postgres
+	courierPendingKey  = "courier_pending_locations"
+	courierFlushingKey = "courier_flushing_locations"
+	// GPS breadcrumbs not yet written to Postgres
+	courierBreadcrumbsKey         = "courier_breadcrumbs"
+	courierFlushingBreadcrumbsKey = "courier_flushing_breadcrumbs"
+	courierUsersKey               = "courier_users"
+	courierTripsKey               = "courier_trips"
+)
+
+var (
+	geo CourierIndex
+	// Only pings after the last seen time are kept, and only the one that
+	// moves it may move the live position, in one step so a concurrent or
+	// replayed batch can't overwrite a fresher fix or repeat breadcrumbs.
+	// KEYS: breadcrumbs, seen, locations, pending.
+	// ARGV: courier id, lng, lat, then seen score and breadcrumb per ping
+	// oldest first.
+	trackCourierScript = redis.NewScript(`
+local seen = redis.call('ZSCORE', KEYS[2], ARGV[1])
+seen = seen and tonumber(seen) or -1
+local fresh = {}
+for i = 4, #ARGV, 2 do
+  if tonumber(ARGV[i]) > seen then
+    table.insert(fresh, ARGV[i + 1])
+  end
+end
+if #fresh == 0 then
+  return 0
+end
+redis.call('RPUSH', KEYS[1], unpack(fresh))
+local moved = redis.call('ZADD', KEYS[2], 'GT', 'CH', ARGV[#ARGV - 1], ARGV[1])
+if moved == 1 then
+  redis.call('GEOADD', KEYS[3], ARGV[2], ARGV[3], ARGV[1])
+  redis.call('HSET', KEYS[4], ARGV[1], ARGV[#ARGV])
+end
+return moved
+`)
+)
+
+// CourierIndex - live courier positions. GPS pings land here so dispatch
+// doesn't touch Postgres on every ping, Postgres catches up on each flush.
+type CourierIndex interface {
+	TrackCourier(ctx context.Context, courierID uuid.UUID, pings []CourierPing) (bool, error)
+	NearbyCouriers(ctx context.Context, point model.GpsInput, radius float64) ([]*TrackedCourier, error)
+	GetCourier(ctx context.Context, courierID uuid.UUID) (*TrackedCourier, error)
+	PendingLocations(ctx context.Context) ([]*TrackedCourier, error)
+	ClearPendingLocations(ctx context.Context) error
+	PendingBreadcrumbs(ctx context.Context) ([]*CourierPing, error)
+	ClearPendingBreadcrumbs(ctx context.Context) error
+	PruneStaleCouriers(ctx context.Context, now time.Time) (int, error)
+	GetCourierID(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error)
+	SetCourierID(ctx context.Context, userID, courierID uuid.UUID) error
+	GetCourierTrip(ctx context.Context, courierID uuid.UUID) (*uuid.UUID, error)
+	SetCourierTrip(ctx context.Context, courierID uuid.UUID, tripID *uuid.UUID) error
+}
+
+type TrackedCourier struct {
+	ID       uuid.UUID
+	Location model.Gps
+	SeenAt   time.Time
+	// Meters from the searched point
+	Distance float64
+}
+
+// CourierPing - one GPS fix from a courier
+type CourierPing struct {
+	CourierID uuid.UUID `json:"courier_id"`
+	// Trip the courier is on when the ping arrives. History attributes points
+	// to trips by when they were recorded.
+	TripID     *uuid.UUID `json:"trip_id,omitempty"`
+	Lat        float64    `json:"lat"`
+	Lng        float64    `json:"lng"`
+	Accuracy   *float64   `json:"accuracy,omitempty"`
+	Speed      *float64   `json:"speed,omitempty"`
+	Heading    *float64   `json:"heading,omitempty"`
+	RecordedAt time.Time  `json:"recorded_at"`
+}
+
+type redisCourierIndex struct {
+	redis *redis.Client
+	// Couriers silent for longer aren't offered trips
+	ttl time.Duration
+	log *logrus.Logger
+}
+
+func NewCourierIndex() {
+	geo = &redisCourierIndex{
+		GetCache().GetRedis(),
+		config.Config.Courier.HeartbeatTimeout,
+		log,
+	}
+}
+
+func GetCourierIndex() CourierIndex {
+	return geo
+}
+
+// TrackCourier - append pings, oldest first, to the breadcrumbs and move the
+// live position to the last one. Pings at or before the courier's last seen
+// time were already tracked and are dropped. Reports whether the live
+// position moved.
+func (r *redisCourierIndex) TrackCourier(ctx context.Context, courierID uuid.UUID, pings []CourierPing) (bool, error) {
+	if len(pings) == 0 {
+		return false, nil
+	}
+	id := courierID.String()
+
+	freshest := pings[len(pings)-1]
+	args := []interface{}{id, freshest.Lng, freshest.Lat}
+	for i := range pings {
+		pings[i].RecordedAt = pings[i].RecordedAt.UTC()
+		breadcrumb, err := json.Marshal(pings[i])
+		if err != nil {
+			return false, err
+		}
+		args = append(args, seenScore(pings[i].RecordedAt), breadcrumb)
+	}
+	moved, err := trackCourierScript.Run(ctx, r.redis, []string{
+		courierBreadcrumbsKey,
+		courierSeenKey,
+		courierLocationsKey,
+		courierPendingKey,
+	}, args...).Int()
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("courier index: track courier")
+		return false, err
+	}
+
+	return moved == 1, nil
+}
+
+// NearbyCouriers - couriers seen recently within radius meters, nearest first
+func (r *redisCourierIndex) NearbyCouriers(
+	ctx context.Context,
+	point model.GpsInput,
+	radius float64,
+) ([]*TrackedCourier, error) {
+	couriers := make([]*TrackedCourier, 0)
+
+	found, err := r.redis.GeoSearchLocation(ctx, courierLocationsKey, &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: redis.GeoSearchQuery{
+			Longitude:  point.Lng,
+			Latitude:   point.Lat,
+			Radius:     radius,
+			RadiusUnit: "m",
+			Sort:       "ASC",
+		},
+		WithCoord: true,
+		WithDist:  true,
+	}).Result()
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: nearby couriers")
+		return nil, err
+	}
+	if len(found) == 0 {
+		return couriers, nil
+	}
+
+	ids := make([]string, len(found))
+	for i, item := range found {
+		ids[i] = item.Name
+	}
+	seen, err := r.redis.ZMScore(ctx, courierSeenKey, ids...).Result()
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: nearby couriers seen")
+		return nil, err
+	}
+
+	freshSince := time.Now().Add(-r.ttl).Unix()
+	for i, item := range found {
+		courierID, err := uuid.Parse(item.Name)
+		if err != nil || int64(seen[i]) < freshSince {
+			continue
+		}
+
+		couriers = append(couriers, &TrackedCourier{
+			ID:       courierID,
+			Location: model.Gps{Lat: item.Latitude, Lng: item.Longitude},
+			SeenAt:   seenTime(seen[i]),
+			Distance: item.Dist,
+		})
+	}
+
+	return couriers, nil
+}
+
+// GetCourier - last known position, nil when the courier isn't in the index
+func (r *redisCourierIndex) GetCourier(ctx context.Context, courierID uuid.UUID) (*TrackedCourier, error) {
+	id := courierID.String()
+
+	var (
+		pos  *redis.GeoPosCmd
+		seen *redis.FloatCmd
+	)
+	_, err := r.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pos = pipe.GeoPos(ctx, courierLocationsKey, id)
+		seen = pipe.ZScore(ctx, courierSeenKey, id)
+		return nil
+	})
+	if err == redis.Nil || (err == nil && pos.Val()[0] == nil) {
+		return nil, nil
+	} else if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("courier index: get courier")
+		return nil, err
+	}
+
+	return &TrackedCourier{
+		ID:       courierID,
+		Location: model.Gps{Lat: pos.Val()[0].Latitude, Lng: pos.Val()[0].Longitude},
+		SeenAt:   seenTime(seen.Val()),
+	}, nil
+}
+
+// PendingLocations - positions to write to Postgres. A batch that failed to
+// flush is handed out again until ClearPendingLocations.
+func (r *redisCourierIndex) PendingLocations(ctx context.Context) ([]*TrackedCourier, error) {
+	pending := make([]*TrackedCourier, 0)
+
+	flushing, err := r.redis.HGetAll(ctx, courierFlushingKey).Result()
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: get flushing locations")
+		return nil, err
+	}
+
+	if len(flushing) == 0 {
+		err := r.redis.Rename(ctx, courierPendingKey, courierFlushingKey).Err()
+		if err != nil && err.Error() == "ERR no such key" {
+			return pending, nil
+		} else if err != nil {
+			r.log.WithError(err).Errorf("courier index: swap pending locations")
+			return nil, err
+		}
+
+		flushing, err = r.redis.HGetAll(ctx, courierFlushingKey).Result()
+		if err != nil {
+			r.log.WithError(err).Errorf("courier index: get flushing locations")
+			return nil, err
+		}
+	}
+
+	for id, value := range flushing {
+		courierID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+
+		var ping CourierPing
+		if err := json.Unmarshal([]byte(value), &ping); err != nil {
+			r.log.WithFields(logrus.Fields{
+				"courier_id": id,
+			}).WithError(err).Errorf("courier index: unmarshal pending location")
+			continue
+		}
+
+		pending = append(pending, &TrackedCourier{
+			ID:       courierID,
+			Location: model.Gps{Lat: ping.Lat, Lng: ping.Lng},
+			SeenAt:   ping.RecordedAt,
+		})
+	}
+
+	return pending, nil
+}
+
+func (r *redisCourierIndex) ClearPendingLocations(ctx context.Context) error {
+	if err := r.redis.Del(ctx, courierFlushingKey).Err(); err != nil {
+		r.log.WithError(err).Errorf("courier index: clear flushed locations")
+		return err
+	}
+
+	return nil
+}
+
+// PendingBreadcrumbs - GPS fixes to append to the history. A batch that failed
+// to flush is handed out again until ClearPendingBreadcrumbs.
+func (r *redisCourierIndex) PendingBreadcrumbs(ctx context.Context) ([]*CourierPing, error) {
+	pings := make([]*CourierPing, 0)
+
+	flushing, err := r.redis.LRange(ctx, courierFlushingBreadcrumbsKey, 0, -1).Result()
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: get flushing breadcrumbs")
+		return nil, err
+	}
+
+	if len(flushing) == 0 {
+		err := r.redis.Rename(ctx, courierBreadcrumbsKey, courierFlushingBreadcrumbsKey).Err()
+		if err != nil && err.Error() == "ERR no such key" {
+			return pings, nil
+		} else if err != nil {
+			r.log.WithError(err).Errorf("courier index: swap pending breadcrumbs")
+			return nil, err
+		}
+
+		flushing, err = r.redis.LRange(ctx, courierFlushingBreadcrumbsKey, 0, -1).Result()
+		if err != nil {
+			r.log.WithError(err).Errorf("courier index: get flushing breadcrumbs")
+			return nil, err
+		}
+	}
+
+	for _, value := range flushing {
+		var ping CourierPing
+		if err := json.Unmarshal([]byte(value), &ping); err != nil {
+			r.log.WithError(err).Errorf("courier index: unmarshal breadcrumb")
+			continue
+		}
+		pings = append(pings, &ping)
+	}
+
+	return pings, nil
+}
+
+func (r *redisCourierIndex) ClearPendingBreadcrumbs(ctx context.Context) error {
+	if err := r.redis.Del(ctx, courierFlushingBreadcrumbsKey).Err(); err != nil {
+		r.log.WithError(err).Errorf("courier index: clear flushed breadcrumbs")
+		return err
+	}
+
+	return nil
+}
+
+// PruneStaleCouriers - drop couriers that stopped pinging so searches stay small
+func (r *redisCourierIndex) PruneStaleCouriers(ctx context.Context, now time.Time) (int, error) {
+	staleBefore := fmt.Sprintf("(%d", now.Add(-r.ttl).Unix())
+
+	stale, err := r.redis.ZRangeByScore(ctx, courierSeenKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: staleBefore,
+	}).Result()
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: get stale couriers")
+		return 0, err
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	members := make([]interface{}, len(stale))
+	for i, id := range stale {
+		members[i] = id
+	}
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, courierLocationsKey, members...)
+		pipe.ZRemRangeByScore(ctx, courierSeenKey, "-inf", staleBefore)
+		return nil
+	})
+	if err != nil {
+		r.log.WithError(err).Errorf("courier index: prune stale couriers")
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+// GetCourierID - courier behind a user, nil until SetCourierID
+func (r *redisCourierIndex) GetCourierID(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	return r.getID(ctx, courierUsersKey, userID)
+}
+
+func (r *redisCourierIndex) SetCourierID(ctx context.Context, userID, courierID uuid.UUID) error {
+	if err := r.redis.HSet(ctx, courierUsersKey, userID.String(), courierID.String()).Err(); err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_user_id": userID,
+		}).WithError(err).Errorf("courier index: set courier id")
+		return err
+	}
+
+	return nil
+}
+
+// GetCourierTrip - trip the courier is on, nil when they're free
+func (r *redisCourierIndex) GetCourierTrip(ctx context.Context, courierID uuid.UUID) (*uuid.UUID, error) {
+	return r.getID(ctx, courierTripsKey, courierID)
+}
+
+// SetCourierTrip - a nil trip frees the courier
+func (r *redisCourierIndex) SetCourierTrip(ctx context.Context, courierID uuid.UUID, tripID *uuid.UUID) error {
+	var err error
+	if tripID == nil {
+		err = r.redis.HDel(ctx, courierTripsKey, courierID.String()).Err()
+	} else {
+		err = r.redis.HSet(ctx, courierTripsKey, courierID.String(), tripID.String()).Err()
+	}
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+			"trip_id":    tripID,
+		}).WithError(err).Errorf("courier index: set courier trip")
+		return err
+	}
+
+	return nil
+}
+
+func (r *redisCourierIndex) getID(ctx context.Context, key string, field uuid.UUID) (*uuid.UUID, error) {
+	value, err := r.redis.HGet(ctx, key, field.String()).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"key":   key,
+			"field": field,
+		}).WithError(err).Errorf("courier index: get id")
+		return nil, err
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, nil
+	}
+
+	return &id, nil
+}
+
+// Seen scores are unix seconds with millisecond fractions
+func seenScore(at time.Time) float64 {
+	return float64(at.UnixMilli()) / 1000
+}
+
+func seenTime(score float64) time.Time {
+	return time.UnixMilli(int64(score * 1000)).UTC()
+}
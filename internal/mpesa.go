@@ -0,0 +1,488 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	mpesaService Mpesa
+
+	ErrInvalidMpesaPhone = errors.New("mpesa: invalid phone number")
+	// The request went out but we didn't get daraja's answer, it may have
+	// been accepted
+	ErrMpesaNoResponse = errors.New("mpesa: no response from daraja")
+)
+
+const (
+	// Daraja stk callback result codes we care about
+	MpesaResultSuccess   = 0
+	MpesaResultCancelled = 1032
+)
+
+type Mpesa interface {
+	StkPush(phone string, amount int, reference string) (*StkPushResponse, error)
+	B2CPayment(phone string, amount int, originatorID string) (*B2CResponse, error)
+	TransactionStatus(originatorID string) (*B2CResponse, error)
+	VerifyCallback(token string) bool
+}
+
+type StkPushResponse struct {
+	MerchantRequestID   string `json:"MerchantRequestID"`
+	CheckoutRequestID   string `json:"CheckoutRequestID"`
+	ResponseCode        string `json:"ResponseCode"`
+	ResponseDescription string `json:"ResponseDescription"`
+	CustomerMessage     string `json:"CustomerMessage"`
+	ErrorCode           string `json:"errorCode,omitempty"`
+	ErrorMessage        string `json:"errorMessage,omitempty"`
+}
+
+type stkPushRequest struct {
+	BusinessShortCode string `json:"BusinessShortCode"`
+	Password          string `json:"Password"`
+	Timestamp         string `json:"Timestamp"`
+	TransactionType   string `json:"TransactionType"`
+	Amount            int    `json:"Amount"`
+	PartyA            string `json:"PartyA"`
+	PartyB            string `json:"PartyB"`
+	PhoneNumber       string `json:"PhoneNumber"`
+	CallBackURL       string `json:"CallBackURL"`
+	AccountReference  string `json:"AccountReference"`
+	TransactionDesc   string `json:"TransactionDesc"`
+}
+
+// StkCallback - daraja stk push result posted to our callback url
+type StkCallback struct {
+	Body struct {
+		StkCallback struct {
+			MerchantRequestID string `json:"MerchantRequestID"`
+			CheckoutRequestID string `json:"CheckoutRequestID"`
+			ResultCode        int    `json:"ResultCode"`
+			ResultDesc        string `json:"ResultDesc"`
+			CallbackMetadata  struct {
+				Item []struct {
+					Name  string      `json:"Name"`
+					Value interface{} `json:"Value,omitempty"`
+				} `json:"Item"`
+			} `json:"CallbackMetadata"`
+		} `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+// Receipt - mpesa receipt number from callback metadata
+func (s StkCallback) Receipt() string {
+	for _, item := range s.Body.StkCallback.CallbackMetadata.Item {
+		if item.Name == "MpesaReceiptNumber" {
+			return fmt.Sprintf("%v", item.Value)
+		}
+	}
+
+	return ""
+}
+
+// Amount - what the customer actually paid from callback metadata
+func (s StkCallback) Amount() int {
+	for _, item := range s.Body.StkCallback.CallbackMetadata.Item {
+		if item.Name != "Amount" {
+			continue
+		}
+
+		switch v := item.Value.(type) {
+		case float64:
+			return int(v)
+		case string:
+			amount, _ := strconv.ParseFloat(v, 64)
+			return int(amount)
+		}
+	}
+
+	return 0
+}
+
+type b2cRequest struct {
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+	InitiatorName            string `json:"InitiatorName"`
+	SecurityCredential       string `json:"SecurityCredential"`
+	CommandID                string `json:"CommandID"`
+	Amount                   int    `json:"Amount"`
+	PartyA                   string `json:"PartyA"`
+	PartyB                   string `json:"PartyB"`
+	Remarks                  string `json:"Remarks"`
+	QueueTimeOutURL          string `json:"QueueTimeOutURL"`
+	ResultURL                string `json:"ResultURL"`
+	Occasion                 string `json:"Occasion"`
+}
+
+type B2CResponse struct {
+	ConversationID           string `json:"ConversationID"`
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+	ResponseCode             string `json:"ResponseCode"`
+	ResponseDescription      string `json:"ResponseDescription"`
+	ErrorCode                string `json:"errorCode,omitempty"`
+	ErrorMessage             string `json:"errorMessage,omitempty"`
+}
+
+// B2CResult - daraja b2c result posted to our result or queue timeout url
+type B2CResult struct {
+	Result struct {
+		ResultType               int    `json:"ResultType"`
+		ResultCode               int    `json:"ResultCode"`
+		ResultDesc               string `json:"ResultDesc"`
+		OriginatorConversationID string `json:"OriginatorConversationID"`
+		ConversationID           string `json:"ConversationID"`
+		TransactionID            string `json:"TransactionID"`
+	} `json:"Result"`
+}
+
+type transactionStatusRequest struct {
+	Initiator                string `json:"Initiator"`
+	SecurityCredential       string `json:"SecurityCredential"`
+	CommandID                string `json:"CommandID"`
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+	PartyA                   string `json:"PartyA"`
+	IdentifierType           string `json:"IdentifierType"`
+	ResultURL                string `json:"ResultURL"`
+	QueueTimeOutURL          string `json:"QueueTimeOutURL"`
+	Remarks                  string `json:"Remarks"`
+	Occasion                 string `json:"Occasion"`
+}
+
+// TransactionStatusResult - daraja transaction status posted to our status url
+type TransactionStatusResult struct {
+	Result struct {
+		ResultType               int    `json:"ResultType"`
+		ResultCode               int    `json:"ResultCode"`
+		ResultDesc               string `json:"ResultDesc"`
+		OriginatorConversationID string `json:"OriginatorConversationID"`
+		ConversationID           string `json:"ConversationID"`
+		TransactionID            string `json:"TransactionID"`
+		ResultParameters         struct {
+			ResultParameter []struct {
+				Key   string      `json:"Key"`
+				Value interface{} `json:"Value,omitempty"`
+			} `json:"ResultParameter"`
+		} `json:"ResultParameters"`
+	} `json:"Result"`
+}
+
+// Completed - the queried transaction went through
+func (t TransactionStatusResult) Completed() bool {
+	return t.Result.ResultCode == MpesaResultSuccess &&
+		t.Parameter("TransactionStatus") == "Completed"
+}
+
+// Parameter - result parameter value by key
+func (t TransactionStatusResult) Parameter(key string) string {
+	for _, item := range t.Result.ResultParameters.ResultParameter {
+		if item.Key == key {
+			return fmt.Sprintf("%v", item.Value)
+		}
+	}
+
+	return ""
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   string `json:"expires_in"`
+}
+
+type mpesaClient struct {
+	mu          sync.Mutex
+	baseApi     string
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewMpesa() {
+	mpesaService = &mpesaClient{baseApi: config.Config.Mpesa.BaseApi}
+}
+
+func GetMpesa() Mpesa {
+	return mpesaService
+}
+
+func (m *mpesaClient) StkPush(
+	phone string,
+	amount int,
+	reference string,
+) (*StkPushResponse, error) {
+	msisdn, err := MpesaPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := m.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	shortCode := config.Config.Mpesa.ShortCode
+	timestamp := time.Now().In(time.FixedZone("EAT", 3*60*60)).Format("20060102150405")
+	password := base64.StdEncoding.EncodeToString(
+		[]byte(shortCode + config.Config.Mpesa.PassKey + timestamp),
+	)
+
+	payload, err := json.Marshal(stkPushRequest{
+		BusinessShortCode: shortCode,
+		Password:          password,
+		Timestamp:         timestamp,
+		TransactionType:   "CustomerPayBillOnline",
+		Amount:            amount,
+		PartyA:            msisdn,
+		PartyB:            shortCode,
+		PhoneNumber:       msisdn,
+		CallBackURL:       callbackUrl(config.Config.Mpesa.CallbackUrl),
+		AccountReference:  reference,
+		TransactionDesc:   "Uzi trip",
+	})
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: marshal stk push request")
+		return nil, err
+	}
+
+	var stkRes StkPushResponse
+	if err := m.post("/mpesa/stkpush/v1/processrequest", token, payload, &stkRes); err != nil {
+		return nil, err
+	}
+
+	if stkRes.ErrorCode != "" || stkRes.ResponseCode != "0" {
+		resErr := fmt.Errorf("mpesa: stk push: %s%s", stkRes.ErrorMessage, stkRes.ResponseDescription)
+		log.WithFields(logrus.Fields{
+			"reference":  reference,
+			"error_code": stkRes.ErrorCode,
+		}).WithError(resErr).Errorf("mpesa: stk push response")
+		return nil, resErr
+	}
+
+	return &stkRes, nil
+}
+
+// B2CPayment - send money from the b2c shortcode to a customer phone. The
+// outcome arrives later on the result url keyed by originatorID, which must
+// be unique per attempt.
+func (m *mpesaClient) B2CPayment(
+	phone string,
+	amount int,
+	originatorID string,
+) (*B2CResponse, error) {
+	msisdn, err := MpesaPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := m.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(b2cRequest{
+		OriginatorConversationID: originatorID,
+		InitiatorName:            config.Config.Mpesa.InitiatorName,
+		SecurityCredential:       config.Config.Mpesa.SecurityCredential,
+		CommandID:                "BusinessPayment",
+		Amount:                   amount,
+		PartyA:                   config.Config.Mpesa.B2CShortCode,
+		PartyB:                   msisdn,
+		Remarks:                  "Uzi courier payout",
+		QueueTimeOutURL:          callbackUrl(config.Config.Mpesa.B2CTimeoutUrl),
+		ResultURL:                callbackUrl(config.Config.Mpesa.B2CResultUrl),
+		Occasion:                 "Payout",
+	})
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: marshal b2c request")
+		return nil, err
+	}
+
+	var b2cRes B2CResponse
+	if err := m.post("/mpesa/b2c/v3/paymentrequest", token, payload, &b2cRes); err != nil {
+		return nil, err
+	}
+
+	if b2cRes.ErrorCode != "" || b2cRes.ResponseCode != "0" {
+		resErr := fmt.Errorf("mpesa: b2c: %s%s", b2cRes.ErrorMessage, b2cRes.ResponseDescription)
+		log.WithFields(logrus.Fields{
+			"originator_id": originatorID,
+			"error_code":    b2cRes.ErrorCode,
+		}).WithError(resErr).Errorf("mpesa: b2c response")
+		return nil, resErr
+	}
+
+	return &b2cRes, nil
+}
+
+// TransactionStatus - ask daraja what became of a b2c payment. The answer
+// arrives later on the status url keyed by the returned conversation id.
+func (m *mpesaClient) TransactionStatus(originatorID string) (*B2CResponse, error) {
+	token, err := m.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(transactionStatusRequest{
+		Initiator:                config.Config.Mpesa.InitiatorName,
+		SecurityCredential:       config.Config.Mpesa.SecurityCredential,
+		CommandID:                "TransactionStatusQuery",
+		OriginatorConversationID: originatorID,
+		PartyA:                   config.Config.Mpesa.B2CShortCode,
+		IdentifierType:           "4",
+		ResultURL:                callbackUrl(config.Config.Mpesa.B2CStatusUrl),
+		QueueTimeOutURL:          callbackUrl(config.Config.Mpesa.B2CStatusUrl),
+		Remarks:                  "Uzi courier payout status",
+		Occasion:                 "Payout",
+	})
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: marshal transaction status request")
+		return nil, err
+	}
+
+	var statusRes B2CResponse
+	if err := m.post("/mpesa/transactionstatus/v1/query", token, payload, &statusRes); err != nil {
+		return nil, err
+	}
+
+	if statusRes.ErrorCode != "" || statusRes.ResponseCode != "0" {
+		resErr := fmt.Errorf("mpesa: transaction status: %s%s", statusRes.ErrorMessage, statusRes.ResponseDescription)
+		log.WithFields(logrus.Fields{
+			"originator_id": originatorID,
+			"error_code":    statusRes.ErrorCode,
+		}).WithError(resErr).Errorf("mpesa: transaction status response")
+		return nil, resErr
+	}
+
+	return &statusRes, nil
+}
+
+// VerifyCallback - daraja doesn't sign callbacks so we only trust requests
+// carrying the token we put on the callback url
+func (m *mpesaClient) VerifyCallback(token string) bool {
+	secret := config.Config.Mpesa.CallbackToken
+	if secret == "" || token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+// callbackUrl - add our callback token to a daraja callback url
+func callbackUrl(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || raw == "" {
+		return raw
+	}
+
+	q := u.Query()
+	q.Set("token", config.Config.Mpesa.CallbackToken)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func (m *mpesaClient) post(path, token string, payload []byte, v interface{}) error {
+	req, err := http.NewRequest("POST", m.baseApi+path, bytes.NewBuffer(payload))
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: %s request", path)
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: call %s api", path)
+		return fmt.Errorf("%w: %v", ErrMpesaNoResponse, err)
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		log.WithError(err).Errorf("mpesa: unmarshal %s res", path)
+		return fmt.Errorf("%w: %v", ErrMpesaNoResponse, err)
+	}
+
+	return nil
+}
+
+// accessToken - reuse daraja oauth token until it expires
+func (m *mpesaClient) accessToken() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token != "" && time.Now().Before(m.tokenExpiry) {
+		return m.token, nil
+	}
+
+	req, err := http.NewRequest(
+		"GET",
+		fmt.Sprintf("%s/oauth/v1/generate?grant_type=client_credentials", m.baseApi),
+		nil,
+	)
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: access token request")
+		return "", err
+	}
+	req.SetBasicAuth(config.Config.Mpesa.ConsumerKey, config.Config.Mpesa.ConsumerSecret)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.WithError(err).Errorf("mpesa: call access token api")
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		resErr := fmt.Errorf("mpesa: access token: %s", res.Status)
+		log.WithError(resErr).Errorf("mpesa: access token response")
+		return "", resErr
+	}
+
+	var tokenRes accessTokenResponse
+	if err := json.NewDecoder(res.Body).Decode(&tokenRes); err != nil {
+		log.WithError(err).Errorf("mpesa: unmarshal access token res")
+		return "", err
+	}
+
+	expiresIn, err := strconv.Atoi(tokenRes.ExpiresIn)
+	if err != nil {
+		expiresIn = 3599
+	}
+
+	m.token = tokenRes.AccessToken
+	// Refresh a minute early so in-flight requests don't race expiry
+	m.tokenExpiry = time.Now().Add(time.Duration(expiresIn-60) * time.Second)
+
+	return m.token, nil
+}
+
+// MpesaPhone - format phone number as daraja msisdn e.g 254712345678
+func MpesaPhone(phone string) (string, error) {
+	p := strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(phone), " ", ""), "+")
+
+	switch {
+	case strings.HasPrefix(p, "0") && len(p) == 10:
+		p = "254" + p[1:]
+	case (strings.HasPrefix(p, "7") || strings.HasPrefix(p, "1")) && len(p) == 9:
+		p = "254" + p
+	}
+
+	if len(p) != 12 || !strings.HasPrefix(p, "254") {
+		return "", ErrInvalidMpesaPhone
+	}
+	if _, err := strconv.Atoi(p); err != nil {
+		return "", ErrInvalidMpesaPhone
+	}
+
+	return p, nil
+}
@@ -0,0 +1,237 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/internal/darajatest"
+)
+
+const callbackToken = "callback-secret"
+
+// callbacks - what daraja posted back to us, by path
+type callbacks struct {
+	stk    chan internal.StkCallback
+	b2c    chan internal.B2CResult
+	status chan internal.TransactionStatusResult
+}
+
+func setupMpesa(t *testing.T) (internal.Mpesa, *callbacks) {
+	t.Helper()
+
+	received := &callbacks{
+		stk:    make(chan internal.StkCallback, 1),
+		b2c:    make(chan internal.B2CResult, 1),
+		status: make(chan internal.TransactionStatusResult, 1),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stk", func(w http.ResponseWriter, r *http.Request) {
+		var callback internal.StkCallback
+		decodeCallback(t, r, &callback)
+		received.stk <- callback
+	})
+	mux.HandleFunc("/b2c", func(w http.ResponseWriter, r *http.Request) {
+		var result internal.B2CResult
+		decodeCallback(t, r, &result)
+		received.b2c <- result
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		var result internal.TransactionStatusResult
+		decodeCallback(t, r, &result)
+		received.status <- result
+	})
+	us := httptest.NewServer(mux)
+	t.Cleanup(us.Close)
+
+	daraja := darajatest.NewServer()
+	t.Cleanup(daraja.Close)
+
+	config.Config = &config.Configuration{
+		Mpesa: config.Mpesa{
+			ConsumerKey:    "key",
+			ConsumerSecret: "secret",
+			BaseApi:        daraja.URL,
+			ShortCode:      "174379",
+			CallbackUrl:    us.URL + "/stk",
+			CallbackToken:  callbackToken,
+			B2CShortCode:   "600000",
+			B2CResultUrl:   us.URL + "/b2c",
+			B2CTimeoutUrl:  us.URL + "/b2c",
+			B2CStatusUrl:   us.URL + "/status",
+		},
+	}
+	internal.NewMpesa()
+
+	return internal.GetMpesa(), received
+}
+
+// decodeCallback - daraja callbacks must carry our token
+func decodeCallback(t *testing.T, r *http.Request, v interface{}) {
+	if !internal.GetMpesa().VerifyCallback(r.URL.Query().Get("token")) {
+		t.Errorf("callback %s without our token", r.URL.Path)
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		t.Errorf("decode %s callback: %v", r.URL.Path, err)
+	}
+}
+
+func await[T any](t *testing.T, c chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("daraja didn't call back")
+	}
+
+	var zero T
+	return zero
+}
+
+func TestStkPushCallback(t *testing.T) {
+	mpesa, received := setupMpesa(t)
+
+	res, err := mpesa.StkPush("0712345678", 150, "trip")
+	if err != nil {
+		t.Fatalf("stk push: %v", err)
+	}
+
+	callback := await(t, received.stk)
+	result := callback.Body.StkCallback
+	if result.CheckoutRequestID != res.CheckoutRequestID {
+		t.Errorf("checkout request id = %s, want %s", result.CheckoutRequestID, res.CheckoutRequestID)
+	}
+	if result.ResultCode != internal.MpesaResultSuccess {
+		t.Errorf("result code = %d, want %d", result.ResultCode, internal.MpesaResultSuccess)
+	}
+	if callback.Amount() != 150 {
+		t.Errorf("amount = %d, want 150", callback.Amount())
+	}
+	if callback.Receipt() == "" {
+		t.Error("paid callback without a receipt")
+	}
+}
+
+func TestStkPushCancelled(t *testing.T) {
+	mpesa, received := setupMpesa(t)
+
+	if _, err := mpesa.StkPush(darajatest.CancelPhone, 150, "trip"); err != nil {
+		t.Fatalf("stk push: %v", err)
+	}
+
+	callback := await(t, received.stk)
+	if callback.Body.StkCallback.ResultCode != internal.MpesaResultCancelled {
+		t.Errorf("result code = %d, want %d", callback.Body.StkCallback.ResultCode, internal.MpesaResultCancelled)
+	}
+	if callback.Amount() != 0 || callback.Receipt() != "" {
+		t.Error("cancelled callback carries payment metadata")
+	}
+}
+
+func TestB2CPaymentSuccess(t *testing.T) {
+	mpesa, received := setupMpesa(t)
+
+	res, err := mpesa.B2CPayment("0712345678", 500, "payout-1")
+	if err != nil {
+		t.Fatalf("b2c payment: %v", err)
+	}
+
+	result := await(t, received.b2c).Result
+	if result.OriginatorConversationID != "payout-1" || result.ConversationID != res.ConversationID {
+		t.Errorf("result for %s/%s, want payout-1/%s", result.OriginatorConversationID, result.ConversationID, res.ConversationID)
+	}
+	if result.ResultCode != internal.MpesaResultSuccess || result.TransactionID == "" {
+		t.Errorf("result code = %d receipt = %q, want a paid result", result.ResultCode, result.TransactionID)
+	}
+
+	query, err := mpesa.TransactionStatus("payout-1")
+	if err != nil {
+		t.Fatalf("transaction status: %v", err)
+	}
+
+	status := await(t, received.status)
+	if status.Result.ConversationID != query.ConversationID {
+		t.Errorf("status for %s, want %s", status.Result.ConversationID, query.ConversationID)
+	}
+	if !status.Completed() {
+		t.Error("paid b2c payment isn't completed")
+	}
+	if status.Parameter("ReceiptNo") != result.TransactionID {
+		t.Errorf("receipt = %s, want %s", status.Parameter("ReceiptNo"), result.TransactionID)
+	}
+}
+
+func TestB2CPaymentFailure(t *testing.T) {
+	mpesa, received := setupMpesa(t)
+
+	if _, err := mpesa.B2CPayment(darajatest.CancelPhone, 500, "payout-2"); err != nil {
+		t.Fatalf("b2c payment: %v", err)
+	}
+
+	result := await(t, received.b2c).Result
+	if result.ResultCode != darajatest.B2CFailure {
+		t.Errorf("result code = %d, want %d", result.ResultCode, darajatest.B2CFailure)
+	}
+
+	if _, err := mpesa.TransactionStatus("payout-2"); err != nil {
+		t.Fatalf("transaction status: %v", err)
+	}
+
+	if status := await(t, received.status); status.Completed() {
+		t.Error("failed b2c payment is completed")
+	}
+}
+
+func TestVerifyCallback(t *testing.T) {
+	mpesa, _ := setupMpesa(t)
+
+	for _, token := range []string{"", "forged"} {
+		if mpesa.VerifyCallback(token) {
+			t.Errorf("verified callback token %q", token)
+		}
+	}
+	if !mpesa.VerifyCallback(callbackToken) {
+		t.Error("our callback token didn't verify")
+	}
+
+	config.Config.Mpesa.CallbackToken = ""
+	if mpesa.VerifyCallback("") {
+		t.Error("verified callback without a configured token")
+	}
+}
+
+func TestB2CPaymentNoResponse(t *testing.T) {
+	setupMpesa(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/v1/generate", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "expires_in": "3599"})
+	})
+	// Daraja took the request but the connection dropped before it answered
+	mux.HandleFunc("/mpesa/b2c/v3/paymentrequest", func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("hijack: %v", err)
+		}
+		conn.Close()
+	})
+	daraja := httptest.NewServer(mux)
+	t.Cleanup(daraja.Close)
+
+	config.Config.Mpesa.BaseApi = daraja.URL
+	internal.NewMpesa()
+
+	_, err := internal.GetMpesa().B2CPayment("0712345678", 500, "payout-3")
+	if !errors.Is(err, internal.ErrMpesaNoResponse) {
+		t.Errorf("error = %v, want %v", err, internal.ErrMpesaNoResponse)
+	}
+}
@@ -0,0 +1,222 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	paystackService Paystack
+)
+
+const (
+	PAYSTACK_CURRENCY = "KES"
+	// Users sign in with phone only but paystack customers need an email
+	paystackEmailDomain = "customers.uzi.africa"
+)
+
+type Paystack interface {
+	InitializeTransaction(email string, amount int, reference string) (*PaystackTransaction, error)
+	ChargeAuthorization(email, authorizationCode string, amount int, reference string) (*PaystackCharge, error)
+	Refund(reference string, amount int) error
+	VerifySignature(body []byte, signature string) bool
+}
+
+type PaystackTransaction struct {
+	AuthorizationUrl string `json:"authorization_url"`
+	AccessCode       string `json:"access_code"`
+	Reference        string `json:"reference"`
+}
+
+type PaystackAuthorization struct {
+	AuthorizationCode string `json:"authorization_code"`
+	CardType          string `json:"card_type"`
+	Last4             string `json:"last4"`
+	ExpMonth          string `json:"exp_month"`
+	ExpYear           string `json:"exp_year"`
+	Bank              string `json:"bank"`
+	Channel           string `json:"channel"`
+	Signature         string `json:"signature"`
+	Reusable          bool   `json:"reusable"`
+}
+
+type PaystackCharge struct {
+	ID            int64                 `json:"id"`
+	Status        string                `json:"status"`
+	Reference     string                `json:"reference"`
+	Amount        int                   `json:"amount"`
+	Currency      string                `json:"currency"`
+	GatewayReply  string                `json:"gateway_response"`
+	Authorization PaystackAuthorization `json:"authorization"`
+	Customer      struct {
+		Email string `json:"email"`
+	} `json:"customer"`
+}
+
+type PaystackRefund struct {
+	ID                   int64  `json:"id"`
+	Status               string `json:"status"`
+	TransactionReference string `json:"transaction_reference"`
+	Amount               int    `json:"amount"`
+}
+
+// PaystackEvent - webhook payload, data shape depends on the event
+type PaystackEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type paystackResponse struct {
+	Status  bool            `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type paystackClient struct {
+	baseApi   string
+	secretKey string
+}
+
+func NewPaystack() {
+	paystackService = &paystackClient{
+		config.Config.Paystack.BaseApi,
+		config.Config.Paystack.SecretKey,
+	}
+}
+
+func GetPaystack() Paystack {
+	return paystackService
+}
+
+// PaystackEmail - stable paystack customer email for a user phone
+func PaystackEmail(phone string) string {
+	msisdn, err := MpesaPhone(phone)
+	if err != nil {
+		msisdn = phone
+	}
+
+	return fmt.Sprintf("%s@%s", msisdn, paystackEmailDomain)
+}
+
+// PaystackAmount - paystack amounts are in the currency subunit
+func PaystackAmount(amount int) int {
+	return amount * 100
+}
+
+func (p *paystackClient) InitializeTransaction(
+	email string,
+	amount int,
+	reference string,
+) (*PaystackTransaction, error) {
+	var transaction PaystackTransaction
+
+	if err := p.post("/transaction/initialize", map[string]interface{}{
+		"email":        email,
+		"amount":       PaystackAmount(amount),
+		"currency":     PAYSTACK_CURRENCY,
+		"reference":    reference,
+		"callback_url": config.Config.Paystack.CallbackUrl,
+		"channels":     []string{"card"},
+	}, &transaction); err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (p *paystackClient) ChargeAuthorization(
+	email, authorizationCode string,
+	amount int,
+	reference string,
+) (*PaystackCharge, error) {
+	var charge PaystackCharge
+
+	if err := p.post("/transaction/charge_authorization", map[string]interface{}{
+		"email":              email,
+		"amount":             PaystackAmount(amount),
+		"currency":           PAYSTACK_CURRENCY,
+		"reference":          reference,
+		"authorization_code": authorizationCode,
+	}, &charge); err != nil {
+		return nil, err
+	}
+
+	return &charge, nil
+}
+
+func (p *paystackClient) Refund(reference string, amount int) error {
+	var refund PaystackRefund
+
+	return p.post("/refund", map[string]interface{}{
+		"transaction": reference,
+		"amount":      PaystackAmount(amount),
+		"currency":    PAYSTACK_CURRENCY,
+	}, &refund)
+}
+
+// VerifySignature - paystack signs webhook bodies with HMAC-SHA512 of our secret key.
+// Without a key anyone could sign, so nothing verifies.
+func (p *paystackClient) VerifySignature(body []byte, signature string) bool {
+	if p.secretKey == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha512.New, []byte(p.secretKey))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (p *paystackClient) post(path string, payload, data interface{}) error {
+	reqPayload, err := json.Marshal(payload)
+	if err != nil {
+		log.WithError(err).Errorf("paystack: marshal %s request", path)
+		return err
+	}
+
+	req, err := http.NewRequest("POST", p.baseApi+path, bytes.NewBuffer(reqPayload))
+	if err != nil {
+		log.WithError(err).Errorf("paystack: %s request", path)
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+p.secretKey)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.WithError(err).Errorf("paystack: call %s api", path)
+		return err
+	}
+	defer res.Body.Close()
+
+	var paystackRes paystackResponse
+	if err := json.NewDecoder(res.Body).Decode(&paystackRes); err != nil {
+		log.WithError(err).Errorf("paystack: unmarshal %s res", path)
+		return err
+	}
+
+	if !paystackRes.Status {
+		resErr := fmt.Errorf("paystack: %s", paystackRes.Message)
+		log.WithFields(logrus.Fields{
+			"path":        path,
+			"status_code": res.StatusCode,
+		}).WithError(resErr).Errorf("paystack: response")
+		return resErr
+	}
+
+	if err := json.Unmarshal(paystackRes.Data, data); err != nil {
+		log.WithError(err).Errorf("paystack: unmarshal %s data", path)
+		return err
+	}
+
+	return nil
+}
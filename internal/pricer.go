@@ -2,36 +2,79 @@ package internal
 
 import (
 	"math"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/edwinlomolo/uzi-api/config"
 )
 
 var (
 	pService Pricing
+	tz       *time.Location
 )
 
+// Pricing schedule kinds
+const (
+	NightSchedule   = "NIGHT"
+	WeekendSchedule = "WEEKEND"
+	HolidaySchedule = "HOLIDAY"
+)
+
+// Courier performance tiers
+const (
+	BronzeTier = "BRONZE"
+	SilverTier = "SILVER"
+	GoldTier   = "GOLD"
+)
+
+// PricingSchedule - time-of-day/day-of-week multiplier rule
+type PricingSchedule struct {
+	Kind       string
+	Multiplier float64
+	// Local time window, can wrap past midnight
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
 type Pricing interface {
-	CalculateTripCost(weightClass, distance int, earnWithFuel bool) int
+	CalculateTripCost(weightClass, distance, duration, staticDuration int, earnWithFuel bool) int
 	CalculateTripRevenue(tripCost int) int
+	TierBonusPercent(tier string) int
+	CalculateTierBonus(tripCost int, tier string) int
+	ScheduleMultiplier(schedules []PricingSchedule, at time.Time, isHoliday bool) float64
+	ApplyMultiplier(cost int, multiplier float64) int
 }
 
 type pricerClient struct{}
 
 func NewPricer() {
+	loc, err := time.LoadLocation(TIMEZONE)
+	if err != nil {
+		log.WithError(err).Fatalln("load pricer timezone")
+	}
+	tz = loc
+
 	pService = &pricerClient{}
 }
 
+// LocalTime - time in the pricing timezone
+func LocalTime(t time.Time) time.Time {
+	return t.In(tz)
+}
+
 func GetPricer() Pricing {
 	return pService
 }
 
 func (p *pricerClient) CalculateTripCost(
-	weightClass, distance int,
+	weightClass, distance, duration, staticDuration int,
 	earnWithFuel bool,
 ) int {
 	hourlyWage := config.Config.Pricer.HourlyWage
 	work := p.workToBeDone(weightClass, distance)
-	tripCost := p.nominalTripCost(work, hourlyWage) + p.earnWithRatingPoints(work, hourlyWage)
+	tripCost := p.nominalTripCost(work, hourlyWage) +
+		p.earnWithRatingPoints(work, hourlyWage) +
+		p.earnTrafficDelay(duration, staticDuration)
 	if earnWithFuel {
 		return tripCost + p.earnTripFuel(tripCost)
 	} else {
@@ -43,6 +86,16 @@ func (p *pricerClient) workToBeDone(weightClass, distance int) int {
 	return weightClass * distance / int(math.Pow10(6))
 }
 
+// Time stuck in traffic over the free-flow route duration(seconds)
+func (p *pricerClient) earnTrafficDelay(duration, staticDuration int) int {
+	delay := duration - staticDuration
+	if delay <= 0 {
+		return 0
+	}
+
+	return p.byminuteWage() * delay / 60
+}
+
 func (p *pricerClient) earnTripFuel(tripCost int) int {
 	return p.byminuteWage() * tripCost
 }
@@ -62,9 +115,92 @@ func (p *pricerClient) earnWithRatingPoints(
 func (p *pricerClient) CalculateTripRevenue(
 	tripCost int,
 ) int {
-	return (16 / 100) * tripCost
+	return tripCost * 16 / 100
+}
+
+// Higher tier couriers earn a platform funded bonus on every trip
+func (p *pricerClient) TierBonusPercent(tier string) int {
+	switch tier {
+	case GoldTier:
+		return 5
+	case SilverTier:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func (p *pricerClient) CalculateTierBonus(tripCost int, tier string) int {
+	return tripCost * p.TierBonusPercent(tier) / 100
 }
 
 func (p *pricerClient) byminuteWage() int {
 	return config.Config.Pricer.HourlyWage / 60
 }
+
+// ScheduleMultiplier - highest multiplier of the schedules active at the given time.
+// Schedules don't stack so a holiday night doesn't compound.
+func (p *pricerClient) ScheduleMultiplier(
+	schedules []PricingSchedule,
+	at time.Time,
+	isHoliday bool,
+) float64 {
+	multiplier := 1.0
+	local := LocalTime(at)
+
+	for _, s := range schedules {
+		if !p.isScheduleActive(s, local, isHoliday) {
+			continue
+		}
+
+		if s.Multiplier > multiplier {
+			multiplier = s.Multiplier
+		}
+	}
+
+	return multiplier
+}
+
+func (p *pricerClient) isScheduleActive(
+	s PricingSchedule,
+	local time.Time,
+	isHoliday bool,
+) bool {
+	switch s.Kind {
+	case NightSchedule:
+		if s.StartTime == nil || s.EndTime == nil {
+			return false
+		}
+	case WeekendSchedule:
+		if local.Weekday() != time.Saturday && local.Weekday() != time.Sunday {
+			return false
+		}
+	case HolidaySchedule:
+		if !isHoliday {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if s.StartTime == nil || s.EndTime == nil {
+		return true
+	}
+
+	now := minuteOfDay(local)
+	start := minuteOfDay(*s.StartTime)
+	end := minuteOfDay(*s.EndTime)
+	if start <= end {
+		return now >= start && now < end
+	}
+
+	return now >= start || now < end
+}
+
+func (p *pricerClient) ApplyMultiplier(cost int, multiplier float64) int {
+	return int(math.Round(float64(cost) * multiplier))
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
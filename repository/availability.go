@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+)
+
+// Why a courier status changed
+const (
+	transitionManual       = "MANUAL"
+	transitionTrip         = "TRIP"
+	transitionHeartbeat    = "HEARTBEAT"
+	transitionVerification = "VERIFICATION"
+)
+
+// SetStaleCouriersOffline - take couriers whose GPS pings stopped off
+// dispatch so trips aren't offered to phones nobody is holding
+func (c *CourierRepository) SetStaleCouriersOffline(now time.Time) (int, error) {
+	ctx := context.Background()
+
+	var stale []sqlc.SetStaleCouriersOfflineRow
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		stale, err = q.SetStaleCouriersOffline(ctx, sql.NullTime{
+			Time:  now.Add(-c.config.HeartbeatTimeout).UTC(),
+			Valid: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, courier := range stale {
+			if err := recordCourierTransition(
+				q,
+				courier.ID,
+				courier.FromStatus,
+				model.CourierStatusOffline.String(),
+				transitionHeartbeat,
+				nil,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		c.log.WithError(err).Errorf("set stale couriers offline")
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+// setCourierStatus - move the courier to status and record why.
+// Must run inside store.WithTx.
+func setCourierStatus(
+	q *sqlc.Queries,
+	courierID uuid.UUID,
+	status model.CourierStatus,
+	reason string,
+	tripID *uuid.UUID,
+) error {
+	ctx := context.Background()
+
+	current, err := q.GetCourierStatusForUpdate(ctx, courierID)
+	if err != nil {
+		return err
+	}
+	if current == status.String() {
+		return nil
+	}
+
+	if _, err := q.SetCourierStatus(ctx, sqlc.SetCourierStatusParams{
+		ID:     courierID,
+		Status: status.String(),
+	}); err != nil {
+		return err
+	}
+
+	return recordCourierTransition(q, courierID, current, status.String(), reason, tripID)
+}
+
+// releaseCourier - free the courier from their trip and put them back online
+// if the trip kept them busy.
+// Must run inside store.WithTx.
+func releaseCourier(q *sqlc.Queries, tripID, courierID uuid.UUID) error {
+	ctx := context.Background()
+
+	if _, err := q.UnassignCourierTrip(ctx, courierID); err != nil {
+		return err
+	}
+
+	current, err := q.GetCourierStatusForUpdate(ctx, courierID)
+	if err != nil {
+		return err
+	}
+	if current != model.CourierStatusBusy.String() {
+		return nil
+	}
+
+	return setCourierStatus(q, courierID, model.CourierStatusOnline, transitionTrip, &tripID)
+}
+
+// recordCourierTransition - log a status change for hours reporting.
+// Must run inside store.WithTx.
+func recordCourierTransition(
+	q *sqlc.Queries,
+	courierID uuid.UUID,
+	from, to, reason string,
+	tripID *uuid.UUID,
+) error {
+	if from == to {
+		return nil
+	}
+
+	return q.CreateCourierStatusTransition(context.Background(), sqlc.CreateCourierStatusTransitionParams{
+		CourierID:  courierID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		TripID:     nullUUID(tripID),
+	})
+}
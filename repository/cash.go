@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidCollectAmount    = errors.New("cash repository: collect amount can't be negative")
+	ErrCollectedAmountRequired = errors.New("cash repository: confirm the cash collected from the recipient")
+	ErrCollectedAmountMismatch = errors.New("cash repository: collected cash doesn't match what the recipient owes")
+	ErrNoCashToRemit           = errors.New("cash repository: courier isn't holding any cash")
+)
+
+type CashRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (c *CashRepository) Init(q *sqlc.Queries) {
+	c.store = q
+	c.log = internal.GetLogger()
+}
+
+// GetCouriersHoldingCash - couriers holding at least minimum in collected cash
+func (c *CashRepository) GetCouriersHoldingCash(minimum int) ([]*model.CourierCashBalance, error) {
+	var balances []*model.CourierCashBalance
+
+	holding, err := c.store.GetCouriersHoldingCash(context.Background(), int32(minimum))
+	if err != nil {
+		c.log.WithFields(logrus.Fields{
+			"minimum": minimum,
+		}).WithError(err).Errorf("get couriers holding cash")
+		return nil, err
+	}
+
+	for _, item := range holding {
+		balance := &model.CourierCashBalance{
+			CourierID: item.CourierID,
+			UserID:    item.UserID,
+			Name:      fmt.Sprintf("%s %s", item.FirstName, item.LastName),
+			Phone:     item.Phone,
+			CashHeld:  int(item.CashHeld),
+			Earnings:  int(item.Earnings),
+			UpdatedAt: &item.UpdatedAt,
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// collectTripCash - record the cash a courier collected on delivery. The
+// sender wallet is credited and the courier carries the cash as a liability
+// until it's netted against earnings or remitted. The courier has to confirm
+// exactly what the recipient owed.
+// Must run inside store.WithTx, after the trip moved to complete.
+func collectTripCash(q *sqlc.Queries, trip sqlc.Trip, collected *int) error {
+	ctx := context.Background()
+
+	if trip.CollectAmount == 0 {
+		return nil
+	}
+	if collected == nil {
+		return ErrCollectedAmountRequired
+	}
+	if *collected != int(trip.CollectAmount) {
+		return ErrCollectedAmountMismatch
+	}
+
+	courier, err := q.GetCourierByID(ctx, trip.CourierID.UUID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.SetTripCollectedAmount(ctx, sqlc.SetTripCollectedAmountParams{
+		ID:              trip.ID,
+		CollectedAmount: sql.NullInt32{Int32: trip.CollectAmount, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindCodCollected,
+		reference:   fmt.Sprintf("cod:%s", trip.ID),
+		tripID:      &trip.ID,
+		description: "Cash collected on delivery",
+		legs: []ledgerLeg{
+			userLeg(courier.UserID.UUID, model.WalletAccountCashHeld, -*collected),
+			userLeg(trip.UserID, model.WalletAccountCash, *collected),
+		},
+	}); err != nil {
+		return err
+	}
+
+	return netCashHeld(q, courier.UserID.UUID)
+}
+
+// netCashHeld - settle as much of the cash a courier holds as their
+// earnings cover. Must run inside store.WithTx.
+func netCashHeld(q *sqlc.Queries, userID uuid.UUID) error {
+	earnings, err := ledgerAccount(q, userID, model.WalletAccountEarnings.String())
+	if err != nil {
+		return err
+	}
+
+	cash, err := ledgerAccount(q, userID, model.WalletAccountCashHeld.String())
+	if err != nil {
+		return err
+	}
+
+	locked, err := q.LockLedgerAccounts(context.Background(), []uuid.UUID{earnings.ID, cash.ID})
+	if err != nil {
+		return err
+	}
+
+	var earningsBalance, cashBalance int
+	for _, account := range locked {
+		switch account.ID {
+		case earnings.ID:
+			earningsBalance = int(account.Balance)
+		case cash.ID:
+			cashBalance = int(account.Balance)
+		}
+	}
+
+	amount := -cashBalance
+	if earningsBalance < amount {
+		amount = earningsBalance
+	}
+	if amount <= 0 {
+		return nil
+	}
+
+	_, err = postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindCodSettlement,
+		reference:   fmt.Sprintf("cod-settlement:%s", uuid.New()),
+		description: "Cash on delivery netted from earnings",
+		legs: []ledgerLeg{
+			userLeg(userID, model.WalletAccountEarnings, -amount),
+			userLeg(userID, model.WalletAccountCashHeld, amount),
+		},
+	})
+
+	return err
+}
+
+// settleRemittance - clear courier cash once the remittance is paid. Anything
+// paid over what they hold, e.g after netting caught up, goes to earnings.
+func settleRemittance(q *sqlc.Queries, payment sqlc.Payment) error {
+	cash, err := lockUserAccount(q, payment.UserID, model.WalletAccountCashHeld)
+	if err != nil {
+		return err
+	}
+
+	amount := int(payment.Amount)
+	applied := -int(cash.Balance)
+	if applied > amount {
+		applied = amount
+	}
+	if applied < 0 {
+		applied = 0
+	}
+
+	_, err = postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindCodRemittance,
+		reference:   fmt.Sprintf("remittance:%s", payment.ID),
+		paymentID:   &payment.ID,
+		description: "Cash on delivery remittance",
+		legs: []ledgerLeg{
+			platformLeg(platformCash, -amount),
+			userLeg(payment.UserID, model.WalletAccountCashHeld, applied),
+			userLeg(payment.UserID, model.WalletAccountEarnings, amount-applied),
+		},
+	})
+
+	return err
+}
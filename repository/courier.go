@@ -3,22 +3,49 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/edwinlomolo/uzi-api/config"
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
 	"github.com/edwinlomolo/uzi-api/store/sqlc"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrCourierNotFound      = errors.New("courier repository: courier not found")
+	ErrInvalidCourierStatus = errors.New("courier repository: couriers can only go ONLINE, OFFLINE or ON_BREAK")
+	ErrCourierBusy          = errors.New("courier repository: finish the current trip first")
+	ErrGpsBatchTooLarge     = errors.New("courier repository: upload at most 500 GPS points at once")
+)
+
+const (
+	// tripDistanceWindow - how long after completion a trip distance is still
+	// recorded if flushing falls behind
+	tripDistanceWindow = 24 * time.Hour
+	// Most points a device can upload at once
+	maxGpsBatch = 500
+	// Device clocks running ahead by more are not trusted
+	gpsClockSkew = time.Minute
+)
+
 type CourierRepository struct {
-	store *sqlc.Queries
-	log   *logrus.Logger
+	store  *sqlc.Queries
+	index  internal.CourierIndex
+	config config.Courier
+	log    *logrus.Logger
 }
 
 func (c *CourierRepository) Init(q *sqlc.Queries) {
 	c.log = internal.GetLogger()
+	c.index = internal.GetCourierIndex()
+	c.config = config.Config.Courier
 	c.store = q
 }
 
@@ -117,6 +144,10 @@ func (c *CourierRepository) getCourierByUserID(userID uuid.UUID) (*model.Courier
 		UserID:    foundCourier.UserID.UUID,
 		Avatar:    c.getAvatar(foundCourier.ID),
 		ProductID: foundCourier.ProductID.UUID,
+		VehicleID: nullUUIDPtr(foundCourier.VehicleID),
+		Rating:    foundCourier.Rating.Float64,
+		Points:    int(foundCourier.Points),
+		Tier:      model.CourierTier(foundCourier.Tier),
 		Location:  model.ParsePostgisLocation(foundCourier.Location),
 	}, nil
 }
@@ -147,30 +178,245 @@ func (c *CourierRepository) GetCourierByUserID(userID uuid.UUID) (*model.Courier
 	return c.getCourierByUserID(userID)
 }
 
-func (c *CourierRepository) TrackCourierLocation(userID uuid.UUID, input model.GpsInput) error {
-	args := sqlc.TrackCourierLocationParams{
-		UserID: uuid.NullUUID{
-			UUID:  userID,
-			Valid: true,
-		},
-		Location: fmt.Sprintf(
-			"SRID=4326;POINT(%.8f %.8f)",
-			input.Lng, input.Lat,
-		),
+// TrackCourierLocation - record a GPS ping taken now
+func (c *CourierRepository) TrackCourierLocation(userID uuid.UUID, input model.GpsInput) (*internal.CourierPing, error) {
+	return c.TrackCourierLocations(userID, []*model.GpsPointInput{{
+		Lat:        input.Lat,
+		Lng:        input.Lng,
+		RecordedAt: time.Now(),
+		Accuracy:   input.Accuracy,
+		Speed:      input.Speed,
+		Heading:    input.Heading,
+	}})
+}
+
+// TrackCourierLocations - record GPS points buffered on the device in the live
+// location index, tied to the trip the courier is on. Points are stored in
+// recorded order once each, points from the future are dropped. Postgres gets
+// them on the next flush. Returns the freshest point when it moved the live
+// position.
+func (c *CourierRepository) TrackCourierLocations(
+	userID uuid.UUID,
+	points []*model.GpsPointInput,
+) (*internal.CourierPing, error) {
+	ctx := context.Background()
+
+	if len(points) > maxGpsBatch {
+		return nil, ErrGpsBatchTooLarge
+	}
+
+	courierID, err := c.index.GetCourierID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if courierID == nil {
+		courier, err := c.getCourierByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if courier == nil {
+			return nil, ErrCourierNotFound
+		}
+
+		courierID = &courier.ID
+		if err := c.index.SetCourierID(ctx, userID, courier.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	tripID, err := c.getCourierTrip(ctx, *courierID)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]*model.GpsPointInput, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RecordedAt.Before(sorted[j].RecordedAt)
+	})
+
+	latest := time.Now().Add(gpsClockSkew)
+	pings := make([]internal.CourierPing, 0, len(sorted))
+	for _, point := range sorted {
+		if point.RecordedAt.After(latest) {
+			continue
+		}
+		if n := len(pings); n > 0 && pings[n-1].RecordedAt.Equal(point.RecordedAt.UTC()) {
+			continue
+		}
+
+		pings = append(pings, internal.CourierPing{
+			CourierID:  *courierID,
+			TripID:     tripID,
+			Lat:        point.Lat,
+			Lng:        point.Lng,
+			Accuracy:   point.Accuracy,
+			Speed:      point.Speed,
+			Heading:    point.Heading,
+			RecordedAt: point.RecordedAt.UTC(),
+		})
+	}
+
+	moved, err := c.index.TrackCourier(ctx, *courierID, pings)
+	if err != nil || !moved {
+		return nil, err
+	}
+
+	return &pings[len(pings)-1], nil
+}
+
+// getCourierTrip - trip the courier is on. Index misses are read back from
+// couriers.trip_id in case the index write after assignment failed.
+func (c *CourierRepository) getCourierTrip(ctx context.Context, courierID uuid.UUID) (*uuid.UUID, error) {
+	tripID, err := c.index.GetCourierTrip(ctx, courierID)
+	if err != nil || tripID != nil {
+		return tripID, err
 	}
-	if _, updateErr := c.store.TrackCourierLocation(context.Background(), args); updateErr != nil {
+
+	found, err := c.store.GetCourierTripID(ctx, courierID)
+	if err != nil {
 		c.log.WithFields(logrus.Fields{
-			"courier_user_id": userID,
-		}).WithError(updateErr).Errorf("track courier location")
-		return updateErr
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier trip id")
+		return nil, err
+	}
+	if !found.Valid {
+		return nil, nil
+	}
+
+	if err := c.index.SetCourierTrip(ctx, courierID, &found.UUID); err != nil {
+		return nil, err
+	}
+
+	return &found.UUID, nil
+}
+
+// FlushCourierLocations - write live positions and breadcrumbs back to
+// Postgres, record the distance driven on trips completed since the last flush
+// and forget couriers that stopped pinging
+func (c *CourierRepository) FlushCourierLocations(now time.Time) (int, error) {
+	ctx := context.Background()
+
+	lateTrips, err := c.flushBreadcrumbs()
+	if err != nil {
+		return 0, err
+	}
+
+	// Every point recorded before now is in the history
+	if _, err := c.store.RecordTripDistances(ctx, sqlc.RecordTripDistancesParams{
+		CompletedAfter:  sql.NullTime{Time: now.Add(-tripDistanceWindow).UTC(), Valid: true},
+		CompletedBefore: sql.NullTime{Time: now.UTC(), Valid: true},
+		TripIds:         lateTrips,
+	}); err != nil {
+		c.log.WithError(err).Errorf("record trip distances")
+		return 0, err
+	}
+
+	pending, err := c.index.PendingLocations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var flushed int64
+	if len(pending) > 0 {
+		args := sqlc.FlushCourierLocationsParams{
+			Ids:       make([]uuid.UUID, len(pending)),
+			Locations: make([]string, len(pending)),
+			LocatedAt: make([]time.Time, len(pending)),
+		}
+		for i, item := range pending {
+			args.Ids[i] = item.ID
+			args.Locations[i] = fmt.Sprintf(
+				"SRID=4326;POINT(%.8f %.8f)",
+				item.Location.Lng, item.Location.Lat,
+			)
+			args.LocatedAt[i] = item.SeenAt.UTC()
+		}
+
+		flushed, err = c.store.FlushCourierLocations(ctx, args)
+		if err != nil {
+			c.log.WithFields(logrus.Fields{
+				"couriers": len(pending),
+			}).WithError(err).Errorf("flush courier locations")
+			return 0, err
+		}
+
+		if err := c.index.ClearPendingLocations(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := c.index.PruneStaleCouriers(ctx, now); err != nil {
+		return 0, err
+	}
+
+	return int(flushed), nil
+}
+
+// flushBreadcrumbs - append pending GPS fixes to the history. A batch left
+// over from a failed flush goes first, then everything pinged since. Returns
+// completed trips that got late points.
+func (c *CourierRepository) flushBreadcrumbs() ([]uuid.UUID, error) {
+	ctx := context.Background()
+	lateTrips := make([]uuid.UUID, 0)
+
+	for i := 0; i < 2; i++ {
+		pings, err := c.index.PendingBreadcrumbs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(pings) == 0 {
+			break
+		}
+
+		points, err := json.Marshal(pings)
+		if err != nil {
+			return nil, err
+		}
+		late, err := c.store.CreateCourierBreadcrumbs(ctx, points)
+		if err != nil {
+			c.log.WithFields(logrus.Fields{
+				"points": len(pings),
+			}).WithError(err).Errorf("flush courier breadcrumbs")
+			return nil, err
+		}
+		for _, tripID := range late {
+			lateTrips = append(lateTrips, tripID.UUID)
+		}
+
+		if err := c.index.ClearPendingBreadcrumbs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return lateTrips, nil
+}
+
+// EnsureBreadcrumbPartitions - history partitions for this month and the next
+func (c *CourierRepository) EnsureBreadcrumbPartitions(now time.Time) error {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, start := range []time.Time{month, month.AddDate(0, 1, 0)} {
+		if err := c.store.CreateCourierLocationsPartition(context.Background(), start); err != nil {
+			c.log.WithFields(logrus.Fields{
+				"month": start,
+			}).WithError(err).Errorf("create courier locations partition")
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (c *CourierRepository) GetCourierTrip(tripID uuid.UUID) (*model.Trip, error) {
-	tid := uuid.NullUUID{UUID: tripID, Valid: true}
-	trip, err := c.store.GetCourierTrip(context.Background(), tid)
+// GetCourierTrip - trip the courier is on
+func (c *CourierRepository) GetCourierTrip(courierID, tripID uuid.UUID) (*model.Trip, error) {
+	ctx := context.Background()
+
+	trip, err := c.store.GetCourierTrip(ctx, sqlc.GetCourierTripParams{
+		ID:        tripID,
+		CourierID: uuid.NullUUID{UUID: courierID, Valid: true},
+	})
 	if err == sql.ErrNoRows {
 		return nil, ErrCourierTripNotFound
 	} else if err != nil {
@@ -187,22 +433,131 @@ func (c *CourierRepository) GetCourierTrip(tripID uuid.UUID) (*model.Trip, error
 	}, nil
 }
 
-func (c *CourierRepository) UpdateCourierStatus(userID uuid.UUID, status model.CourierStatus) (bool, error) {
-	args := sqlc.SetCourierStatusParams{
-		Status: status.String(),
-		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+// UpdateCourierStatus - couriers switch between ONLINE, ON_BREAK and OFFLINE.
+// Going ONLINE is refused with the reasons the courier is not eligible for
+// trips. BUSY comes and goes with trips.
+func (c *CourierRepository) UpdateCourierStatus(
+	userID uuid.UUID,
+	status model.CourierStatus,
+) (*model.CourierStatusUpdate, error) {
+	ctx := context.Background()
+
+	switch status {
+	case model.CourierStatusOnline, model.CourierStatusOnBreak, model.CourierStatusOffline:
+	default:
+		return nil, ErrInvalidCourierStatus
 	}
-	if _, setErr := c.store.SetCourierStatus(
-		context.Background(),
-		args); setErr != nil {
+
+	courier, err := c.store.GetCourierEligibility(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil, ErrCourierNotFound
+	} else if err != nil {
+		c.log.WithFields(logrus.Fields{
+			"courier_user_id": userID,
+			"error":           err,
+		}).Errorf("get courier eligibility")
+		return nil, err
+	}
+	if courier.Status == model.CourierStatusBusy.String() {
+		return nil, ErrCourierBusy
+	}
+
+	update := &model.CourierStatusUpdate{
+		Status:  model.CourierStatus(courier.Status),
+		Reasons: make([]model.CourierIneligibility, 0),
+	}
+
+	if status == model.CourierStatusOnline {
+		reasons, err := c.onlineIneligibility(userID, courier)
+		if err != nil {
+			return nil, err
+		}
+		update.Reasons = append(update.Reasons, reasons...)
+	}
+	// Onboarding couriers stay onboarding until they're verified
+	if len(update.Reasons) > 0 || courier.Status == model.CourierStatusOnboarding.String() {
+		return update, nil
+	}
+
+	if setErr := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		// A trip may have been assigned since the eligibility read
+		current, err := q.GetCourierStatusForUpdate(ctx, courier.ID)
+		if err != nil {
+			return err
+		}
+		if current == model.CourierStatusBusy.String() {
+			return ErrCourierBusy
+		}
+
+		return setCourierStatus(q, courier.ID, status, transitionManual, nil)
+	}); errors.Is(setErr, ErrCourierBusy) {
+		return nil, setErr
+	} else if setErr != nil {
 		c.log.WithFields(logrus.Fields{
 			"courier_user_id": userID,
 			"error":           setErr,
 		}).Errorf("update courier status")
-		return false, setErr
+		return nil, setErr
+	}
+
+	update.Status = status
+	update.Updated = true
+
+	return update, nil
+}
+
+// onlineIneligibility - what keeps a courier from taking trips
+func (c *CourierRepository) onlineIneligibility(
+	userID uuid.UUID,
+	courier sqlc.GetCourierEligibilityRow,
+) ([]model.CourierIneligibility, error) {
+	reasons := make([]model.CourierIneligibility, 0)
+	now := time.Now().UTC()
+
+	if !courier.Verified.Bool {
+		reasons = append(reasons, model.CourierIneligibilityNotVerified)
+	}
+
+	if !courier.VehicleID.Valid {
+		reasons = append(reasons, model.CourierIneligibilityNoVehicle)
+	} else if courier.VehicleStatus.String != model.VehicleStatusApproved.String() || !courier.FleetApproved {
+		reasons = append(reasons, model.CourierIneligibilityVehicleNotApproved)
+	}
+
+	expired, err := c.store.CountExpiredCourierDocuments(
+		context.Background(),
+		sqlc.CountExpiredCourierDocumentsParams{
+			UserID: uuid.NullUUID{UUID: userID, Valid: true},
+			Now:    sql.NullTime{Time: now, Valid: true},
+		},
+	)
+	if err != nil {
+		c.log.WithFields(logrus.Fields{
+			"courier_user_id": userID,
+			"error":           err,
+		}).Errorf("count expired courier documents")
+		return nil, err
+	}
+	if expired > 0 {
+		reasons = append(reasons, model.CourierIneligibilityDocumentsExpired)
+	}
+
+	// Pings reach Postgres on the next flush, the index has the latest
+	tracked, err := c.index.GetCourier(context.Background(), courier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tracked != nil && tracked.SeenAt.After(courier.LocationUpdatedAt.Time) {
+		courier.LocationUpdatedAt = sql.NullTime{Time: tracked.SeenAt, Valid: true}
+	}
+
+	if !courier.LocationUpdatedAt.Valid {
+		reasons = append(reasons, model.CourierIneligibilityNoLocation)
+	} else if now.Sub(courier.LocationUpdatedAt.Time) > c.config.HeartbeatTimeout {
+		reasons = append(reasons, model.CourierIneligibilityStaleLocation)
 	}
 
-	return true, nil
+	return reasons, nil
 }
 
 func (c *CourierRepository) GetCourierProduct(productID uuid.UUID) (*model.Product, error) {
@@ -248,6 +603,10 @@ func (c *CourierRepository) GetCourierByID(courierID uuid.UUID) (*model.Courier,
 		TripID:    &courier.TripID.UUID,
 		UserID:    courier.UserID.UUID,
 		ProductID: courier.ProductID.UUID,
+		VehicleID: nullUUIDPtr(courier.VehicleID),
+		Rating:    courier.Rating.Float64,
+		Points:    int(courier.Points),
+		Tier:      model.CourierTier(courier.Tier),
 		Location:  model.ParsePostgisLocation(courier.Location),
 		Avatar:    c.getAvatar(courierID),
 	}, nil
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const platformBonus = "PLATFORM_BONUS"
+
+var (
+	ErrInvalidAdjustmentAmount = errors.New("earnings: adjustment amount must be positive")
+)
+
+type EarningsRepository struct {
+	p     internal.Pricing
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (e *EarningsRepository) Init(q *sqlc.Queries) {
+	e.p = internal.GetPricer()
+	e.store = q
+	e.log = internal.GetLogger()
+}
+
+// RecordTripEarnings - credit the courier the trip fare less platform commission,
+// and the fleet owner their share of it if the courier rides for a fleet.
+// Safe to call more than once per trip.
+func (e *EarningsRepository) RecordTripEarnings(trip model.Trip) error {
+	if trip.CourierID == nil || trip.CourierID.String() == internal.ZERO_UUID || trip.Cost <= 0 {
+		return nil
+	}
+
+	courier, err := e.getCourier(*trip.CourierID)
+	if err != nil {
+		return err
+	}
+	courierUserID := courier.UserID.UUID
+
+	commission := e.p.CalculateTripRevenue(trip.Cost)
+	tierBonus := e.p.CalculateTierBonus(trip.Cost, courier.Tier)
+
+	err = store.WithTx(context.Background(), func(q *sqlc.Queries) error {
+		if _, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindTripFare,
+			reference:   fmt.Sprintf("fare:%s", trip.ID),
+			tripID:      &trip.ID,
+			description: "Trip fare",
+			legs: []ledgerLeg{
+				userLeg(courierUserID, model.WalletAccountEarnings, trip.Cost),
+				platformLeg(platformRevenue, -trip.Cost),
+			},
+		}); err != nil {
+			return err
+		}
+
+		if commission > 0 {
+			if _, err := postLedger(q, ledgerPosting{
+				kind:        model.LedgerTransactionKindCommission,
+				reference:   fmt.Sprintf("commission:%s", trip.ID),
+				tripID:      &trip.ID,
+				description: "Platform commission",
+				legs: []ledgerLeg{
+					userLeg(courierUserID, model.WalletAccountEarnings, -commission),
+					platformLeg(platformRevenue, commission),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := postFleetShare(q, trip.ID, courier, trip.Cost-commission); err != nil {
+			return err
+		}
+
+		if tierBonus > 0 {
+			if _, err := postLedger(q, ledgerPosting{
+				kind:        model.LedgerTransactionKindBonus,
+				reference:   fmt.Sprintf("tier-bonus:%s", trip.ID),
+				tripID:      &trip.ID,
+				description: fmt.Sprintf("%s tier bonus", courier.Tier),
+				legs: []ledgerLeg{
+					userLeg(courierUserID, model.WalletAccountEarnings, tierBonus),
+					platformLeg(platformBonus, -tierBonus),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return netCashHeld(q, courierUserID)
+	})
+	if err != nil {
+		e.log.WithFields(logrus.Fields{
+			"trip_id":    trip.ID,
+			"courier_id": trip.CourierID,
+		}).WithError(err).Errorf("record trip earnings")
+		return err
+	}
+
+	return nil
+}
+
+// AdjustCourierEarnings - manual bonus or penalty on a courier earnings
+func (e *EarningsRepository) AdjustCourierEarnings(input model.CourierEarningsAdjustmentInput) error {
+	if input.Amount <= 0 {
+		return ErrInvalidAdjustmentAmount
+	}
+
+	courier, err := e.getCourier(input.CourierID)
+	if err != nil {
+		return err
+	}
+	courierUserID := courier.UserID.UUID
+
+	posting := ledgerPosting{
+		reference:   fmt.Sprintf("adjustment:%s", uuid.New()),
+		tripID:      input.TripID,
+		description: input.Reason,
+	}
+	switch input.Kind {
+	case model.EarningsAdjustmentBonus:
+		posting.kind = model.LedgerTransactionKindBonus
+		posting.legs = []ledgerLeg{
+			userLeg(courierUserID, model.WalletAccountEarnings, input.Amount),
+			platformLeg(platformBonus, -input.Amount),
+		}
+	case model.EarningsAdjustmentPenalty:
+		posting.kind = model.LedgerTransactionKindPenalty
+		posting.legs = []ledgerLeg{
+			userLeg(courierUserID, model.WalletAccountEarnings, -input.Amount),
+			platformLeg(platformRevenue, input.Amount),
+		}
+	}
+
+	err = store.WithTx(context.Background(), func(q *sqlc.Queries) error {
+		_, err := postLedger(q, posting)
+		return err
+	})
+	if err != nil {
+		e.log.WithFields(logrus.Fields{
+			"courier_id": input.CourierID,
+			"kind":       input.Kind,
+			"amount":     input.Amount,
+		}).WithError(err).Errorf("adjust courier earnings")
+		return err
+	}
+
+	return nil
+}
+
+// GetCourierEarnings - earnings statement for the current day, week or month
+func (e *EarningsRepository) GetCourierEarnings(
+	courierUserID uuid.UUID,
+	period model.EarningsPeriod,
+) (*model.CourierEarnings, error) {
+	start, end := earningsWindow(period, time.Now())
+	owner := uuid.NullUUID{UUID: courierUserID, Valid: true}
+
+	earnings := &model.CourierEarnings{
+		Period: period,
+		Start:  start,
+		End:    end,
+		Total:  &model.EarningsTotal{Start: start},
+	}
+
+	account, err := e.store.GetUserLedgerAccount(context.Background(), sqlc.GetUserLedgerAccountParams{
+		UserID: owner,
+		Kind:   model.WalletAccountEarnings.String(),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		e.log.WithFields(logrus.Fields{
+			"courier_user_id": courierUserID,
+		}).WithError(err).Errorf("get courier earnings account")
+		return nil, err
+	}
+	earnings.Balance = int(account.Balance)
+
+	entries, err := e.store.GetUserLedgerEntriesBetween(context.Background(), sqlc.GetUserLedgerEntriesBetweenParams{
+		UserID:  owner,
+		Kind:    model.WalletAccountEarnings.String(),
+		StartAt: start.UTC(),
+		EndAt:   end.UTC(),
+	})
+	if err != nil {
+		e.log.WithFields(logrus.Fields{
+			"courier_user_id": courierUserID,
+			"period":          period,
+		}).WithError(err).Errorf("get courier earnings entries")
+		return nil, err
+	}
+
+	days := make(map[time.Time]*model.EarningsTotal)
+	weeks := make(map[time.Time]*model.EarningsTotal)
+	trips := make(map[uuid.UUID]*model.TripEarnings)
+
+	for _, entry := range entries {
+		kind := model.LedgerTransactionKind(entry.Kind)
+		amount := int(entry.Amount)
+		day := startOfDay(internal.LocalTime(entry.CreatedAt))
+		week := startOfWeek(day)
+
+		addEarningsTotal(earnings.Total, kind, amount)
+
+		if _, ok := days[day]; !ok {
+			days[day] = &model.EarningsTotal{Start: day}
+			earnings.Daily = append(earnings.Daily, days[day])
+		}
+		addEarningsTotal(days[day], kind, amount)
+
+		if _, ok := weeks[week]; !ok {
+			weeks[week] = &model.EarningsTotal{Start: week}
+			earnings.Weekly = append(earnings.Weekly, weeks[week])
+		}
+		addEarningsTotal(weeks[week], kind, amount)
+
+		if !entry.TripID.Valid {
+			continue
+		}
+		if _, ok := trips[entry.TripID.UUID]; !ok {
+			trips[entry.TripID.UUID] = &model.TripEarnings{TripID: entry.TripID.UUID}
+			earnings.Trips = append(earnings.Trips, trips[entry.TripID.UUID])
+		}
+		addTripEarnings(trips[entry.TripID.UUID], kind, amount, entry.CreatedAt)
+	}
+
+	return earnings, nil
+}
+
+func (e *EarningsRepository) getCourier(courierID uuid.UUID) (*sqlc.GetCourierByIDRow, error) {
+	courier, err := e.store.GetCourierByID(context.Background(), courierID)
+	if err != nil {
+		e.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get earnings courier")
+		return nil, err
+	}
+
+	return &courier, nil
+}
+
+// postFleetShare - move the fleet cut of what the rider earned on the trip to
+// the fleet owner. Only trips ridden on one of the fleet's vehicles pay the
+// fleet share.
+// Must run inside store.WithTx.
+func postFleetShare(q *sqlc.Queries, tripID uuid.UUID, courier *sqlc.GetCourierByIDRow, earned int) error {
+	ctx := context.Background()
+
+	if earned <= 0 {
+		return nil
+	}
+
+	tripFleet, err := q.GetTripFleet(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	if !tripFleet.FleetID.Valid || !tripFleet.FleetVehicle {
+		return nil
+	}
+
+	fleet, err := q.GetFleet(ctx, tripFleet.FleetID.UUID)
+	if err != nil {
+		return err
+	}
+
+	share := earned * int(100-fleet.RiderShare) / 100
+	if share <= 0 || fleet.OwnerID == courier.UserID.UUID {
+		return nil
+	}
+
+	_, err = postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindFleetShare,
+		reference:   fmt.Sprintf("fleet-share:%s", tripID),
+		tripID:      &tripID,
+		description: fmt.Sprintf("%s fleet share", fleet.Name),
+		legs: []ledgerLeg{
+			userLeg(courier.UserID.UUID, model.WalletAccountEarnings, -share),
+			userLeg(fleet.OwnerID, model.WalletAccountEarnings, share),
+		},
+	})
+	return err
+}
+
+// Deductions are reported as positive amounts, net is what the courier keeps.
+// Payouts and cash on delivery netting move earnings out of the account so
+// they don't count towards net.
+func addEarningsTotal(total *model.EarningsTotal, kind model.LedgerTransactionKind, amount int) {
+	switch kind {
+	case model.LedgerTransactionKindPayout, model.LedgerTransactionKindPayoutReversal:
+		total.Payouts -= amount
+		return
+	case model.LedgerTransactionKindCodSettlement, model.LedgerTransactionKindCodRemittance:
+		total.CashSettled -= amount
+		return
+	case model.LedgerTransactionKindTripFare:
+		total.Fares += amount
+	case model.LedgerTransactionKindCommission:
+		total.Commission -= amount
+	case model.LedgerTransactionKindFleetShare:
+		// Riders give the fleet share, fleet owners receive it
+		if amount > 0 {
+			total.FleetIncome += amount
+		} else {
+			total.FleetShare -= amount
+		}
+	case model.LedgerTransactionKindTip:
+		total.Tips += amount
+	case model.LedgerTransactionKindBonus:
+		total.Bonuses += amount
+	case model.LedgerTransactionKindPenalty:
+		total.Penalties -= amount
+	}
+	total.Net += amount
+}
+
+func addTripEarnings(
+	trip *model.TripEarnings,
+	kind model.LedgerTransactionKind,
+	amount int,
+	at time.Time,
+) {
+	switch kind {
+	case model.LedgerTransactionKindTripFare:
+		trip.Fare += amount
+		trip.CompletedAt = &at
+	case model.LedgerTransactionKindCommission:
+		trip.Commission -= amount
+	case model.LedgerTransactionKindFleetShare:
+		if amount > 0 {
+			trip.FleetIncome += amount
+		} else {
+			trip.FleetShare -= amount
+		}
+	case model.LedgerTransactionKindTip:
+		trip.Tips += amount
+	case model.LedgerTransactionKindBonus:
+		trip.Bonuses += amount
+	case model.LedgerTransactionKindPenalty:
+		trip.Penalties -= amount
+	}
+	trip.Net += amount
+}
+
+// earningsWindow - local calendar period containing now. Weeks start on Monday.
+func earningsWindow(period model.EarningsPeriod, now time.Time) (time.Time, time.Time) {
+	today := startOfDay(internal.LocalTime(now))
+
+	switch period {
+	case model.EarningsPeriodWeek:
+		start := startOfWeek(today)
+		return start, start.AddDate(0, 0, 7)
+	case model.EarningsPeriodMonth:
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return today, today.AddDate(0, 0, 1)
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,546 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidFleet            = errors.New("fleet repository: fleet needs a name, an owner and a rider share between 0 and 100")
+	ErrFleetNotFound           = errors.New("fleet repository: fleet not found")
+	ErrFleetUserNotFound       = errors.New("fleet repository: user not found")
+	ErrCourierInAnotherFleet   = errors.New("fleet repository: courier already rides for another fleet")
+	ErrCourierNotInFleet       = errors.New("fleet repository: courier does not ride for this fleet")
+	ErrFleetCourierOnTrip      = errors.New("fleet repository: courier is on a trip")
+	ErrVehicleNotInFleet       = errors.New("fleet repository: vehicle is not in this fleet")
+	ErrFleetVehicleRejected    = errors.New("fleet repository: vehicle was rejected")
+	ErrFleetInvitationNotFound = errors.New("fleet repository: fleet invitation not found")
+	ErrFleetInvitationClosed   = errors.New("fleet repository: fleet invitation was already answered")
+)
+
+type FleetRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (f *FleetRepository) Init(q *sqlc.Queries) {
+	f.store = q
+	f.log = internal.GetLogger()
+}
+
+// CreateFleet - the owner receives the fleet share of rider earnings and
+// manages the fleet
+func (f *FleetRepository) CreateFleet(input model.FleetInput) (*model.Fleet, error) {
+	ctx := context.Background()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || input.RiderShare < 0 || input.RiderShare > 100 {
+		return nil, ErrInvalidFleet
+	}
+
+	var fleet sqlc.Fleet
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.FindUserByID(ctx, input.OwnerID); err == sql.ErrNoRows {
+			return ErrFleetUserNotFound
+		} else if err != nil {
+			return err
+		}
+
+		var err error
+		fleet, err = q.CreateFleet(ctx, sqlc.CreateFleetParams{
+			Name:       name,
+			OwnerID:    input.OwnerID,
+			RiderShare: int32(input.RiderShare),
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.AddFleetManager(ctx, sqlc.AddFleetManagerParams{
+			FleetID: fleet.ID,
+			UserID:  input.OwnerID,
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, ErrFleetUserNotFound) {
+			f.log.WithFields(logrus.Fields{
+				"owner_id": input.OwnerID,
+			}).WithError(err).Errorf("create fleet")
+		}
+		return nil, err
+	}
+
+	return parseFleet(fleet), nil
+}
+
+// SetFleetRiderShare - percent of trip earnings net of commission riders keep.
+// Applies to trips completed from now on.
+func (f *FleetRepository) SetFleetRiderShare(fleetID uuid.UUID, riderShare int) (*model.Fleet, error) {
+	if riderShare < 0 || riderShare > 100 {
+		return nil, ErrInvalidFleet
+	}
+
+	fleet, err := f.store.SetFleetRiderShare(context.Background(), sqlc.SetFleetRiderShareParams{
+		ID:         fleetID,
+		RiderShare: int32(riderShare),
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrFleetNotFound
+	} else if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id":    fleetID,
+			"rider_share": riderShare,
+		}).WithError(err).Errorf("set fleet rider share")
+		return nil, err
+	}
+
+	return parseFleet(fleet), nil
+}
+
+func (f *FleetRepository) AddFleetManager(fleetID, userID uuid.UUID) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.GetFleet(ctx, fleetID); err == sql.ErrNoRows {
+			return ErrFleetNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if _, err := q.FindUserByID(ctx, userID); err == sql.ErrNoRows {
+			return ErrFleetUserNotFound
+		} else if err != nil {
+			return err
+		}
+
+		return q.AddFleetManager(ctx, sqlc.AddFleetManagerParams{
+			FleetID: fleetID,
+			UserID:  userID,
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, ErrFleetNotFound) && !errors.Is(err, ErrFleetUserNotFound) {
+			f.log.WithFields(logrus.Fields{
+				"fleet_id": fleetID,
+				"user_id":  userID,
+			}).WithError(err).Errorf("add fleet manager")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (f *FleetRepository) IsFleetManager(fleetID, userID uuid.UUID) (bool, error) {
+	isManager, err := f.store.IsFleetManager(context.Background(), sqlc.IsFleetManagerParams{
+		FleetID: fleetID,
+		UserID:  userID,
+	})
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id": fleetID,
+			"user_id":  userID,
+		}).WithError(err).Errorf("is fleet manager")
+		return false, err
+	}
+
+	return isManager, nil
+}
+
+// OwnsFleet - fleet owners earn the fleet share
+func (f *FleetRepository) OwnsFleet(userID uuid.UUID) (bool, error) {
+	owns, err := f.store.OwnsFleet(context.Background(), userID)
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("owns fleet")
+		return false, err
+	}
+
+	return owns, nil
+}
+
+func (f *FleetRepository) GetManagedFleets(userID uuid.UUID) ([]*model.Fleet, error) {
+	fleets := make([]*model.Fleet, 0)
+
+	found, err := f.store.GetManagedFleets(context.Background(), userID)
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get managed fleets")
+		return nil, err
+	}
+
+	for _, item := range found {
+		fleets = append(fleets, parseFleet(item))
+	}
+
+	return fleets, nil
+}
+
+// InviteFleetCourier - invite a courier into the fleet by their signed up
+// phone. They ride for the fleet once they accept.
+func (f *FleetRepository) InviteFleetCourier(managerID, fleetID uuid.UUID, phone string) error {
+	ctx := context.Background()
+
+	courier, err := f.store.GetCourierByPhone(ctx, strings.TrimSpace(phone))
+	if err == sql.ErrNoRows {
+		return ErrCourierNotFound
+	} else if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id": fleetID,
+			"phone":    phone,
+		}).WithError(err).Errorf("get fleet courier by phone")
+		return err
+	}
+
+	if courier.FleetID.Valid {
+		if courier.FleetID.UUID == fleetID {
+			return nil
+		}
+		return ErrCourierInAnotherFleet
+	}
+
+	if _, err := f.store.CreateFleetInvitation(ctx, sqlc.CreateFleetInvitationParams{
+		FleetID:   fleetID,
+		CourierID: courier.ID,
+		InvitedBy: uuid.NullUUID{UUID: managerID, Valid: true},
+	}); err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id":   fleetID,
+			"courier_id": courier.ID,
+		}).WithError(err).Errorf("invite fleet courier")
+		return err
+	}
+
+	return nil
+}
+
+// GetFleetInvitations - fleets waiting on the courier to join them
+func (f *FleetRepository) GetFleetInvitations(userID uuid.UUID) ([]*model.FleetInvitation, error) {
+	invitations := make([]*model.FleetInvitation, 0)
+
+	found, err := f.store.GetCourierFleetInvitations(context.Background(), uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get courier fleet invitations")
+		return nil, err
+	}
+
+	for _, item := range found {
+		invitations = append(invitations, &model.FleetInvitation{
+			ID:         item.ID,
+			FleetID:    item.FleetID,
+			FleetName:  item.FleetName,
+			RiderShare: int(item.RiderShare),
+			Status:     model.FleetInvitationStatus(item.Status),
+			CreatedAt:  &item.CreatedAt,
+		})
+	}
+
+	return invitations, nil
+}
+
+// RespondFleetInvitation - the courier joins the fleet when they accept.
+// Trips they already have stay with the fleet they got them under.
+func (f *FleetRepository) RespondFleetInvitation(userID, invitationID uuid.UUID, accept bool) error {
+	ctx := context.Background()
+
+	status := model.FleetInvitationStatusDeclined
+	if accept {
+		status = model.FleetInvitationStatusAccepted
+	}
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		invitation, err := q.GetCourierFleetInvitationForUpdate(ctx, sqlc.GetCourierFleetInvitationForUpdateParams{
+			ID:     invitationID,
+			UserID: uuid.NullUUID{UUID: userID, Valid: true},
+		})
+		if err == sql.ErrNoRows {
+			return ErrFleetInvitationNotFound
+		} else if err != nil {
+			return err
+		}
+		if invitation.Status != model.FleetInvitationStatusPending.String() {
+			return ErrFleetInvitationClosed
+		}
+
+		if accept {
+			courier, err := q.GetCourierOnboarding(ctx, invitation.CourierID)
+			if err != nil {
+				return err
+			}
+			if courier.FleetID.Valid && courier.FleetID.UUID != invitation.FleetID {
+				return ErrCourierInAnotherFleet
+			}
+
+			if _, err := q.SetCourierFleet(ctx, sqlc.SetCourierFleetParams{
+				ID:      invitation.CourierID,
+				FleetID: uuid.NullUUID{UUID: invitation.FleetID, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+
+		_, err = q.RespondFleetInvitation(ctx, sqlc.RespondFleetInvitationParams{
+			ID:     invitation.ID,
+			Status: status.String(),
+		})
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrFleetInvitationNotFound) &&
+			!errors.Is(err, ErrFleetInvitationClosed) &&
+			!errors.Is(err, ErrCourierInAnotherFleet) {
+			f.log.WithFields(logrus.Fields{
+				"user_id":       userID,
+				"invitation_id": invitationID,
+				"status":        status,
+			}).WithError(err).Errorf("respond fleet invitation")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveFleetCourier - let the courier go. If they were riding a fleet
+// vehicle they need to register or switch to their own before going online.
+func (f *FleetRepository) RemoveFleetCourier(fleetID, courierID uuid.UUID) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		courier, err := q.GetCourierOnboarding(ctx, courierID)
+		if err == sql.ErrNoRows {
+			return ErrCourierNotFound
+		} else if err != nil {
+			return err
+		}
+		if courier.FleetID.UUID != fleetID {
+			return ErrCourierNotInFleet
+		}
+		if courier.TripID.Valid {
+			return ErrFleetCourierOnTrip
+		}
+
+		if _, err := q.SetCourierFleet(ctx, sqlc.SetCourierFleetParams{ID: courierID}); err != nil {
+			return err
+		}
+
+		if !courier.VehicleID.Valid {
+			return nil
+		}
+		vehicle, err := q.GetVehicle(ctx, courier.VehicleID.UUID)
+		if err != nil {
+			return err
+		}
+		if vehicle.FleetID.UUID != fleetID {
+			return nil
+		}
+
+		if _, err := q.ClearCourierVehicle(ctx, courierID); err != nil {
+			return err
+		}
+
+		return syncCourierVerification(q, courierID)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrCourierNotFound) &&
+			!errors.Is(err, ErrCourierNotInFleet) &&
+			!errors.Is(err, ErrFleetCourierOnTrip) {
+			f.log.WithFields(logrus.Fields{
+				"fleet_id":   fleetID,
+				"courier_id": courierID,
+			}).WithError(err).Errorf("remove fleet courier")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetFleetRiders - live status of every courier riding for the fleet
+func (f *FleetRepository) GetFleetRiders(fleetID uuid.UUID) ([]*model.FleetRider, error) {
+	riders := make([]*model.FleetRider, 0)
+
+	found, err := f.store.GetFleetRiders(context.Background(), uuid.NullUUID{UUID: fleetID, Valid: true})
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id": fleetID,
+		}).WithError(err).Errorf("get fleet riders")
+		return nil, err
+	}
+
+	for _, item := range found {
+		rider := &model.FleetRider{
+			CourierID: item.CourierID,
+			UserID:    item.UserID.UUID,
+			Name:      strings.TrimSpace(item.FirstName + " " + item.LastName),
+			Phone:     item.Phone,
+			Status:    model.CourierStatus(item.Status),
+			Location:  model.ParsePostgisLocation(item.Location),
+			TripID:    nullUUIDPtr(item.TripID),
+			VehicleID: nullUUIDPtr(item.VehicleID),
+		}
+		if item.LocationUpdatedAt.Valid {
+			rider.LastSeenAt = &item.LocationUpdatedAt.Time
+		}
+		riders = append(riders, rider)
+	}
+
+	return riders, nil
+}
+
+// GetFleetTrips - latest trips couriers got while riding for the fleet
+func (f *FleetRepository) GetFleetTrips(fleetID uuid.UUID, limit, offset int) ([]*model.Trip, error) {
+	trips := make([]*model.Trip, 0)
+
+	found, err := f.store.GetFleetTrips(context.Background(), sqlc.GetFleetTripsParams{
+		FleetID: uuid.NullUUID{UUID: fleetID, Valid: true},
+		Limit:   int32(limit),
+		Offset:  int32(offset),
+	})
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id": fleetID,
+		}).WithError(err).Errorf("get fleet trips")
+		return nil, err
+	}
+
+	for _, item := range found {
+		trips = append(trips, &model.Trip{
+			ID:              item.ID,
+			Status:          model.TripStatus(item.Status),
+			CourierID:       &item.CourierID.UUID,
+			UserID:          item.UserID,
+			ProductID:       item.ProductID,
+			Cost:            int(item.Cost),
+			Distance:        int(item.Distance),
+			Duration:        int(item.Duration),
+			Discount:        int(item.Discount),
+			CollectAmount:   int(item.CollectAmount),
+			CreatedAt:       &item.CreatedAt,
+			StartLocation:   model.ParsePostgisLocation(item.StartLocation),
+			EndLocation:     model.ParsePostgisLocation(item.EndLocation),
+			ConfirmedPickup: model.ParsePostgisLocation(item.ConfirmedPickup),
+		})
+	}
+
+	return trips, nil
+}
+
+// GetFleetPendingVehicles - fleet vehicles waiting on a manager to approve the
+// rider using them
+func (f *FleetRepository) GetFleetPendingVehicles(fleetID uuid.UUID) ([]*model.Vehicle, error) {
+	vehicles := make([]*model.Vehicle, 0)
+
+	pending, err := f.store.GetFleetPendingVehicles(context.Background(), uuid.NullUUID{UUID: fleetID, Valid: true})
+	if err != nil {
+		f.log.WithFields(logrus.Fields{
+			"fleet_id": fleetID,
+		}).WithError(err).Errorf("get fleet pending vehicles")
+		return nil, err
+	}
+
+	for _, item := range pending {
+		vehicles = append(vehicles, parseVehicle(item.Vehicle, item.ActiveVehicleID))
+	}
+
+	return vehicles, nil
+}
+
+// ReviewFleetVehicle - fleet manager approval of a rider using a fleet
+// vehicle. Admins still review the vehicle itself.
+func (f *FleetRepository) ReviewFleetVehicle(
+	managerID, fleetID, vehicleID uuid.UUID,
+	status model.VehicleStatus,
+	reason string,
+) (*model.Vehicle, error) {
+	ctx := context.Background()
+
+	if status == model.VehicleStatusRejected && strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	var (
+		reviewed sqlc.Vehicle
+		active   uuid.NullUUID
+	)
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		vehicle, err := q.GetVehicleForUpdate(ctx, vehicleID)
+		if err == sql.ErrNoRows {
+			return ErrVehicleNotFound
+		} else if err != nil {
+			return err
+		}
+		if vehicle.FleetID.UUID != fleetID {
+			return ErrVehicleNotInFleet
+		}
+		if vehicle.Status == model.VehicleStatusRejected.String() {
+			return ErrFleetVehicleRejected
+		}
+
+		if status == model.VehicleStatusRejected {
+			reviewed, err = q.ReviewVehicle(ctx, sqlc.ReviewVehicleParams{
+				ID:              vehicle.ID,
+				Status:          status.String(),
+				ReviewedBy:      uuid.NullUUID{UUID: managerID, Valid: true},
+				RejectionReason: sql.NullString{String: strings.TrimSpace(reason), Valid: true},
+			})
+		} else {
+			reviewed, err = q.ApproveFleetVehicle(ctx, sqlc.ApproveFleetVehicleParams{
+				ID:              vehicle.ID,
+				FleetApprovedBy: uuid.NullUUID{UUID: managerID, Valid: true},
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		courier, err := q.GetCourierOnboarding(ctx, vehicle.CourierID)
+		if err != nil {
+			return err
+		}
+		active = courier.VehicleID
+		if active.UUID != vehicle.ID {
+			return nil
+		}
+
+		return syncCourierVerification(q, vehicle.CourierID)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrVehicleNotFound) &&
+			!errors.Is(err, ErrVehicleNotInFleet) &&
+			!errors.Is(err, ErrFleetVehicleRejected) {
+			f.log.WithFields(logrus.Fields{
+				"fleet_id":   fleetID,
+				"vehicle_id": vehicleID,
+				"status":     status,
+			}).WithError(err).Errorf("review fleet vehicle")
+		}
+		return nil, err
+	}
+
+	return parseVehicle(reviewed, active), nil
+}
+
+func parseFleet(f sqlc.Fleet) *model.Fleet {
+	return &model.Fleet{
+		ID:         f.ID,
+		Name:       f.Name,
+		OwnerID:    f.OwnerID,
+		RiderShare: int(f.RiderShare),
+		CreatedAt:  &f.CreatedAt,
+		UpdatedAt:  &f.UpdatedAt,
+	}
+}
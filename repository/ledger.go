@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Platform ledger accounts, user accounts use model.WalletAccount kinds
+const (
+	platformCash     = "PLATFORM_CASH"
+	platformRevenue  = "PLATFORM_REVENUE"
+	platformPromo    = "PLATFORM_PROMO"
+	platformReferral = "PLATFORM_REFERRAL"
+	checkViolation   = "23514"
+	uniqueViolation  = "23505"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("ledger: insufficient wallet balance")
+	ErrUnbalancedPosting   = errors.New("ledger: posting entries don't balance")
+)
+
+// ledgerLeg - one side of a posting. Positive amounts credit the account.
+type ledgerLeg struct {
+	userID  uuid.UUID
+	account string
+	amount  int
+}
+
+type ledgerPosting struct {
+	kind        model.LedgerTransactionKind
+	reference   string
+	tripID      *uuid.UUID
+	paymentID   *uuid.UUID
+	description string
+	legs        []ledgerLeg
+}
+
+func userLeg(userID uuid.UUID, account model.WalletAccount, amount int) ledgerLeg {
+	return ledgerLeg{userID, account.String(), amount}
+}
+
+func platformLeg(account string, amount int) ledgerLeg {
+	return ledgerLeg{uuid.Nil, account, amount}
+}
+
+// postLedger - append a balanced transaction and move account balances.
+// Must run inside store.WithTx. Posting a reference twice is a no-op and
+// reports false so callers can retry safely.
+func postLedger(q *sqlc.Queries, posting ledgerPosting) (bool, error) {
+	ctx := context.Background()
+
+	sum := 0
+	for _, leg := range posting.legs {
+		sum += leg.amount
+	}
+	if sum != 0 || len(posting.legs) < 2 {
+		return false, ErrUnbalancedPosting
+	}
+
+	transaction, err := q.CreateLedgerTransaction(ctx, sqlc.CreateLedgerTransactionParams{
+		Kind:        posting.kind.String(),
+		Reference:   posting.reference,
+		TripID:      nullUUID(posting.tripID),
+		PaymentID:   nullUUID(posting.paymentID),
+		Description: posting.description,
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	accountIDs := make([]uuid.UUID, len(posting.legs))
+	for i, leg := range posting.legs {
+		account, err := ledgerAccount(q, leg.userID, leg.account)
+		if err != nil {
+			return false, err
+		}
+		accountIDs[i] = account.ID
+	}
+
+	// Lock in a stable order so concurrent postings can't deadlock
+	if _, err := q.LockLedgerAccounts(ctx, accountIDs); err != nil {
+		return false, err
+	}
+
+	for i, leg := range posting.legs {
+		if leg.amount == 0 {
+			continue
+		}
+
+		balance, err := q.UpdateLedgerAccountBalance(ctx, sqlc.UpdateLedgerAccountBalanceParams{
+			ID:     accountIDs[i],
+			Amount: int32(leg.amount),
+		})
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == checkViolation {
+			return false, ErrInsufficientBalance
+		} else if err != nil {
+			return false, err
+		}
+
+		if _, err := q.CreateLedgerEntry(ctx, sqlc.CreateLedgerEntryParams{
+			TransactionID: transaction.ID,
+			AccountID:     accountIDs[i],
+			Amount:        int32(leg.amount),
+			Balance:       balance,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// ledgerAccount - platform account or user account, opened on first use
+func ledgerAccount(q *sqlc.Queries, userID uuid.UUID, kind string) (*sqlc.LedgerAccount, error) {
+	ctx := context.Background()
+
+	if userID == uuid.Nil {
+		account, err := q.GetPlatformLedgerAccount(ctx, kind)
+		if err != nil {
+			return nil, fmt.Errorf("ledger: platform account %s: %w", kind, err)
+		}
+		return &account, nil
+	}
+
+	owner := uuid.NullUUID{UUID: userID, Valid: true}
+	if err := q.EnsureLedgerAccount(ctx, sqlc.EnsureLedgerAccountParams{
+		UserID: owner,
+		Kind:   kind,
+	}); err != nil {
+		return nil, err
+	}
+
+	account, err := q.GetUserLedgerAccount(ctx, sqlc.GetUserLedgerAccountParams{
+		UserID: owner,
+		Kind:   kind,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// lockUserAccount - read a user balance that won't move until the tx ends
+func lockUserAccount(q *sqlc.Queries, userID uuid.UUID, account model.WalletAccount) (*sqlc.LedgerAccount, error) {
+	found, err := ledgerAccount(q, userID, account.String())
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := q.LockLedgerAccounts(context.Background(), []uuid.UUID{found.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &locked[0], nil
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+
+	return &id.UUID
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrProductNotFound = errors.New("onboarding repository: product not found")
+)
+
+type OnboardingRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (o *OnboardingRepository) Init(q *sqlc.Queries) {
+	o.store = q
+	o.log = internal.GetLogger()
+}
+
+// GetCourierOnboarding - checklist of the documents the courier product needs.
+// The active vehicle decides the product, until one is registered the
+// checklist is empty.
+func (o *OnboardingRepository) GetCourierOnboarding(courierID uuid.UUID) (*model.CourierOnboarding, error) {
+	ctx := context.Background()
+
+	courier, err := o.store.GetCourierOnboarding(ctx, courierID)
+	if err != nil {
+		o.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier onboarding")
+		return nil, err
+	}
+
+	onboarding := &model.CourierOnboarding{
+		CourierID: courier.ID,
+		Status:    model.CourierStatus(courier.Status),
+		Verified:  courier.Verified.Bool,
+		Steps:     make([]*model.OnboardingStep, 0),
+		Products:  make([]*model.Product, 0),
+	}
+
+	products, err := o.store.GetProducts(ctx)
+	if err != nil {
+		o.log.WithError(err).Errorf("get onboarding products")
+		return nil, err
+	}
+	for _, product := range products {
+		onboarding.Products = append(onboarding.Products, &model.Product{
+			ID:          product.ID,
+			Name:        product.Name,
+			Description: product.Description,
+			WeightClass: int(product.WeightClass),
+			IconURL:     product.Icon,
+		})
+	}
+
+	if !courier.ProductID.Valid {
+		return onboarding, nil
+	}
+	onboarding.ProductID = &courier.ProductID.UUID
+
+	required, err := o.store.GetProductDocuments(ctx, courier.ProductID.UUID)
+	if err != nil {
+		o.log.WithFields(logrus.Fields{
+			"product_id": courier.ProductID.UUID,
+		}).WithError(err).Errorf("get product documents")
+		return nil, err
+	}
+
+	uploads, err := o.store.GetCourierUploads(ctx, uuid.NullUUID{UUID: courierID, Valid: true})
+	if err != nil {
+		o.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get onboarding uploads")
+		return nil, err
+	}
+	byType := make(map[string]sqlc.Upload, len(uploads))
+	for _, upload := range uploads {
+		byType[upload.Type] = upload
+	}
+
+	for _, document := range required {
+		step := &model.OnboardingStep{
+			Type:   model.UploadFile(document),
+			Status: model.OnboardingStepStatusMissing,
+		}
+
+		if upload, ok := byType[document]; ok {
+			step.Upload = parseUpload(upload)
+			step.RejectionReason = step.Upload.RejectionReason
+			switch step.Upload.Verification {
+			case model.UploadVerificationStatusVerified:
+				step.Status = model.OnboardingStepStatusVerified
+				onboarding.CompletedSteps++
+			case model.UploadVerificationStatusRejected:
+				step.Status = model.OnboardingStepStatusRejected
+			default:
+				step.Status = model.OnboardingStepStatusVerifying
+			}
+		}
+
+		onboarding.Steps = append(onboarding.Steps, step)
+	}
+	onboarding.TotalSteps = len(onboarding.Steps)
+
+	return onboarding, nil
+}
+
+// syncCourierVerification - verify the courier once their active vehicle is
+// approved, by their fleet too if it has one, and every document its product
+// needs is verified, send them back to onboarding otherwise.
+// Must run inside store.WithTx.
+func syncCourierVerification(q *sqlc.Queries, courierID uuid.UUID) error {
+	ctx := context.Background()
+
+	courier, err := q.GetCourierOnboarding(ctx, courierID)
+	if err != nil {
+		return err
+	}
+
+	verified := false
+	if courier.ProductID.Valid &&
+		courier.VehicleStatus.String == model.VehicleStatusApproved.String() &&
+		courier.FleetApproved {
+		missing, err := q.CountMissingCourierDocuments(ctx, sqlc.CountMissingCourierDocumentsParams{
+			CourierID: uuid.NullUUID{UUID: courierID, Valid: true},
+			ProductID: courier.ProductID.UUID,
+		})
+		if err != nil {
+			return err
+		}
+		verified = missing == 0
+	}
+
+	updated, err := q.SetCourierVerified(ctx, sqlc.SetCourierVerifiedParams{
+		ID:       courierID,
+		Verified: sql.NullBool{Bool: verified, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordCourierTransition(q, courierID, courier.Status, updated.Status, transitionVerification, nil)
+}
@@ -0,0 +1,909 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPaymentNotFound       = errors.New("payment repository: payment not found")
+	ErrPaymentInProgress     = errors.New("payment repository: trip payment already in progress")
+	ErrPaymentNotRequired    = errors.New("payment repository: trip has nothing to pay")
+	ErrPaymentTripNotPriced  = errors.New("payment repository: trip has not been priced")
+	ErrPaymentTripNotAllowed = errors.New("payment repository: trip does not belong to user")
+	ErrPaymentCardNotFound   = errors.New("payment repository: payment card not found")
+	ErrRefundNotAllowed      = errors.New("payment repository: trip can't be refunded")
+	ErrRefundNotSupported    = errors.New("payment repository: payment provider doesn't support refunds")
+	ErrInvalidTopUpAmount    = errors.New("payment repository: top-up amount must be positive")
+	ErrTopUpProvider         = errors.New("payment repository: wallet can't be topped up from this provider")
+)
+
+type PaymentRepository struct {
+	store    *sqlc.Queries
+	log      *logrus.Logger
+	mpesa    internal.Mpesa
+	paystack internal.Paystack
+}
+
+func (p *PaymentRepository) Init(q *sqlc.Queries) {
+	p.store = q
+	p.log = internal.GetLogger()
+	p.mpesa = internal.GetMpesa()
+	p.paystack = internal.GetPaystack()
+}
+
+// CreateMpesaPayment - charge what the user owes on a trip through stk push
+func (p *PaymentRepository) CreateMpesaPayment(
+	userID uuid.UUID,
+	trip model.Trip,
+	phone string,
+) (*model.Payment, error) {
+	amount, err := p.tripAmountDue(userID, trip)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.mpesaCharge(userID, &trip.ID, model.PaymentPurposeTrip, amount, phone)
+}
+
+// CreateMpesaTopUp - load the user wallet through stk push
+func (p *PaymentRepository) CreateMpesaTopUp(
+	userID uuid.UUID,
+	amount int,
+	phone string,
+) (*model.Payment, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidTopUpAmount
+	}
+
+	return p.mpesaCharge(userID, nil, model.PaymentPurposeTopup, amount, phone)
+}
+
+// CreateMpesaRemittance - courier pays back the cash on delivery they hold
+func (p *PaymentRepository) CreateMpesaRemittance(
+	userID uuid.UUID,
+	phone string,
+) (*model.Payment, error) {
+	cash, err := p.store.GetUserLedgerAccount(context.Background(), sqlc.GetUserLedgerAccountParams{
+		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+		Kind:   model.WalletAccountCashHeld.String(),
+	})
+	if err == sql.ErrNoRows || (err == nil && cash.Balance >= 0) {
+		return nil, ErrNoCashToRemit
+	} else if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get courier cash account")
+		return nil, err
+	}
+
+	return p.mpesaCharge(userID, nil, model.PaymentPurposeRemittance, -int(cash.Balance), phone)
+}
+
+func (p *PaymentRepository) mpesaCharge(
+	userID uuid.UUID,
+	tripID *uuid.UUID,
+	purpose model.PaymentPurpose,
+	amount int,
+	phone string,
+) (*model.Payment, error) {
+	ctx := context.Background()
+
+	if phone == "" {
+		user, err := p.store.FindUserByID(ctx, userID)
+		if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"user_id": userID,
+			}).WithError(err).Errorf("find payment user")
+			return nil, err
+		}
+		phone = user.Phone
+	}
+
+	msisdn, err := internal.MpesaPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := p.store.CreatePayment(ctx, sqlc.CreatePaymentParams{
+		TripID:   nullUUID(tripID),
+		UserID:   userID,
+		Provider: model.PaymentProviderMpesa.String(),
+		Purpose:  purpose.String(),
+		Amount:   int32(amount),
+		Phone:    sql.NullString{String: msisdn, Valid: true},
+	})
+	if err != nil {
+		if openErr := openPaymentConflict(purpose, err); openErr != nil {
+			return nil, openErr
+		}
+		p.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+			"amount":  amount,
+		}).WithError(err).Errorf("create payment")
+		return nil, err
+	}
+
+	stk, stkErr := p.mpesa.StkPush(msisdn, amount, payment.ID.String()[:12])
+	if stkErr != nil {
+		p.failPayment(payment.ID, stkErr.Error())
+		return nil, stkErr
+	}
+
+	payment, err = p.store.SetPaymentCheckout(ctx, sqlc.SetPaymentCheckoutParams{
+		ID:                payment.ID,
+		MerchantRequestID: sql.NullString{String: stk.MerchantRequestID, Valid: true},
+		CheckoutRequestID: sql.NullString{String: stk.CheckoutRequestID, Valid: true},
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payment_id":          payment.ID,
+			"checkout_request_id": stk.CheckoutRequestID,
+		}).WithError(err).Errorf("set payment checkout")
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+// CompleteMpesaPayment - settle a pending payment from its daraja callback.
+// Safaricom may deliver a callback more than once so settled payments are
+// returned untouched with settled set to false.
+func (p *PaymentRepository) CompleteMpesaPayment(
+	callback internal.StkCallback,
+) (payment *model.Payment, settled bool, err error) {
+	ctx := context.Background()
+	result := callback.Body.StkCallback
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		found, err := q.GetPaymentByCheckoutIDForUpdate(ctx, sql.NullString{
+			String: result.CheckoutRequestID,
+			Valid:  true,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPaymentNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if found.Status != model.PaymentStatusPending.String() {
+			payment = parsePayment(found)
+			return nil
+		}
+
+		status := model.PaymentStatusFailed
+		resultDesc := result.ResultDesc
+		switch result.ResultCode {
+		case internal.MpesaResultSuccess:
+			// Only what was asked for settles the payment
+			if callback.Amount() == int(found.Amount) {
+				status = model.PaymentStatusPaid
+			} else {
+				resultDesc = fmt.Sprintf("amount mismatch: paid %d of %d", callback.Amount(), found.Amount)
+			}
+		case internal.MpesaResultCancelled:
+			status = model.PaymentStatusCancelled
+		}
+
+		updated, err := q.SetPaymentResult(ctx, sqlc.SetPaymentResultParams{
+			ID:         found.ID,
+			Status:     status.String(),
+			Receipt:    sql.NullString{String: callback.Receipt(), Valid: callback.Receipt() != ""},
+			ResultCode: sql.NullInt32{Int32: int32(result.ResultCode), Valid: true},
+			ResultDesc: sql.NullString{String: resultDesc, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := settlePayment(q, updated); err != nil {
+			return err
+		}
+
+		payment = parsePayment(updated)
+		settled = true
+		return nil
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"checkout_request_id": result.CheckoutRequestID,
+			"result_code":         result.ResultCode,
+		}).WithError(err).Errorf("complete mpesa payment")
+		return nil, false, err
+	}
+
+	return payment, settled, nil
+}
+
+// CreateCardPayment - charge a saved card or start a paystack checkout
+func (p *PaymentRepository) CreateCardPayment(
+	userID uuid.UUID,
+	trip model.Trip,
+	cardID *uuid.UUID,
+) (*model.Payment, error) {
+	amount, err := p.tripAmountDue(userID, trip)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.cardCharge(userID, &trip.ID, model.PaymentPurposeTrip, amount, cardID)
+}
+
+// CreateCardTopUp - load the user wallet from a card
+func (p *PaymentRepository) CreateCardTopUp(
+	userID uuid.UUID,
+	amount int,
+	cardID *uuid.UUID,
+) (*model.Payment, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidTopUpAmount
+	}
+
+	return p.cardCharge(userID, nil, model.PaymentPurposeTopup, amount, cardID)
+}
+
+func (p *PaymentRepository) cardCharge(
+	userID uuid.UUID,
+	tripID *uuid.UUID,
+	purpose model.PaymentPurpose,
+	amount int,
+	cardID *uuid.UUID,
+) (*model.Payment, error) {
+	ctx := context.Background()
+
+	var card *sqlc.PaymentCard
+	email := ""
+	usedCard := uuid.NullUUID{}
+	if cardID != nil {
+		found, err := p.store.GetUserPaymentCard(ctx, sqlc.GetUserPaymentCardParams{
+			ID:     *cardID,
+			UserID: userID,
+		})
+		if err == sql.ErrNoRows {
+			return nil, ErrPaymentCardNotFound
+		} else if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"card_id": *cardID,
+				"user_id": userID,
+			}).WithError(err).Errorf("get user payment card")
+			return nil, err
+		}
+		card = &found
+		email = found.Email
+		usedCard = uuid.NullUUID{UUID: found.ID, Valid: true}
+	} else {
+		user, err := p.store.FindUserByID(ctx, userID)
+		if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"user_id": userID,
+			}).WithError(err).Errorf("find payment user")
+			return nil, err
+		}
+		email = internal.PaystackEmail(user.Phone)
+	}
+
+	payment, err := p.store.CreatePayment(ctx, sqlc.CreatePaymentParams{
+		TripID:   nullUUID(tripID),
+		UserID:   userID,
+		Provider: model.PaymentProviderPaystack.String(),
+		Purpose:  purpose.String(),
+		Amount:   int32(amount),
+		CardID:   usedCard,
+	})
+	if err != nil {
+		if openErr := openPaymentConflict(purpose, err); openErr != nil {
+			return nil, openErr
+		}
+		p.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+			"amount":  amount,
+		}).WithError(err).Errorf("create payment")
+		return nil, err
+	}
+
+	// Reference goes in before calling paystack so an early webhook finds it
+	reference := payment.ID.String()
+	payment, err = p.store.SetPaymentReference(ctx, sqlc.SetPaymentReferenceParams{
+		ID:        payment.ID,
+		Reference: sql.NullString{String: reference, Valid: true},
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+		}).WithError(err).Errorf("set payment reference")
+		return nil, err
+	}
+
+	// One-tap repeat payment with a saved card authorization
+	if card != nil {
+		charge, chargeErr := p.paystack.ChargeAuthorization(email, card.AuthorizationCode, amount, reference)
+		if chargeErr != nil {
+			p.failPayment(payment.ID, chargeErr.Error())
+			return nil, chargeErr
+		}
+
+		// Webhook will settle charges paystack is still processing
+		if charge.Status != "success" && charge.Status != "failed" {
+			return parsePayment(payment), nil
+		}
+
+		var settled *model.Payment
+		if err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+			settled, _, err = p.settlePaystackCharge(q, *charge)
+			return err
+		}); err != nil {
+			p.log.WithFields(logrus.Fields{
+				"payment_id": payment.ID,
+			}).WithError(err).Errorf("settle card charge")
+			return nil, err
+		}
+
+		return settled, nil
+	}
+
+	transaction, initErr := p.paystack.InitializeTransaction(email, amount, reference)
+	if initErr != nil {
+		p.failPayment(payment.ID, initErr.Error())
+		return nil, initErr
+	}
+
+	payment, err = p.store.SetPaymentReference(ctx, sqlc.SetPaymentReferenceParams{
+		ID:               payment.ID,
+		Reference:        sql.NullString{String: reference, Valid: true},
+		AuthorizationUrl: sql.NullString{String: transaction.AuthorizationUrl, Valid: true},
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+		}).WithError(err).Errorf("set payment reference")
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+// CompletePaystackEvent - apply a verified paystack webhook event once.
+// Settled is true only when a pending charge got paid by this event.
+func (p *PaymentRepository) CompletePaystackEvent(
+	event internal.PaystackEvent,
+) (payment *model.Payment, settled bool, err error) {
+	ctx := context.Background()
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		switch event.Event {
+		case "charge.success":
+			var charge internal.PaystackCharge
+			if err := json.Unmarshal(event.Data, &charge); err != nil {
+				return err
+			}
+
+			if fresh, err := p.recordPaystackEvent(q, event.Event, charge.Reference); err != nil || !fresh {
+				return err
+			}
+
+			payment, settled, err = p.settlePaystackCharge(q, charge)
+			return err
+		case "refund.processed", "refund.failed":
+			var refund internal.PaystackRefund
+			if err := json.Unmarshal(event.Data, &refund); err != nil {
+				return err
+			}
+
+			if fresh, err := p.recordPaystackEvent(q, event.Event, fmt.Sprintf("%d", refund.ID)); err != nil || !fresh {
+				return err
+			}
+
+			found, err := q.GetPaymentByReferenceForUpdate(ctx, sql.NullString{
+				String: refund.TransactionReference,
+				Valid:  true,
+			})
+			if err == sql.ErrNoRows {
+				return ErrPaymentNotFound
+			} else if err != nil {
+				return err
+			}
+
+			if event.Event == "refund.failed" {
+				// Let the user retry the refund
+				updated, err := q.SetPaymentStatus(ctx, sqlc.SetPaymentStatusParams{
+					ID:     found.ID,
+					Status: model.PaymentStatusPaid.String(),
+				})
+				payment = parsePayment(updated)
+				return err
+			}
+
+			updated, err := q.RefundPayment(ctx, sqlc.RefundPaymentParams{
+				ID:             found.ID,
+				Status:         model.PaymentStatusRefunded.String(),
+				RefundedAmount: int32(refund.Amount / 100),
+			})
+			payment = parsePayment(updated)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"event": event.Event,
+		}).WithError(err).Errorf("complete paystack event")
+		return nil, false, err
+	}
+
+	return payment, settled, nil
+}
+
+// RefundTripPayment - refund payments for trips that never got a courier.
+// Card payments go back to the card, m-pesa and wallet payments to the wallet.
+func (p *PaymentRepository) RefundTripPayment(
+	userID uuid.UUID,
+	trip model.Trip,
+) (*model.Payment, error) {
+	var payment sqlc.Payment
+	ctx := context.Background()
+
+	if trip.UserID != userID {
+		return nil, ErrPaymentTripNotAllowed
+	}
+	if trip.Status != model.TripStatusCancelled &&
+		trip.Status != model.TripStatusCourierNotFound {
+		return nil, ErrRefundNotAllowed
+	}
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		found, err := q.GetPaidTripPaymentForUpdate(ctx, uuid.NullUUID{UUID: trip.ID, Valid: true})
+		if err == sql.ErrNoRows {
+			return ErrPaymentNotFound
+		} else if err != nil {
+			return err
+		}
+
+		switch found.Provider {
+		case model.PaymentProviderWallet.String():
+			payment, err = releaseTripHold(q, trip, found)
+			return err
+		case model.PaymentProviderMpesa.String():
+			amount := int(found.Amount - found.RefundedAmount)
+			if _, err := postLedger(q, ledgerPosting{
+				kind:        model.LedgerTransactionKindRefund,
+				reference:   fmt.Sprintf("refund:%s", found.ID),
+				tripID:      &trip.ID,
+				paymentID:   &found.ID,
+				description: "Trip refund",
+				legs: []ledgerLeg{
+					platformLeg(platformCash, -amount),
+					userLeg(found.UserID, model.WalletAccountCash, amount),
+				},
+			}); err != nil {
+				return err
+			}
+
+			payment, err = q.RefundPayment(ctx, sqlc.RefundPaymentParams{
+				ID:             found.ID,
+				Status:         model.PaymentStatusRefunded.String(),
+				RefundedAmount: int32(amount),
+			})
+			return err
+		case model.PaymentProviderPaystack.String():
+			payment, err = q.SetPaymentStatus(ctx, sqlc.SetPaymentStatusParams{
+				ID:     found.ID,
+				Status: model.PaymentStatusRefunding.String(),
+			})
+			return err
+		}
+
+		return ErrRefundNotSupported
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("refund trip payment")
+		return nil, err
+	}
+
+	if payment.Status != model.PaymentStatusRefunding.String() {
+		return parsePayment(payment), nil
+	}
+
+	amount := int(payment.Amount - payment.RefundedAmount)
+	if err := p.paystack.Refund(payment.Reference.String, amount); err != nil {
+		if _, statusErr := p.store.SetPaymentStatus(ctx, sqlc.SetPaymentStatusParams{
+			ID:     payment.ID,
+			Status: model.PaymentStatusPaid.String(),
+		}); statusErr != nil {
+			p.log.WithFields(logrus.Fields{
+				"payment_id": payment.ID,
+			}).WithError(statusErr).Errorf("reset refunding payment")
+		}
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+// CreateWalletPayment - pay for a trip by holding the amount on the wallet.
+// The hold is charged when the trip completes or released if it's cancelled.
+func (p *PaymentRepository) CreateWalletPayment(
+	userID uuid.UUID,
+	trip model.Trip,
+) (*model.Payment, error) {
+	var payment sqlc.Payment
+	ctx := context.Background()
+
+	amount, err := p.tripAmountDue(userID, trip)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		created, err := q.CreatePayment(ctx, sqlc.CreatePaymentParams{
+			TripID:   uuid.NullUUID{UUID: trip.ID, Valid: true},
+			UserID:   userID,
+			Provider: model.PaymentProviderWallet.String(),
+			Purpose:  model.PaymentPurposeTrip.String(),
+			Amount:   int32(amount),
+		})
+		if err != nil {
+			return err
+		}
+
+		posted, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindHold,
+			reference:   fmt.Sprintf("hold:%s", trip.ID),
+			tripID:      &trip.ID,
+			paymentID:   &created.ID,
+			description: "Trip booking hold",
+			legs: []ledgerLeg{
+				userLeg(userID, model.WalletAccountCash, -amount),
+				userLeg(userID, model.WalletAccountHold, amount),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		// The trip fare is already held
+		if !posted {
+			return ErrPaymentInProgress
+		}
+
+		payment, err = q.SetPaymentStatus(ctx, sqlc.SetPaymentStatusParams{
+			ID:     created.ID,
+			Status: model.PaymentStatusPaid.String(),
+		})
+		return err
+	})
+	if err != nil {
+		if openErr := openPaymentConflict(model.PaymentPurposeTrip, err); openErr != nil {
+			return nil, openErr
+		}
+		if !errors.Is(err, ErrInsufficientBalance) && !errors.Is(err, ErrPaymentInProgress) {
+			p.log.WithFields(logrus.Fields{
+				"trip_id": trip.ID,
+				"amount":  amount,
+			}).WithError(err).Errorf("create wallet payment")
+		}
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+func (p *PaymentRepository) GetUserPaymentCards(userID uuid.UUID) ([]*model.PaymentCard, error) {
+	var cards []*model.PaymentCard
+
+	found, err := p.store.GetUserPaymentCards(context.Background(), userID)
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get user payment cards")
+		return nil, err
+	}
+
+	for _, item := range found {
+		card := &model.PaymentCard{
+			ID:        item.ID,
+			CardType:  item.CardType,
+			Last4:     item.Last4,
+			ExpMonth:  item.ExpMonth,
+			ExpYear:   item.ExpYear,
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.Bank.Valid {
+			card.Bank = &item.Bank.String
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+func (p *PaymentRepository) settlePaystackCharge(
+	q *sqlc.Queries,
+	charge internal.PaystackCharge,
+) (*model.Payment, bool, error) {
+	ctx := context.Background()
+
+	found, err := q.GetPaymentByReferenceForUpdate(ctx, sql.NullString{
+		String: charge.Reference,
+		Valid:  true,
+	})
+	if err == sql.ErrNoRows {
+		return nil, false, ErrPaymentNotFound
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	if found.Status != model.PaymentStatusPending.String() {
+		return parsePayment(found), false, nil
+	}
+
+	status := model.PaymentStatusFailed
+	if charge.Status == "success" &&
+		charge.Currency == internal.PAYSTACK_CURRENCY &&
+		charge.Amount >= internal.PaystackAmount(int(found.Amount)) {
+		status = model.PaymentStatusPaid
+	}
+
+	updated, err := q.SetPaymentResult(ctx, sqlc.SetPaymentResultParams{
+		ID:         found.ID,
+		Status:     status.String(),
+		Receipt:    sql.NullString{String: fmt.Sprintf("%d", charge.ID), Valid: charge.ID > 0},
+		ResultDesc: sql.NullString{String: charge.GatewayReply, Valid: true},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := settlePayment(q, updated); err != nil {
+		return nil, false, err
+	}
+
+	auth := charge.Authorization
+	if status == model.PaymentStatusPaid && auth.Reusable && auth.Channel == "card" {
+		card, err := q.SavePaymentCard(ctx, sqlc.SavePaymentCardParams{
+			UserID:            found.UserID,
+			Email:             charge.Customer.Email,
+			AuthorizationCode: auth.AuthorizationCode,
+			Signature:         auth.Signature,
+			CardType:          strings.TrimSpace(auth.CardType),
+			Last4:             auth.Last4,
+			ExpMonth:          auth.ExpMonth,
+			ExpYear:           auth.ExpYear,
+			Bank:              sql.NullString{String: auth.Bank, Valid: auth.Bank != ""},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Checkout payments learn their card here
+		if !found.CardID.Valid {
+			if err := q.SetPaymentCard(ctx, sqlc.SetPaymentCardParams{
+				CardID: uuid.NullUUID{UUID: card.ID, Valid: true},
+				ID:     found.ID,
+			}); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	return parsePayment(updated), status == model.PaymentStatusPaid, nil
+}
+
+// recordPaystackEvent - false when the event was already processed
+func (p *PaymentRepository) recordPaystackEvent(
+	q *sqlc.Queries,
+	event, key string,
+) (bool, error) {
+	_, err := q.CreatePaymentEvent(context.Background(), sqlc.CreatePaymentEventParams{
+		Provider: model.PaymentProviderPaystack.String(),
+		EventKey: fmt.Sprintf("%s:%s", event, key),
+		Event:    event,
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// tripAmountDue - what the user still owes on a trip
+func (p *PaymentRepository) tripAmountDue(userID uuid.UUID, trip model.Trip) (int, error) {
+	if trip.UserID != userID {
+		return 0, ErrPaymentTripNotAllowed
+	}
+	if trip.Cost == 0 {
+		return 0, ErrPaymentTripNotPriced
+	}
+
+	amount := trip.Cost - trip.Discount
+	if amount <= 0 {
+		return 0, ErrPaymentNotRequired
+	}
+
+	// Checkouts left unanswered give way to a new attempt
+	if err := p.store.ExpireStaleTripPayments(context.Background(), sqlc.ExpireStaleTripPaymentsParams{
+		TripID:  uuid.NullUUID{UUID: trip.ID, Valid: true},
+		Purpose: model.PaymentPurposeTrip.String(),
+	}); err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("expire stale trip payments")
+		return 0, err
+	}
+
+	open, err := p.store.HasOpenTripPayment(context.Background(), sqlc.HasOpenTripPaymentParams{
+		TripID:  uuid.NullUUID{UUID: trip.ID, Valid: true},
+		Purpose: model.PaymentPurposeTrip.String(),
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("has open trip payment")
+		return 0, err
+	}
+	if open {
+		return 0, ErrPaymentInProgress
+	}
+
+	return amount, nil
+}
+
+// openPaymentConflict - the trip already has a pending or paid payment for
+// the purpose, caught by its unique index
+func openPaymentConflict(purpose model.PaymentPurpose, err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return nil
+	}
+
+	switch purpose {
+	case model.PaymentPurposeTrip:
+		return ErrPaymentInProgress
+	case model.PaymentPurposeTip:
+		return ErrTripAlreadyTipped
+	}
+
+	return nil
+}
+
+func (p *PaymentRepository) failPayment(paymentID uuid.UUID, reason string) {
+	if _, err := p.store.SetPaymentResult(context.Background(), sqlc.SetPaymentResultParams{
+		ID:         paymentID,
+		Status:     model.PaymentStatusFailed.String(),
+		ResultDesc: sql.NullString{String: reason, Valid: true},
+	}); err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payment_id": paymentID,
+		}).WithError(err).Errorf("fail payment")
+	}
+}
+
+// GetTripPayment - latest trip payment for the customer, courier or an admin
+func (p *PaymentRepository) GetTripPayment(userID, tripID uuid.UUID, admin bool) (*model.Payment, error) {
+	ctx := context.Background()
+
+	if !admin {
+		trip, err := p.store.GetTrip(ctx, tripID)
+		if err == sql.ErrNoRows {
+			return nil, ErrPaymentTripNotAllowed
+		} else if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"trip_id": tripID,
+			}).WithError(err).Errorf("get trip payment trip")
+			return nil, err
+		}
+
+		party, err := isTripParty(p.store, userID, trip.UserID, trip.CourierID)
+		if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"trip_id": tripID,
+			}).WithError(err).Errorf("get trip payment courier")
+			return nil, err
+		}
+		if !party {
+			return nil, ErrPaymentTripNotAllowed
+		}
+	}
+
+	return p.tripPayment(tripID)
+}
+
+func (p *PaymentRepository) tripPayment(tripID uuid.UUID) (*model.Payment, error) {
+	payment, err := p.store.GetTripPayment(
+		context.Background(),
+		uuid.NullUUID{UUID: tripID, Valid: true},
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get trip payment")
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+func parsePayment(p sqlc.Payment) *model.Payment {
+	payment := &model.Payment{
+		ID:             p.ID,
+		Provider:       model.PaymentProvider(p.Provider),
+		Purpose:        model.PaymentPurpose(p.Purpose),
+		Amount:         int(p.Amount),
+		Status:         model.PaymentStatus(p.Status),
+		RefundedAmount: int(p.RefundedAmount),
+		CreatedAt:      &p.CreatedAt,
+		UpdatedAt:      &p.UpdatedAt,
+	}
+	if p.TripID.Valid {
+		payment.TripID = &p.TripID.UUID
+	}
+	if p.Phone.Valid {
+		payment.Phone = &p.Phone.String
+	}
+	if p.Receipt.Valid {
+		payment.Receipt = &p.Receipt.String
+	}
+	if p.ResultDesc.Valid {
+		payment.ResultDesc = &p.ResultDesc.String
+	}
+	if p.Reference.Valid {
+		payment.Reference = &p.Reference.String
+	}
+	if p.AuthorizationUrl.Valid {
+		payment.AuthorizationURL = &p.AuthorizationUrl.String
+	}
+
+	return payment
+}
+
+// settlePayment - post what a paid payment is for to the ledger
+func settlePayment(q *sqlc.Queries, payment sqlc.Payment) error {
+	if payment.Status != model.PaymentStatusPaid.String() {
+		return nil
+	}
+
+	switch model.PaymentPurpose(payment.Purpose) {
+	case model.PaymentPurposeTopup:
+		return settleTopUp(q, payment)
+	case model.PaymentPurposeRemittance:
+		return settleRemittance(q, payment)
+	case model.PaymentPurposeTip:
+		return settleTip(q, payment)
+	}
+
+	return nil
+}
+
+// settleTopUp - credit the wallet once a top-up payment is paid
+func settleTopUp(q *sqlc.Queries, payment sqlc.Payment) error {
+	_, err := postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindTopup,
+		reference:   fmt.Sprintf("topup:%s", payment.ID),
+		paymentID:   &payment.ID,
+		description: "Wallet top-up",
+		legs: []ledgerLeg{
+			platformLeg(platformCash, -int(payment.Amount)),
+			userLeg(payment.UserID, model.WalletAccountCash, int(payment.Amount)),
+		},
+	})
+
+	return err
+}
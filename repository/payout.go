@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	platformPayout = "PLATFORM_PAYOUT"
+	// Payouts run DAILY unless configured WEEKLY
+	payoutWeekly    = "WEEKLY"
+	payoutRetryStep = 15 * time.Minute
+)
+
+var (
+	ErrPayoutNotFound = errors.New("payout repository: payout not found")
+)
+
+type PayoutRepository struct {
+	store  *sqlc.Queries
+	config config.Payout
+	log    *logrus.Logger
+	mpesa  internal.Mpesa
+}
+
+func (p *PayoutRepository) Init(q *sqlc.Queries) {
+	p.store = q
+	p.config = config.Config.Payout
+	p.log = internal.GetLogger()
+	p.mpesa = internal.GetMpesa()
+}
+
+// BatchPayouts - open a payout for every courier or fleet owner whose
+// earnings settled before the current schedule cutoff add up to the minimum
+func (p *PayoutRepository) BatchPayouts(now time.Time) error {
+	cutoff := payoutCutoff(p.config.Schedule, now)
+
+	candidates, err := p.store.GetPayoutCandidates(context.Background(), int32(p.config.Minimum))
+	if err != nil {
+		p.log.WithError(err).Errorf("get payout candidates")
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if err := p.batchCourierPayout(candidate, cutoff); err != nil {
+			p.log.WithFields(logrus.Fields{
+				"user_id": candidate.UserID,
+			}).WithError(err).Errorf("batch courier payout")
+		}
+	}
+
+	return nil
+}
+
+func (p *PayoutRepository) batchCourierPayout(
+	candidate sqlc.GetPayoutCandidatesRow,
+	cutoff time.Time,
+) error {
+	ctx := context.Background()
+
+	msisdn, err := internal.MpesaPhone(candidate.Phone)
+	if err != nil {
+		return err
+	}
+
+	return store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if err := netCashHeld(q, candidate.UserID); err != nil {
+			return err
+		}
+
+		account, err := lockUserAccount(q, candidate.UserID, model.WalletAccountEarnings)
+		if err != nil {
+			return err
+		}
+
+		entries, err := q.GetUnpaidEarningsEntries(ctx, sqlc.GetUnpaidEarningsEntriesParams{
+			AccountID: account.ID,
+			Cutoff:    cutoff.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Every entry the amount is made of is linked to the payout so the
+		// next batch doesn't count it again. Deductions after the cutoff,
+		// cash netted just now included, are in here which keeps the amount
+		// within the balance.
+		amount := 0
+		entryIDs := make([]uuid.UUID, len(entries))
+		for i, entry := range entries {
+			amount += int(entry.Amount)
+			entryIDs[i] = entry.ID
+		}
+		if amount < p.config.Minimum || amount > int(account.Balance) {
+			return nil
+		}
+
+		payout, err := q.CreatePayout(ctx, sqlc.CreatePayoutParams{
+			CourierID: candidate.CourierID,
+			UserID:    candidate.UserID,
+			Amount:    int32(amount),
+			Phone:     msisdn,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.CreatePayoutEntries(ctx, sqlc.CreatePayoutEntriesParams{
+			PayoutID: payout.ID,
+			EntryIds: entryIDs,
+		}); err != nil {
+			return err
+		}
+
+		_, err = postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindPayout,
+			reference:   fmt.Sprintf("payout:%s", payout.ID),
+			description: "M-Pesa payout",
+			legs: []ledgerLeg{
+				userLeg(candidate.UserID, model.WalletAccountEarnings, -amount),
+				platformLeg(platformPayout, amount),
+			},
+		})
+		return err
+	})
+}
+
+// SubmitDuePayouts - send pending payouts whose next attempt is due to daraja
+func (p *PayoutRepository) SubmitDuePayouts() error {
+	due, err := p.store.GetDuePayouts(context.Background())
+	if err != nil {
+		p.log.WithError(err).Errorf("get due payouts")
+		return err
+	}
+
+	for _, payoutID := range due {
+		p.submitPayout(payoutID)
+	}
+
+	// Ask again about failures daraja hasn't told us the status of
+	verifying, err := p.store.GetDuePayoutVerifications(context.Background())
+	if err != nil {
+		p.log.WithError(err).Errorf("get due payout verifications")
+		return err
+	}
+
+	for _, payout := range verifying {
+		p.queryPayoutStatus(payout)
+	}
+
+	return nil
+}
+
+func (p *PayoutRepository) submitPayout(payoutID uuid.UUID) {
+	ctx := context.Background()
+	var submitted *sqlc.Payout
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payout, err := q.GetPayoutForUpdate(ctx, payoutID)
+		if err != nil {
+			return err
+		}
+
+		// Another instance got to it first
+		if payout.Status != model.PayoutStatusPending.String() {
+			return nil
+		}
+
+		// Daraja wants a fresh originator id for every attempt
+		updated, err := q.SetPayoutSubmitted(ctx, sqlc.SetPayoutSubmittedParams{
+			ID: payout.ID,
+			OriginatorConversationID: sql.NullString{
+				String: fmt.Sprintf("%s-%d", payout.ID, payout.Attempts+1),
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		submitted = &updated
+		return nil
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payout_id": payoutID,
+		}).WithError(err).Errorf("submit payout")
+		return
+	}
+	if submitted == nil {
+		return
+	}
+
+	res, b2cErr := p.mpesa.B2CPayment(
+		submitted.Phone,
+		int(submitted.Amount),
+		submitted.OriginatorConversationID.String,
+	)
+	if errors.Is(b2cErr, internal.ErrMpesaNoResponse) {
+		// Daraja may have taken it, ask once the payment had time to go through
+		if _, err := p.verifyPayout(submitted.OriginatorConversationID.String, nil, b2cErr.Error()); err != nil {
+			p.log.WithFields(logrus.Fields{
+				"payout_id": payoutID,
+			}).WithError(err).Errorf("verify unanswered payout")
+		}
+		return
+	} else if b2cErr != nil {
+		if err := p.failPayoutAttempt(submitted.OriginatorConversationID.String, nil, b2cErr.Error()); err != nil {
+			p.log.WithFields(logrus.Fields{
+				"payout_id": payoutID,
+			}).WithError(err).Errorf("fail payout attempt")
+		}
+		return
+	}
+
+	if err := p.store.SetPayoutConversation(ctx, sqlc.SetPayoutConversationParams{
+		ID:             submitted.ID,
+		ConversationID: sql.NullString{String: res.ConversationID, Valid: true},
+	}); err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payout_id":       payoutID,
+			"conversation_id": res.ConversationID,
+		}).WithError(err).Errorf("set payout conversation")
+	}
+}
+
+// CompleteB2CResult - settle a submitted payout from its daraja result.
+// Failures and timeouts may still have sent the money so they are checked
+// with daraja before another attempt. Results for attempts we already
+// settled are ignored.
+func (p *PayoutRepository) CompleteB2CResult(result internal.B2CResult) error {
+	res := result.Result
+
+	if res.ResultCode != internal.MpesaResultSuccess {
+		verifying, err := p.verifyPayout(res.OriginatorConversationID, &res.ResultCode, res.ResultDesc)
+		if verifying != nil {
+			p.queryPayoutStatus(*verifying)
+		}
+		return err
+	}
+
+	ctx := context.Background()
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payout, err := q.GetPayoutByOriginatorIDForUpdate(ctx, sql.NullString{
+			String: res.OriginatorConversationID,
+			Valid:  true,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPayoutNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// A result can still come in for an attempt that went unanswered
+		if payout.Status != model.PayoutStatusProcessing.String() &&
+			payout.Status != model.PayoutStatusVerifying.String() {
+			return nil
+		}
+
+		return payPayout(q, payout, res.TransactionID, res.ResultCode, res.ResultDesc)
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"originator_id": res.OriginatorConversationID,
+			"result_code":   res.ResultCode,
+		}).WithError(err).Errorf("complete b2c result")
+		return err
+	}
+
+	return nil
+}
+
+// CompleteB2CStatus - settle a payout we were verifying from what daraja
+// says became of it. Only payments daraja didn't complete are retried.
+func (p *PayoutRepository) CompleteB2CStatus(result internal.TransactionStatusResult) error {
+	ctx := context.Background()
+	res := result.Result
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payout, err := q.GetPayoutByStatusConversationForUpdate(ctx, sql.NullString{
+			String: res.ConversationID,
+			Valid:  true,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPayoutNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if payout.Status != model.PayoutStatusVerifying.String() {
+			return nil
+		}
+
+		if result.Completed() {
+			return payPayout(q, payout, result.Parameter("ReceiptNo"), res.ResultCode, res.ResultDesc)
+		}
+
+		return p.failPayout(q, payout, payout.ResultCode, payout.ResultDesc)
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"conversation_id": res.ConversationID,
+			"result_code":     res.ResultCode,
+		}).WithError(err).Errorf("complete b2c status")
+		return err
+	}
+
+	return nil
+}
+
+// verifyPayout - hold a failed or unanswered attempt until daraja confirms
+// the money didn't go out. Payouts still verifying are asked about again once
+// they are due.
+func (p *PayoutRepository) verifyPayout(originatorID string, resultCode *int, reason string) (*sqlc.Payout, error) {
+	ctx := context.Background()
+	var verifying *sqlc.Payout
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payout, err := q.GetPayoutByOriginatorIDForUpdate(ctx, sql.NullString{
+			String: originatorID,
+			Valid:  true,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPayoutNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if payout.Status != model.PayoutStatusProcessing.String() {
+			return nil
+		}
+
+		code := sql.NullInt32{}
+		if resultCode != nil {
+			code = sql.NullInt32{Int32: int32(*resultCode), Valid: true}
+		}
+
+		updated, err := q.SetPayoutVerifying(ctx, sqlc.SetPayoutVerifyingParams{
+			ID:            payout.ID,
+			NextAttemptAt: time.Now().UTC().Add(payoutRetryStep),
+			ResultCode:    code,
+			ResultDesc:    sql.NullString{String: reason, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		verifying = &updated
+		return nil
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"originator_id": originatorID,
+			"result_code":   resultCode,
+		}).WithError(err).Errorf("verify payout")
+		return nil, err
+	}
+
+	return verifying, nil
+}
+
+// queryPayoutStatus - ask daraja about a payout we are verifying. Queries
+// that don't go through are asked again once the payout is due.
+func (p *PayoutRepository) queryPayoutStatus(payout sqlc.Payout) {
+	res, err := p.mpesa.TransactionStatus(payout.OriginatorConversationID.String)
+	if err != nil {
+		return
+	}
+
+	if err := p.store.SetPayoutStatusConversation(context.Background(), sqlc.SetPayoutStatusConversationParams{
+		ID:                   payout.ID,
+		StatusConversationID: sql.NullString{String: res.ConversationID, Valid: true},
+		NextAttemptAt:        time.Now().UTC().Add(payoutRetryStep),
+	}); err != nil {
+		p.log.WithFields(logrus.Fields{
+			"payout_id":       payout.ID,
+			"conversation_id": res.ConversationID,
+		}).WithError(err).Errorf("set payout status conversation")
+	}
+}
+
+// failPayoutAttempt - schedule another attempt with a growing backoff or give
+// up and hand the amount back to the courier earnings so the next batch picks
+// the entries up again
+func (p *PayoutRepository) failPayoutAttempt(originatorID string, resultCode *int, reason string) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payout, err := q.GetPayoutByOriginatorIDForUpdate(ctx, sql.NullString{
+			String: originatorID,
+			Valid:  true,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPayoutNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if payout.Status != model.PayoutStatusProcessing.String() {
+			return nil
+		}
+
+		code := sql.NullInt32{}
+		if resultCode != nil {
+			code = sql.NullInt32{Int32: int32(*resultCode), Valid: true}
+		}
+
+		return p.failPayout(q, payout, code, sql.NullString{String: reason, Valid: true})
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"originator_id": originatorID,
+			"reason":        reason,
+		}).WithError(err).Errorf("fail payout attempt")
+		return err
+	}
+
+	return nil
+}
+
+// failPayout - retry or give up on a payout daraja didn't send.
+// Must run inside store.WithTx.
+func (p *PayoutRepository) failPayout(
+	q *sqlc.Queries,
+	payout sqlc.Payout,
+	code sql.NullInt32,
+	desc sql.NullString,
+) error {
+	ctx := context.Background()
+
+	if int(payout.Attempts) < p.config.MaxAttempts {
+		_, err := q.SetPayoutRetry(ctx, sqlc.SetPayoutRetryParams{
+			ID:            payout.ID,
+			Attempts:      payout.Attempts,
+			NextAttemptAt: time.Now().UTC().Add(time.Duration(payout.Attempts) * payoutRetryStep),
+			ResultCode:    code,
+			ResultDesc:    desc,
+		})
+		return err
+	}
+
+	if _, err := q.SetPayoutResult(ctx, sqlc.SetPayoutResultParams{
+		ID:         payout.ID,
+		Status:     model.PayoutStatusFailed.String(),
+		Attempts:   payout.Attempts,
+		ResultCode: code,
+		ResultDesc: desc,
+	}); err != nil {
+		return err
+	}
+
+	if err := q.DeletePayoutEntries(ctx, payout.ID); err != nil {
+		return err
+	}
+
+	_, err := postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindPayoutReversal,
+		reference:   fmt.Sprintf("payout-reversal:%s", payout.ID),
+		description: "M-Pesa payout failed",
+		legs: []ledgerLeg{
+			platformLeg(platformPayout, -int(payout.Amount)),
+			userLeg(payout.UserID, model.WalletAccountEarnings, int(payout.Amount)),
+		},
+	})
+	return err
+}
+
+// payPayout - mark the payout sent and move the amount out of platform cash.
+// Must run inside store.WithTx.
+func payPayout(q *sqlc.Queries, payout sqlc.Payout, receipt string, resultCode int, reason string) error {
+	if _, err := q.SetPayoutResult(context.Background(), sqlc.SetPayoutResultParams{
+		ID:         payout.ID,
+		Status:     model.PayoutStatusPaid.String(),
+		Attempts:   payout.Attempts,
+		Receipt:    sql.NullString{String: receipt, Valid: receipt != ""},
+		ResultCode: sql.NullInt32{Int32: int32(resultCode), Valid: true},
+		ResultDesc: sql.NullString{String: reason, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	_, err := postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindPayout,
+		reference:   fmt.Sprintf("payout-paid:%s", payout.ID),
+		description: "M-Pesa payout sent",
+		legs: []ledgerLeg{
+			platformLeg(platformPayout, -int(payout.Amount)),
+			platformLeg(platformCash, int(payout.Amount)),
+		},
+	})
+
+	return err
+}
+
+func (p *PayoutRepository) GetFailedPayouts(limit, offset int) ([]*model.Payout, error) {
+	var payouts []*model.Payout
+
+	failed, err := p.store.GetFailedPayouts(context.Background(), sqlc.GetFailedPayoutsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		p.log.WithError(err).Errorf("get failed payouts")
+		return nil, err
+	}
+
+	for _, payout := range failed {
+		payouts = append(payouts, parsePayout(payout))
+	}
+
+	return payouts, nil
+}
+
+func parsePayout(p sqlc.Payout) *model.Payout {
+	payout := &model.Payout{
+		ID:        p.ID,
+		CourierID: nullUUIDPtr(p.CourierID),
+		UserID:    p.UserID,
+		Amount:    int(p.Amount),
+		Phone:     p.Phone,
+		Status:    model.PayoutStatus(p.Status),
+		Attempts:  int(p.Attempts),
+		CreatedAt: &p.CreatedAt,
+		UpdatedAt: &p.UpdatedAt,
+	}
+	if p.Receipt.Valid {
+		payout.Receipt = &p.Receipt.String
+	}
+	if p.ResultDesc.Valid {
+		payout.ResultDesc = &p.ResultDesc.String
+	}
+
+	return payout
+}
+
+// payoutCutoff - earnings before the start of the current day or week are settled
+func payoutCutoff(schedule string, now time.Time) time.Time {
+	today := startOfDay(internal.LocalTime(now))
+
+	if schedule == payoutWeekly {
+		return startOfWeek(today)
+	}
+
+	return today
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tripCompletedPoints = 10
+	onTimePoints        = 5
+	highRatingPoints    = 5
+	lowRatingPoints     = -5
+	cancellationPoints  = -15
+	lateArrivalPoints   = -5
+	// Delivered within the route duration plus grace is on time
+	onTimeGrace = 10 * time.Minute
+	// Reaching pickup later than this after assignment is late
+	lateArrivalAfter = 15 * time.Minute
+	silverTierPoints = 500
+	goldTierPoints   = 1500
+	recentPoints     = 20
+)
+
+type PointsRepository struct {
+	p     internal.Pricing
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (p *PointsRepository) Init(q *sqlc.Queries) {
+	p.p = internal.GetPricer()
+	p.store = q
+	p.log = internal.GetLogger()
+}
+
+// AwardTripPoints - completion, on-time delivery and late arrival points for
+// a completed trip. Safe to call more than once per trip.
+func (p *PointsRepository) AwardTripPoints(tripID uuid.UUID) error {
+	ctx := context.Background()
+
+	trip, err := p.store.GetTripTimeline(ctx, tripID)
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get trip timeline")
+		return err
+	}
+	if !trip.CourierID.Valid {
+		return nil
+	}
+
+	awards := map[model.PointsReason]int{
+		model.PointsReasonTripCompleted: tripCompletedPoints,
+	}
+	if trip.PickedUpAt.Valid && trip.CompletedAt.Valid && trip.Duration > 0 {
+		allowed := time.Duration(trip.Duration)*time.Second + onTimeGrace
+		if trip.CompletedAt.Time.Sub(trip.PickedUpAt.Time) <= allowed {
+			awards[model.PointsReasonOnTimeDelivery] = onTimePoints
+		}
+	}
+	if trip.AssignedAt.Valid && trip.ArrivedAt.Valid &&
+		trip.ArrivedAt.Time.Sub(trip.AssignedAt.Time) > lateArrivalAfter {
+		awards[model.PointsReasonLateArrival] = lateArrivalPoints
+	}
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		for reason, points := range awards {
+			if err := awardPoints(q, trip.CourierID.UUID, &trip.ID, reason, points); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id":    tripID,
+			"courier_id": trip.CourierID.UUID,
+		}).WithError(err).Errorf("award trip points")
+		return err
+	}
+
+	return nil
+}
+
+// DeductCancellation - courier dropped a trip they were assigned
+func (p *PointsRepository) DeductCancellation(courierID, tripID uuid.UUID) error {
+	err := store.WithTx(context.Background(), func(q *sqlc.Queries) error {
+		return awardPoints(q, courierID, &tripID, model.PointsReasonCancellation, cancellationPoints)
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"trip_id":    tripID,
+			"courier_id": courierID,
+		}).WithError(err).Errorf("deduct cancellation points")
+		return err
+	}
+
+	return nil
+}
+
+func (p *PointsRepository) GetCourierPerformance(courierID uuid.UUID) (*model.CourierPerformance, error) {
+	ctx := context.Background()
+
+	courier, err := p.store.GetCourierByID(ctx, courierID)
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get performance courier")
+		return nil, err
+	}
+
+	completed, err := p.store.GetCourierCompletedTrips(ctx, uuid.NullUUID{UUID: courierID, Valid: true})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier completed trips")
+		return nil, err
+	}
+
+	entries, err := p.store.GetCourierPoints(ctx, sqlc.GetCourierPointsParams{
+		CourierID: courierID,
+		Limit:     recentPoints,
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier points")
+		return nil, err
+	}
+
+	performance := &model.CourierPerformance{
+		CourierID:        courierID,
+		Points:           int(courier.Points),
+		Tier:             model.CourierTier(courier.Tier),
+		TierBonusPercent: p.p.TierBonusPercent(courier.Tier),
+		CompletedTrips:   int(completed),
+		Recent:           make([]*model.CourierPointsEntry, 0, len(entries)),
+	}
+	if courier.Rating.Valid {
+		performance.Rating = &courier.Rating.Float64
+	}
+	switch performance.Tier {
+	case model.CourierTierBronze:
+		next := model.CourierTierSilver
+		performance.NextTier = &next
+		performance.PointsToNextTier = silverTierPoints - performance.Points
+	case model.CourierTierSilver:
+		next := model.CourierTierGold
+		performance.NextTier = &next
+		performance.PointsToNextTier = goldTierPoints - performance.Points
+	}
+
+	for _, item := range entries {
+		entry := &model.CourierPointsEntry{
+			ID:        item.ID,
+			Reason:    model.PointsReason(item.Reason),
+			Points:    int(item.Points),
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.TripID.Valid {
+			entry.TripID = &item.TripID.UUID
+		}
+		performance.Recent = append(performance.Recent, entry)
+	}
+
+	return performance, nil
+}
+
+// ratingPoints - points for the rating a sender left the courier
+func ratingPoints(q *sqlc.Queries, courierID, tripID uuid.UUID, rating int) error {
+	switch {
+	case rating == 5:
+		return awardPoints(q, courierID, &tripID, model.PointsReasonHighRating, highRatingPoints)
+	case rating <= 2:
+		return awardPoints(q, courierID, &tripID, model.PointsReasonLowRating, lowRatingPoints)
+	}
+
+	return nil
+}
+
+// awardPoints - record a points change and move the courier tier along.
+// Must run inside store.WithTx.
+func awardPoints(
+	q *sqlc.Queries,
+	courierID uuid.UUID,
+	tripID *uuid.UUID,
+	reason model.PointsReason,
+	points int,
+) error {
+	ctx := context.Background()
+
+	if _, err := q.CreateCourierPoints(ctx, sqlc.CreateCourierPointsParams{
+		CourierID: courierID,
+		TripID:    nullUUID(tripID),
+		Reason:    reason.String(),
+		Points:    int32(points),
+	}); err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	total, err := q.AddCourierPoints(ctx, sqlc.AddCourierPointsParams{
+		ID:     courierID,
+		Points: int32(points),
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.SetCourierTier(ctx, sqlc.SetCourierTierParams{
+		ID:   courierID,
+		Tier: courierTier(int(total)).String(),
+	})
+}
+
+func courierTier(points int) model.CourierTier {
+	switch {
+	case points >= goldTierPoints:
+		return model.CourierTierGold
+	case points >= silverTierPoints:
+		return model.CourierTierSilver
+	default:
+		return model.CourierTierBronze
+	}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
@@ -66,7 +68,7 @@ func (p *PricerRepository) getCourierProduct(productID uuid.UUID) (*model.Produc
 	}, nil
 }
 
-func (p *PricerRepository) GetTripCost(trip model.Trip, distance int) (int, error) {
+func (p *PricerRepository) GetTripCost(trip model.Trip, route model.TripRoute) (int, error) {
 	if trip.CourierID.String() == internal.ZERO_UUID {
 		return 0, nil
 	}
@@ -75,7 +77,7 @@ func (p *PricerRepository) GetTripCost(trip model.Trip, distance int) (int, erro
 	if err != nil {
 		p.log.WithFields(logrus.Fields{
 			"error":    err,
-			"distance": distance,
+			"distance": route.Distance,
 		}).Errorf("get trip courier for trip cost calculation")
 		return 0, err
 	}
@@ -89,5 +91,113 @@ func (p *PricerRepository) GetTripCost(trip model.Trip, distance int) (int, erro
 		return 0, productErr
 	}
 
-	return p.pricer.CalculateTripCost(int(product.WeightClass), distance, product.Name != "UziX"), nil
+	return p.pricer.CalculateTripCost(
+		int(product.WeightClass),
+		route.Distance,
+		route.Duration,
+		route.StaticDuration,
+		product.Name != "UziX",
+	), nil
+}
+
+func (p *PricerRepository) getZoneByPoint(point model.Gps) (*uuid.UUID, error) {
+	zone, err := p.store.GetZoneByPoint(
+		context.Background(),
+		fmt.Sprintf("SRID=4326;POINT(%.8f %.8f)", point.Lng, point.Lat),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"point": point,
+		}).WithError(err).Errorf("get zone by point")
+		return nil, err
+	}
+
+	return &zone.ID, nil
+}
+
+func (p *PricerRepository) GetPricingMultiplier(
+	productID uuid.UUID,
+	pickup model.Gps,
+	at time.Time,
+) (float64, error) {
+	multipliers, err := p.GetPricingMultipliers([]uuid.UUID{productID}, pickup, at)
+	if err != nil {
+		return 1, err
+	}
+
+	return multipliers[productID], nil
+}
+
+// GetPricingMultipliers - schedule multiplier for each product, looking up
+// the pickup zone and holiday once for all of them
+func (p *PricerRepository) GetPricingMultipliers(
+	productIDs []uuid.UUID,
+	pickup model.Gps,
+	at time.Time,
+) (map[uuid.UUID]float64, error) {
+	multipliers := make(map[uuid.UUID]float64, len(productIDs))
+	for _, productID := range productIDs {
+		multipliers[productID] = 1
+	}
+
+	zoneID, zoneErr := p.getZoneByPoint(pickup)
+	if zoneErr != nil {
+		return multipliers, zoneErr
+	}
+
+	args := sqlc.GetActivePricingSchedulesParams{
+		At:         at.UTC(),
+		ProductIds: productIDs,
+	}
+	if zoneID != nil {
+		args.ZoneID = uuid.NullUUID{UUID: *zoneID, Valid: true}
+	}
+	schedules, err := p.store.GetActivePricingSchedules(context.Background(), args)
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"product_ids": productIDs,
+			"at":          at,
+		}).WithError(err).Errorf("get active pricing schedules")
+		return multipliers, err
+	}
+
+	if len(schedules) == 0 {
+		return multipliers, nil
+	}
+
+	local := internal.LocalTime(at)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	isHoliday, holidayErr := p.store.IsPublicHoliday(context.Background(), day)
+	if holidayErr != nil {
+		p.log.WithFields(logrus.Fields{
+			"at": at,
+		}).WithError(holidayErr).Errorf("is public holiday")
+		return multipliers, holidayErr
+	}
+
+	for _, productID := range productIDs {
+		rules := make([]internal.PricingSchedule, 0, len(schedules))
+		for _, item := range schedules {
+			if item.ProductID.Valid && item.ProductID.UUID != productID {
+				continue
+			}
+
+			rule := internal.PricingSchedule{
+				Kind:       item.Kind,
+				Multiplier: item.Multiplier,
+			}
+			if item.StartTime.Valid && item.EndTime.Valid {
+				rule.StartTime = &item.StartTime.Time
+				rule.EndTime = &item.EndTime.Time
+			}
+
+			rules = append(rules, rule)
+		}
+
+		multipliers[productID] = p.pricer.ScheduleMultiplier(rules, at, isHoliday)
+	}
+
+	return multipliers, nil
 }
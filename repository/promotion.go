@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPromoNotFound        = errors.New("promotion repository: promo code not found")
+	ErrPromoInactive        = errors.New("promotion repository: promo code is not active")
+	ErrPromoNotApplicable   = errors.New("promotion repository: promo code not valid for this trip")
+	ErrPromoUsageExceeded   = errors.New("promotion repository: promo code usage limit reached")
+	ErrPromoFirstTripOnly   = errors.New("promotion repository: promo code is valid on first trip only")
+	ErrPromoBudgetExhausted = errors.New("promotion repository: promotion budget exhausted")
+)
+
+type PromotionRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (p *PromotionRepository) Init(q *sqlc.Queries) {
+	p.store = q
+	p.log = internal.GetLogger()
+}
+
+func (p *PromotionRepository) ApplyPromoCode(
+	userID uuid.UUID,
+	input model.ApplyPromoCodeInput,
+) (*model.PromoQuote, error) {
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	promo, err := p.store.GetPromotionByCode(context.Background(), code)
+	if err == sql.ErrNoRows {
+		return nil, ErrPromoNotFound
+	} else if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"code":    code,
+			"user_id": userID,
+		}).WithError(err).Errorf("get promotion by code")
+		return nil, err
+	}
+
+	pickup := model.Gps{Lat: input.Pickup.Lat, Lng: input.Pickup.Lng}
+	discount, checkErr := p.checkPromotion(
+		p.store,
+		promo,
+		userID,
+		uuid.Nil,
+		input.ProductID,
+		pickup,
+		input.Price,
+		time.Now(),
+	)
+	if checkErr != nil {
+		return nil, checkErr
+	}
+
+	return &model.PromoQuote{
+		PromotionID: promo.ID,
+		Code:        promo.Code,
+		Description: promo.Description,
+		Discount:    discount,
+		Price:       input.Price - discount,
+		FundedBy:    model.DiscountFunder(promo.FundedBy),
+	}, nil
+}
+
+// RedeemPromotion - atomically spend promotion budget on the final trip cost
+func (p *PromotionRepository) RedeemPromotion(
+	promotionID uuid.UUID,
+	trip model.Trip,
+	price int,
+) (int, error) {
+	var discount int
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		// Lock the promotion so concurrent redemptions can't overspend
+		promo, err := q.GetPromotionForUpdate(ctx, promotionID)
+		if err != nil {
+			return err
+		}
+
+		discount, err = p.checkPromotion(
+			q,
+			promo,
+			trip.UserID,
+			trip.ID,
+			trip.ProductID,
+			*trip.StartLocation,
+			price,
+			*trip.CreatedAt,
+		)
+		if err != nil || discount == 0 {
+			return err
+		}
+
+		if _, err := q.SpendPromotionBudget(ctx, sqlc.SpendPromotionBudgetParams{
+			ID:     promo.ID,
+			Amount: int32(discount),
+		}); err != nil {
+			return err
+		}
+
+		if _, err := q.CreatePromotionRedemption(ctx, sqlc.CreatePromotionRedemptionParams{
+			PromotionID: promo.ID,
+			UserID:      trip.UserID,
+			TripID:      trip.ID,
+			Amount:      int32(discount),
+		}); err != nil {
+			return err
+		}
+
+		_, err = q.SetTripDiscount(ctx, sqlc.SetTripDiscountParams{
+			ID:       trip.ID,
+			Discount: int32(discount),
+			DiscountFundedBy: sql.NullString{
+				String: promo.FundedBy,
+				Valid:  true,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"promotion_id": promotionID,
+			"trip_id":      trip.ID,
+			"price":        price,
+		}).WithError(err).Errorf("redeem promotion")
+		return 0, err
+	}
+
+	return discount, nil
+}
+
+func (p *PromotionRepository) checkPromotion(
+	q *sqlc.Queries,
+	promo sqlc.Promotion,
+	userID, tripID, productID uuid.UUID,
+	pickup model.Gps,
+	price int,
+	at time.Time,
+) (int, error) {
+	ctx := context.Background()
+
+	if !promo.Active || at.Before(promo.StartsAt) ||
+		(promo.EndsAt.Valid && !at.Before(promo.EndsAt.Time)) {
+		return 0, ErrPromoInactive
+	}
+
+	if promo.ProductID.Valid && promo.ProductID.UUID != productID {
+		return 0, ErrPromoNotApplicable
+	}
+
+	if promo.ZoneID.Valid {
+		zone, err := q.GetZoneByPoint(
+			ctx,
+			fmt.Sprintf("SRID=4326;POINT(%.8f %.8f)", pickup.Lng, pickup.Lat),
+		)
+		if err == sql.ErrNoRows || (err == nil && zone.ID != promo.ZoneID.UUID) {
+			return 0, ErrPromoNotApplicable
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	// Zero per-user limit means unlimited uses
+	if promo.PerUserLimit > 0 {
+		uses, err := q.CountUserPromotionRedemptions(ctx, sqlc.CountUserPromotionRedemptionsParams{
+			PromotionID: promo.ID,
+			UserID:      userID,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if uses >= int64(promo.PerUserLimit) {
+			return 0, ErrPromoUsageExceeded
+		}
+	}
+
+	// Any live booking counts so concurrent first trips can't all qualify
+	if promo.FirstTripOnly {
+		booked, err := q.CountUserOtherTrips(ctx, sqlc.CountUserOtherTripsParams{
+			UserID: userID,
+			TripID: tripID,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if booked > 0 {
+			return 0, ErrPromoFirstTripOnly
+		}
+	}
+
+	discount := promotionDiscount(promo, price)
+	if promo.Budget.Valid && promo.BudgetUsed+int32(discount) > promo.Budget.Int32 {
+		return 0, ErrPromoBudgetExhausted
+	}
+
+	return discount, nil
+}
+
+func promotionDiscount(promo sqlc.Promotion, price int) int {
+	var discount int
+
+	switch model.DiscountType(promo.DiscountType) {
+	case model.DiscountTypePercent:
+		discount = price * int(promo.DiscountValue) / 100
+		if promo.MaxDiscount.Valid && discount > int(promo.MaxDiscount.Int32) {
+			discount = int(promo.MaxDiscount.Int32)
+		}
+	case model.DiscountTypeFixed:
+		discount = int(promo.DiscountValue)
+	}
+
+	if discount > price {
+		return price
+	}
+
+	return discount
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidQuest = errors.New("quest repository: quest needs a name, positive target and reward and a valid window")
+)
+
+type QuestRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (r *QuestRepository) Init(q *sqlc.Queries) {
+	r.store = q
+	r.log = internal.GetLogger()
+}
+
+func (r *QuestRepository) CreateQuest(input model.QuestInput) (*model.Quest, error) {
+	if input.Name == "" || input.TargetTrips <= 0 || input.Reward <= 0 ||
+		!input.EndsAt.After(input.StartsAt) {
+		return nil, ErrInvalidQuest
+	}
+
+	args := sqlc.CreateQuestParams{
+		Name:        input.Name,
+		TargetTrips: int32(input.TargetTrips),
+		Reward:      int32(input.Reward),
+		ZoneID:      nullUUID(input.ZoneID),
+		ProductID:   nullUUID(input.ProductID),
+		StartsAt:    input.StartsAt.UTC(),
+		EndsAt:      input.EndsAt.UTC(),
+	}
+	if input.Description != nil {
+		args.Description = *input.Description
+	}
+	if input.Tier != nil {
+		args.Tier = sql.NullString{String: input.Tier.String(), Valid: true}
+	}
+
+	quest, err := r.store.CreateQuest(context.Background(), args)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"name": input.Name,
+		}).WithError(err).Errorf("create quest")
+		return nil, err
+	}
+
+	return parseQuest(quest), nil
+}
+
+// RecordTripProgress - count a completed trip towards every quest it
+// qualifies for and pay out the quests it completes. Safe to call more than
+// once per trip.
+func (r *QuestRepository) RecordTripProgress(trip model.Trip) error {
+	ctx := context.Background()
+
+	if trip.CourierID == nil || trip.CourierID.String() == internal.ZERO_UUID {
+		return nil
+	}
+
+	courier, err := r.store.GetCourierByID(ctx, *trip.CourierID)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": *trip.CourierID,
+		}).WithError(err).Errorf("get quest courier")
+		return err
+	}
+
+	zoneID := uuid.NullUUID{}
+	if trip.StartLocation != nil {
+		zone, err := r.store.GetZoneByPoint(
+			ctx,
+			fmt.Sprintf("SRID=4326;POINT(%.8f %.8f)", trip.StartLocation.Lng, trip.StartLocation.Lat),
+		)
+		if err == nil {
+			zoneID = uuid.NullUUID{UUID: zone.ID, Valid: true}
+		} else if err != sql.ErrNoRows {
+			r.log.WithFields(logrus.Fields{
+				"trip_id": trip.ID,
+			}).WithError(err).Errorf("get quest trip zone")
+			return err
+		}
+	}
+
+	quests, err := r.store.GetEligibleQuests(ctx, sqlc.GetEligibleQuestsParams{
+		At:        time.Now().UTC(),
+		ProductID: uuid.NullUUID{UUID: trip.ProductID, Valid: true},
+		ZoneID:    zoneID,
+		Tier:      sql.NullString{String: courier.Tier, Valid: true},
+	})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("get eligible quests")
+		return err
+	}
+
+	for _, quest := range quests {
+		if err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+			return questTripProgress(q, quest, trip.ID, courier.ID, courier.UserID.UUID)
+		}); err != nil {
+			r.log.WithFields(logrus.Fields{
+				"trip_id":  trip.ID,
+				"quest_id": quest.ID,
+			}).WithError(err).Errorf("record quest progress")
+		}
+	}
+
+	return nil
+}
+
+// GetCourierQuests - running and upcoming quests a courier can take part in
+func (r *QuestRepository) GetCourierQuests(courierID uuid.UUID) ([]*model.CourierQuest, error) {
+	ctx := context.Background()
+	quests := make([]*model.CourierQuest, 0)
+
+	courier, err := r.store.GetCourierByID(ctx, courierID)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get quests courier")
+		return nil, err
+	}
+
+	found, err := r.store.GetCourierQuests(ctx, sqlc.GetCourierQuestsParams{
+		CourierID: courierID,
+		ProductID: courier.ProductID,
+		Tier:      sql.NullString{String: courier.Tier, Valid: true},
+	})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier quests")
+		return nil, err
+	}
+
+	for _, item := range found {
+		quest := &model.CourierQuest{
+			Quest: parseQuest(sqlc.Quest{
+				ID:          item.ID,
+				Name:        item.Name,
+				Description: item.Description,
+				TargetTrips: item.TargetTrips,
+				Reward:      item.Reward,
+				ZoneID:      item.ZoneID,
+				ProductID:   item.ProductID,
+				Tier:        item.Tier,
+				StartsAt:    item.StartsAt,
+				EndsAt:      item.EndsAt,
+			}),
+			Trips:     int(item.Progress),
+			Completed: item.CompletedAt.Valid,
+		}
+		if item.CompletedAt.Valid {
+			quest.CompletedAt = &item.CompletedAt.Time
+		}
+		quests = append(quests, quest)
+	}
+
+	return quests, nil
+}
+
+// questTripProgress - must run inside store.WithTx
+func questTripProgress(
+	q *sqlc.Queries,
+	quest sqlc.Quest,
+	tripID, courierID, courierUserID uuid.UUID,
+) error {
+	ctx := context.Background()
+
+	if _, err := q.CreateQuestTrip(ctx, sqlc.CreateQuestTripParams{
+		QuestID:   quest.ID,
+		TripID:    tripID,
+		CourierID: courierID,
+	}); err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	progress, err := q.AddQuestProgress(ctx, sqlc.AddQuestProgressParams{
+		QuestID:   quest.ID,
+		CourierID: courierID,
+	})
+	if err != nil {
+		return err
+	}
+	if progress.Trips < quest.TargetTrips || progress.CompletedAt.Valid {
+		return nil
+	}
+
+	if _, err := q.SetQuestCompleted(ctx, sqlc.SetQuestCompletedParams{
+		QuestID:   quest.ID,
+		CourierID: courierID,
+	}); err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindBonus,
+		reference:   fmt.Sprintf("quest:%s:%s", quest.ID, courierID),
+		description: quest.Name,
+		legs: []ledgerLeg{
+			userLeg(courierUserID, model.WalletAccountEarnings, int(quest.Reward)),
+			platformLeg(platformBonus, -int(quest.Reward)),
+		},
+	})
+
+	return err
+}
+
+func parseQuest(q sqlc.Quest) *model.Quest {
+	quest := &model.Quest{
+		ID:          q.ID,
+		Name:        q.Name,
+		Description: q.Description,
+		TargetTrips: int(q.TargetTrips),
+		Reward:      int(q.Reward),
+		StartsAt:    q.StartsAt,
+		EndsAt:      q.EndsAt,
+	}
+	if q.ZoneID.Valid {
+		quest.ZoneID = &q.ZoneID.UUID
+	}
+	if q.ProductID.Valid {
+		quest.ProductID = &q.ProductID.UUID
+	}
+	if q.Tier.Valid {
+		tier := model.CourierTier(q.Tier.String)
+		quest.Tier = &tier
+	}
+
+	return quest
+}
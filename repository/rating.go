@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Averages cover the most recent ratings only
+	ratingWindow     = 50
+	maxRatingTags    = 5
+	maxRatingTagSize = 30
+	maxRatingComment = 500
+)
+
+var (
+	ErrInvalidRating       = errors.New("rating repository: rating must be between 1 and 5")
+	ErrInvalidRatingReview = errors.New("rating repository: too many tags or review too long")
+	ErrRatingNotAllowed    = errors.New("rating repository: only trip sender and courier can rate a completed trip")
+	ErrAlreadyRated        = errors.New("rating repository: trip already rated")
+)
+
+type RatingRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (r *RatingRepository) Init(q *sqlc.Queries) {
+	r.store = q
+	r.log = internal.GetLogger()
+}
+
+// RateTrip - sender rates the courier or courier rates the sender
+// depending on who is rating
+func (r *RatingRepository) RateTrip(userID uuid.UUID, input model.TripRatingInput) (*model.TripRating, error) {
+	ctx := context.Background()
+
+	if input.Rating < 1 || input.Rating > 5 {
+		return nil, ErrInvalidRating
+	}
+
+	tags := make([]string, 0, len(input.Tags))
+	for _, tag := range input.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxRatingTagSize {
+			return nil, ErrInvalidRatingReview
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxRatingTags {
+		return nil, ErrInvalidRatingReview
+	}
+
+	comment := sql.NullString{}
+	if input.Comment != nil && strings.TrimSpace(*input.Comment) != "" {
+		if len(*input.Comment) > maxRatingComment {
+			return nil, ErrInvalidRatingReview
+		}
+		comment = sql.NullString{String: strings.TrimSpace(*input.Comment), Valid: true}
+	}
+
+	trip, courierUserID, err := r.ratedTrip(input.TripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != model.TripStatusComplete.String() || courierUserID == uuid.Nil {
+		return nil, ErrRatingNotAllowed
+	}
+
+	args := sqlc.CreateTripRatingParams{
+		TripID:  trip.ID,
+		RaterID: userID,
+		Rating:  int16(input.Rating),
+		Tags:    tags,
+		Comment: comment,
+	}
+	switch userID {
+	case trip.UserID:
+		args.Target = model.RatingTargetCourier.String()
+		args.RateeID = courierUserID
+	case courierUserID:
+		args.Target = model.RatingTargetSender.String()
+		args.RateeID = trip.UserID
+	default:
+		return nil, ErrRatingNotAllowed
+	}
+
+	var rating sqlc.TripRating
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		created, err := q.CreateTripRating(ctx, args)
+		if err == sql.ErrNoRows {
+			return ErrAlreadyRated
+		} else if err != nil {
+			return err
+		}
+		rating = created
+
+		if args.Target == model.RatingTargetCourier.String() {
+			if err := q.SetCourierRating(ctx, sqlc.SetCourierRatingParams{
+				UserID:     args.RateeID,
+				WindowSize: ratingWindow,
+			}); err != nil {
+				return err
+			}
+
+			return ratingPoints(q, trip.CourierID.UUID, trip.ID, input.Rating)
+		}
+
+		return q.SetUserRating(ctx, sqlc.SetUserRatingParams{
+			UserID:     args.RateeID,
+			WindowSize: ratingWindow,
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, ErrAlreadyRated) {
+			r.log.WithFields(logrus.Fields{
+				"trip_id": input.TripID,
+				"user_id": userID,
+			}).WithError(err).Errorf("rate trip")
+		}
+		return nil, err
+	}
+
+	return parseTripRating(rating), nil
+}
+
+// GetTripRatings - ratings left on a trip, visible to its sender and courier
+func (r *RatingRepository) GetTripRatings(userID, tripID uuid.UUID) ([]*model.TripRating, error) {
+	ratings := make([]*model.TripRating, 0)
+
+	trip, courierUserID, err := r.ratedTrip(tripID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != trip.UserID && userID != courierUserID {
+		return nil, ErrRatingNotAllowed
+	}
+
+	found, err := r.store.GetTripRatings(context.Background(), tripID)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get trip ratings")
+		return nil, err
+	}
+
+	for _, item := range found {
+		ratings = append(ratings, parseTripRating(item))
+	}
+
+	return ratings, nil
+}
+
+// ratedTrip - trip and the user behind its courier, if any
+func (r *RatingRepository) ratedTrip(tripID uuid.UUID) (*sqlc.GetTripRow, uuid.UUID, error) {
+	ctx := context.Background()
+
+	trip, err := r.store.GetTrip(ctx, tripID)
+	if err == sql.ErrNoRows {
+		return nil, uuid.Nil, ErrRatingNotAllowed
+	} else if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get rated trip")
+		return nil, uuid.Nil, err
+	}
+
+	if !trip.CourierID.Valid {
+		return &trip, uuid.Nil, nil
+	}
+
+	courier, err := r.store.GetCourierByID(ctx, trip.CourierID.UUID)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": trip.CourierID.UUID,
+		}).WithError(err).Errorf("get rated trip courier")
+		return nil, uuid.Nil, err
+	}
+
+	return &trip, courier.UserID.UUID, nil
+}
+
+func parseTripRating(r sqlc.TripRating) *model.TripRating {
+	rating := &model.TripRating{
+		ID:        r.ID,
+		TripID:    r.TripID,
+		Target:    model.RatingTarget(r.Target),
+		Rating:    int(r.Rating),
+		Tags:      r.Tags,
+		CreatedAt: &r.CreatedAt,
+	}
+	if r.Comment.Valid {
+		rating.Comment = &r.Comment.String
+	}
+
+	return rating
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Referral kinds and statuses
+const (
+	userReferral      = "USER"
+	courierReferral   = "COURIER"
+	referralPending   = "PENDING"
+	referralRejected  = "REJECTED"
+	referralCodeChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+type ReferralRepository struct {
+	store  *sqlc.Queries
+	config config.Referral
+	log    *logrus.Logger
+}
+
+func (r *ReferralRepository) Init(q *sqlc.Queries) {
+	r.store = q
+	r.config = config.Config.Referral
+	r.log = internal.GetLogger()
+}
+
+// AttributeReferral - link a new user to whoever referred them
+func (r *ReferralRepository) AttributeReferral(userID uuid.UUID, signin SigninInput) error {
+	code := strings.ToUpper(strings.TrimSpace(signin.ReferralCode))
+	if len(code) == 0 {
+		return nil
+	}
+
+	_, existingErr := r.store.GetUserReferral(context.Background(), userID)
+	if existingErr == nil {
+		return nil
+	} else if existingErr != sql.ErrNoRows {
+		r.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(existingErr).Errorf("get user referral")
+		return existingErr
+	}
+
+	referrer, referrerErr := r.store.GetUserByReferralCode(
+		context.Background(),
+		sql.NullString{String: code, Valid: true},
+	)
+	if referrerErr == sql.ErrNoRows {
+		r.log.WithFields(logrus.Fields{
+			"user_id":       userID,
+			"referral_code": code,
+		}).Warnln("unknown referral code")
+		return nil
+	} else if referrerErr != nil {
+		r.log.WithFields(logrus.Fields{
+			"referral_code": code,
+		}).WithError(referrerErr).Errorf("get user by referral code")
+		return referrerErr
+	}
+
+	status, statusErr := r.referralStatus(referrer, userID, signin)
+	if statusErr != nil {
+		return statusErr
+	}
+
+	kind := userReferral
+	if signin.Courier {
+		if _, courierErr := r.store.GetCourierByUserID(
+			context.Background(),
+			uuid.NullUUID{UUID: referrer.ID, Valid: true},
+		); courierErr == nil {
+			kind = courierReferral
+		}
+	}
+
+	args := sqlc.CreateReferralParams{
+		ReferrerID: referrer.ID,
+		RefereeID:  userID,
+		Kind:       kind,
+		Status:     status,
+		DeviceID: sql.NullString{
+			String: signin.DeviceID,
+			Valid:  len(signin.DeviceID) > 0,
+		},
+	}
+	if _, err := r.store.CreateReferral(context.Background(), args); err != nil {
+		r.log.WithFields(logrus.Fields{
+			"referrer_id": referrer.ID,
+			"referee_id":  userID,
+		}).WithError(err).Errorf("create referral")
+		return err
+	}
+
+	return nil
+}
+
+// Guard against self-referrals from the same phone or device
+func (r *ReferralRepository) referralStatus(
+	referrer sqlc.User,
+	userID uuid.UUID,
+	signin SigninInput,
+) (string, error) {
+	if referrer.ID == userID || referrer.Phone == signin.Phone {
+		return referralRejected, nil
+	}
+
+	if len(signin.DeviceID) == 0 {
+		return referralPending, nil
+	}
+
+	if referrer.DeviceID.Valid && referrer.DeviceID.String == signin.DeviceID {
+		return referralRejected, nil
+	}
+
+	deviceReferred, err := r.store.IsDeviceReferred(
+		context.Background(),
+		sql.NullString{String: signin.DeviceID, Valid: true},
+	)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"device_id": signin.DeviceID,
+		}).WithError(err).Errorf("is device referred")
+		return referralRejected, err
+	}
+
+	if deviceReferred {
+		return referralRejected, nil
+	}
+
+	return referralPending, nil
+}
+
+// RewardTripReferrals - evaluate referral reward rules on trip completion
+func (r *ReferralRepository) RewardTripReferrals(trip model.Trip) error {
+	// Senders are rewarded on the referee first completed trip
+	completed, err := r.store.CountUserCompletedTrips(context.Background(), trip.UserID)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"user_id": trip.UserID,
+		}).WithError(err).Errorf("count user completed trips")
+		return err
+	}
+
+	if completed >= 1 {
+		if err := r.rewardReferral(trip.UserID, userReferral, r.config.SenderReward, true); err != nil {
+			return err
+		}
+	}
+
+	if trip.CourierID == nil || trip.CourierID.String() == internal.ZERO_UUID {
+		return nil
+	}
+
+	courier, courierErr := r.store.GetCourierByID(context.Background(), *trip.CourierID)
+	if courierErr != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": trip.CourierID,
+		}).WithError(courierErr).Errorf("get referral courier")
+		return courierErr
+	}
+
+	courierTrips, courierTripsErr := r.store.CountCourierCompletedTrips(
+		context.Background(),
+		uuid.NullUUID{UUID: courier.ID, Valid: true},
+	)
+	if courierTripsErr != nil {
+		r.log.WithFields(logrus.Fields{
+			"courier_id": courier.ID,
+		}).WithError(courierTripsErr).Errorf("count courier completed trips")
+		return courierTripsErr
+	}
+
+	if courierTrips >= int64(r.config.CourierTripTarget) {
+		return r.rewardReferral(courier.UserID.UUID, courierReferral, r.config.CourierReward, false)
+	}
+
+	return nil
+}
+
+func (r *ReferralRepository) rewardReferral(
+	refereeID uuid.UUID,
+	kind string,
+	amount int,
+	rewardReferee bool,
+) error {
+	ctx := context.Background()
+
+	referral, err := r.store.GetPendingReferral(ctx, sqlc.GetPendingReferralParams{
+		RefereeID: refereeID,
+		Kind:      kind,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"referee_id": refereeID,
+			"kind":       kind,
+		}).WithError(err).Errorf("get pending referral")
+		return err
+	}
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		// Only the first completion to flip the referral gets to credit
+		if _, err := q.RewardReferral(ctx, referral.ID); err == sql.ErrNoRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Couriers are paid out their referral bonus with their earnings
+		account, txKind := model.WalletAccountPromo, model.LedgerTransactionKindReferralReward
+		if kind == courierReferral {
+			account, txKind = model.WalletAccountEarnings, model.LedgerTransactionKindBonus
+		}
+
+		legs := []ledgerLeg{
+			userLeg(referral.ReferrerID, account, amount),
+		}
+		if rewardReferee {
+			legs = append(legs, userLeg(referral.RefereeID, model.WalletAccountPromo, amount))
+		}
+		legs = append(legs, platformLeg(platformReferral, -amount*len(legs)))
+
+		_, err := postLedger(q, ledgerPosting{
+			kind:        txKind,
+			reference:   fmt.Sprintf("referral:%s", referral.ID),
+			description: "Referral reward",
+			legs:        legs,
+		})
+		return err
+	})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"referral_id": referral.ID,
+			"amount":      amount,
+		}).WithError(err).Errorf("reward referral")
+		return err
+	}
+
+	return nil
+}
+
+// ApplyPromoBalance - discount the trip with the user promo credit
+func (r *ReferralRepository) ApplyPromoBalance(trip model.Trip, cost int) (int, error) {
+	var applied int
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		promo, err := lockUserAccount(q, trip.UserID, model.WalletAccountPromo)
+		if err != nil || promo.Balance <= 0 {
+			return err
+		}
+
+		tripDiscount, err := q.GetTripDiscountForUpdate(ctx, trip.ID)
+		if err != nil {
+			return err
+		}
+
+		applied = cost - int(tripDiscount.Discount)
+		if int(promo.Balance) < applied {
+			applied = int(promo.Balance)
+		}
+		if applied <= 0 {
+			applied = 0
+			return nil
+		}
+
+		posted, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindPromoCredit,
+			reference:   fmt.Sprintf("promo:%s", trip.ID),
+			tripID:      &trip.ID,
+			description: "Promo credit on trip",
+			legs: []ledgerLeg{
+				userLeg(trip.UserID, model.WalletAccountPromo, -applied),
+				platformLeg(platformPromo, applied),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		// Credit was already applied to this trip
+		if !posted {
+			applied = 0
+			return nil
+		}
+
+		fundedBy := tripDiscount.DiscountFundedBy
+		if !fundedBy.Valid {
+			fundedBy = sql.NullString{String: model.DiscountFunderPlatform.String(), Valid: true}
+		}
+		_, err = q.SetTripDiscount(ctx, sqlc.SetTripDiscountParams{
+			ID:               trip.ID,
+			Discount:         tripDiscount.Discount + int32(applied),
+			DiscountFundedBy: fundedBy,
+		})
+		return err
+	})
+	if err != nil {
+		r.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+			"user_id": trip.UserID,
+		}).WithError(err).Errorf("apply promo balance")
+		return 0, err
+	}
+
+	return applied, nil
+}
+
+func newReferralCode() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	for i := range b {
+		b[i] = referralCodeChars[int(b[i])%len(referralCodeChars)]
+	}
+
+	return string(b)
+}
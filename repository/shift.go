@@ -0,0 +1,489 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Bookings can be cancelled up to this long before the shift starts
+	shiftCancelCutoff = 2 * time.Hour
+	// Couriers can check in this long before the shift starts
+	shiftCheckInWindow = 15 * time.Minute
+)
+
+var (
+	ErrInvalidShift         = errors.New("shift repository: shift needs a zone, positive capacity and a future window")
+	ErrShiftNotFound        = errors.New("shift repository: shift not found")
+	ErrShiftBookingNotFound = errors.New("shift repository: shift booking not found")
+	ErrShiftStarted         = errors.New("shift repository: shift already started")
+	ErrShiftFull            = errors.New("shift repository: shift is fully booked")
+	ErrShiftOverlap         = errors.New("shift repository: courier already booked an overlapping shift")
+	ErrShiftNotVerified     = errors.New("shift repository: only verified couriers can book shifts")
+	ErrShiftCancelTooLate   = errors.New("shift repository: bookings can only be cancelled 2 hours before the shift")
+	ErrShiftBookingClosed   = errors.New("shift repository: booking is no longer open")
+	ErrShiftCheckInClosed   = errors.New("shift repository: check in opens 15 minutes before the shift and closes when it ends")
+	ErrNotInShiftZone       = errors.New("shift repository: courier needs a fresh location inside the shift zone")
+)
+
+type ShiftRepository struct {
+	store  *sqlc.Queries
+	config config.Courier
+	log    *logrus.Logger
+}
+
+func (s *ShiftRepository) Init(q *sqlc.Queries) {
+	s.store = q
+	s.config = config.Config.Courier
+	s.log = internal.GetLogger()
+}
+
+func (s *ShiftRepository) CreateShift(input model.ShiftInput) (*model.Shift, error) {
+	ctx := context.Background()
+
+	guarantee := 0
+	if input.Guarantee != nil {
+		guarantee = *input.Guarantee
+	}
+	if input.Capacity <= 0 || guarantee < 0 ||
+		!input.EndsAt.After(input.StartsAt) || !input.StartsAt.After(time.Now()) {
+		return nil, ErrInvalidShift
+	}
+
+	zoneName, err := s.store.GetZoneName(ctx, input.ZoneID)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidShift
+	} else if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"zone_id": input.ZoneID,
+		}).WithError(err).Errorf("get shift zone")
+		return nil, err
+	}
+
+	shift, err := s.store.CreateShift(ctx, sqlc.CreateShiftParams{
+		ZoneID:    input.ZoneID,
+		StartsAt:  input.StartsAt.UTC(),
+		EndsAt:    input.EndsAt.UTC(),
+		Capacity:  int32(input.Capacity),
+		Guarantee: int32(guarantee),
+	})
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"zone_id": input.ZoneID,
+		}).WithError(err).Errorf("create shift")
+		return nil, err
+	}
+
+	return parseShift(shift, zoneName, 0), nil
+}
+
+// GetUpcomingShifts - shifts couriers can still book, optionally in a zone
+func (s *ShiftRepository) GetUpcomingShifts(zoneID *uuid.UUID) ([]*model.Shift, error) {
+	shifts := make([]*model.Shift, 0)
+
+	found, err := s.store.GetUpcomingShifts(context.Background(), sqlc.GetUpcomingShiftsParams{
+		Now:    time.Now().UTC(),
+		ZoneID: nullUUID(zoneID),
+	})
+	if err != nil {
+		s.log.WithError(err).Errorf("get upcoming shifts")
+		return nil, err
+	}
+
+	for _, item := range found {
+		shifts = append(shifts, parseShift(item.Shift, item.ZoneName, item.Booked))
+	}
+
+	return shifts, nil
+}
+
+func (s *ShiftRepository) GetCourierShiftBookings(courierID uuid.UUID, limit, offset int) ([]*model.ShiftBooking, error) {
+	bookings := make([]*model.ShiftBooking, 0)
+
+	found, err := s.store.GetCourierShiftBookings(context.Background(), sqlc.GetCourierShiftBookingsParams{
+		CourierID: courierID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier shift bookings")
+		return nil, err
+	}
+
+	for _, item := range found {
+		bookings = append(bookings, parseShiftBooking(
+			item.ShiftBooking,
+			parseShift(item.Shift, item.ZoneName, item.Booked),
+		))
+	}
+
+	return bookings, nil
+}
+
+// BookShift - reserve a place on a shift that hasn't started and has room
+func (s *ShiftRepository) BookShift(courierID, shiftID uuid.UUID) (*model.ShiftBooking, error) {
+	ctx := context.Background()
+
+	var booking sqlc.ShiftBooking
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		courier, err := q.GetCourierOnboarding(ctx, courierID)
+		if err != nil {
+			return err
+		}
+		if !courier.Verified.Bool {
+			return ErrShiftNotVerified
+		}
+
+		shift, err := q.GetShiftForUpdate(ctx, shiftID)
+		if err == sql.ErrNoRows {
+			return ErrShiftNotFound
+		} else if err != nil {
+			return err
+		}
+		if !shift.StartsAt.After(time.Now().UTC()) {
+			return ErrShiftStarted
+		}
+
+		booked, err := q.CountShiftBookings(ctx, shift.ID)
+		if err != nil {
+			return err
+		}
+		if booked >= int64(shift.Capacity) {
+			return ErrShiftFull
+		}
+
+		overlapping, err := q.CountOverlappingShiftBookings(ctx, sqlc.CountOverlappingShiftBookingsParams{
+			CourierID: courierID,
+			StartsAt:  shift.StartsAt,
+			EndsAt:    shift.EndsAt,
+		})
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrShiftOverlap
+		}
+
+		booking, err = q.CreateShiftBooking(ctx, sqlc.CreateShiftBookingParams{
+			ShiftID:   shift.ID,
+			CourierID: courierID,
+		})
+		return err
+	})
+	if err != nil {
+		if !isShiftRuleErr(err) {
+			s.log.WithFields(logrus.Fields{
+				"courier_id": courierID,
+				"shift_id":   shiftID,
+			}).WithError(err).Errorf("book shift")
+		}
+		return nil, err
+	}
+
+	return s.getShiftBooking(booking.ID)
+}
+
+// CancelShiftBooking - give the place back while there's time to fill it
+func (s *ShiftRepository) CancelShiftBooking(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		booking, shift, err := lockCourierShiftBooking(q, courierID, bookingID)
+		if err != nil {
+			return err
+		}
+		if booking.Status != model.ShiftBookingStatusBooked.String() {
+			return ErrShiftBookingClosed
+		}
+
+		now := time.Now().UTC()
+		if shift.StartsAt.Sub(now) < shiftCancelCutoff {
+			return ErrShiftCancelTooLate
+		}
+
+		_, err = q.CancelShiftBooking(ctx, sqlc.CancelShiftBookingParams{
+			ID:          booking.ID,
+			CancelledAt: sql.NullTime{Time: now, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if !isShiftRuleErr(err) {
+			s.log.WithFields(logrus.Fields{
+				"courier_id": courierID,
+				"booking_id": bookingID,
+			}).WithError(err).Errorf("cancel shift booking")
+		}
+		return nil, err
+	}
+
+	return s.getShiftBooking(bookingID)
+}
+
+// CheckInShift - start working a booked shift from inside its zone
+func (s *ShiftRepository) CheckInShift(courierID, bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		booking, shift, err := lockCourierShiftBooking(q, courierID, bookingID)
+		if err != nil {
+			return err
+		}
+		if booking.Status != model.ShiftBookingStatusBooked.String() {
+			return ErrShiftBookingClosed
+		}
+
+		now := time.Now().UTC()
+		if now.Before(shift.StartsAt.Add(-shiftCheckInWindow)) || !now.Before(shift.EndsAt) {
+			return ErrShiftCheckInClosed
+		}
+
+		inZone, err := q.IsCourierInZone(ctx, sqlc.IsCourierInZoneParams{
+			CourierID:    courierID,
+			ZoneID:       shift.ZoneID,
+			LocatedAfter: sql.NullTime{Time: now.Add(-s.config.HeartbeatTimeout), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if !inZone {
+			return ErrNotInShiftZone
+		}
+
+		_, err = q.CheckInShiftBooking(ctx, sqlc.CheckInShiftBookingParams{
+			ID:          booking.ID,
+			CheckedInAt: sql.NullTime{Time: now, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if !isShiftRuleErr(err) {
+			s.log.WithFields(logrus.Fields{
+				"courier_id": courierID,
+				"booking_id": bookingID,
+			}).WithError(err).Errorf("check in shift")
+		}
+		return nil, err
+	}
+
+	return s.getShiftBooking(bookingID)
+}
+
+// SettleEndedShifts - close bookings on shifts that are over. Couriers who
+// never checked in are no-shows, those who did have their trip earnings during
+// the shift topped up to the shift guarantee, pro-rated by how long they were
+// online during it.
+func (s *ShiftRepository) SettleEndedShifts(now time.Time) error {
+	ctx := context.Background()
+
+	ended, err := s.store.GetEndedShiftBookings(ctx, now.UTC())
+	if err != nil {
+		s.log.WithError(err).Errorf("get ended shift bookings")
+		return err
+	}
+
+	for _, bookingID := range ended {
+		if err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+			return settleShiftBooking(q, bookingID, now.UTC())
+		}); err != nil {
+			s.log.WithFields(logrus.Fields{
+				"booking_id": bookingID,
+			}).WithError(err).Errorf("settle shift booking")
+		}
+	}
+
+	return nil
+}
+
+func (s *ShiftRepository) getShiftBooking(bookingID uuid.UUID) (*model.ShiftBooking, error) {
+	item, err := s.store.GetShiftBooking(context.Background(), bookingID)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"booking_id": bookingID,
+		}).WithError(err).Errorf("get shift booking")
+		return nil, err
+	}
+
+	return parseShiftBooking(
+		item.ShiftBooking,
+		parseShift(item.Shift, item.ZoneName, item.Booked),
+	), nil
+}
+
+// lockCourierShiftBooking - must run inside store.WithTx
+func lockCourierShiftBooking(
+	q *sqlc.Queries,
+	courierID, bookingID uuid.UUID,
+) (*sqlc.ShiftBooking, *sqlc.Shift, error) {
+	ctx := context.Background()
+
+	booking, err := q.GetShiftBookingForUpdate(ctx, bookingID)
+	if err == sql.ErrNoRows || (err == nil && booking.CourierID != courierID) {
+		return nil, nil, ErrShiftBookingNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	shift, err := q.GetShiftForUpdate(ctx, booking.ShiftID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &booking, &shift, nil
+}
+
+// settleShiftBooking - must run inside store.WithTx
+func settleShiftBooking(q *sqlc.Queries, bookingID uuid.UUID, now time.Time) error {
+	ctx := context.Background()
+
+	booking, err := q.GetShiftBookingForUpdate(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	switch booking.Status {
+	case model.ShiftBookingStatusBooked.String():
+		_, err = q.SettleShiftBooking(ctx, sqlc.SettleShiftBookingParams{
+			ID:        booking.ID,
+			Status:    model.ShiftBookingStatusNoShow.String(),
+			SettledAt: sql.NullTime{Time: now, Valid: true},
+		})
+		return err
+	case model.ShiftBookingStatusCheckedIn.String():
+	default:
+		return nil
+	}
+
+	shift, err := q.GetShiftForUpdate(ctx, booking.ShiftID)
+	if err != nil {
+		return err
+	}
+
+	courier, err := q.GetCourierByID(ctx, booking.CourierID)
+	if err != nil {
+		return err
+	}
+
+	earned, err := q.GetCourierEarningsBetween(ctx, sqlc.GetCourierEarningsBetweenParams{
+		UserID:  courier.UserID,
+		StartAt: shift.StartsAt,
+		EndAt:   shift.EndsAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The guarantee covers the part of the shift spent ONLINE or BUSY
+	online, err := q.GetCourierOnlineSecondsBetween(ctx, sqlc.GetCourierOnlineSecondsBetweenParams{
+		CourierID: courier.ID,
+		StartAt:   shift.StartsAt,
+		EndAt:     shift.EndsAt,
+	})
+	if err != nil {
+		return err
+	}
+	guarantee := shiftGuarantee(int(shift.Guarantee), time.Duration(online)*time.Second, shift.EndsAt.Sub(shift.StartsAt))
+
+	topUp := guarantee - int(earned)
+	if topUp > 0 {
+		if _, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindBonus,
+			reference:   fmt.Sprintf("shift:%s", booking.ID),
+			description: "Shift earnings guarantee",
+			legs: []ledgerLeg{
+				userLeg(courier.UserID.UUID, model.WalletAccountEarnings, topUp),
+				platformLeg(platformBonus, -topUp),
+			},
+		}); err != nil {
+			return err
+		}
+	} else {
+		topUp = 0
+	}
+
+	_, err = q.SettleShiftBooking(ctx, sqlc.SettleShiftBookingParams{
+		ID:            booking.ID,
+		Status:        model.ShiftBookingStatusCompleted.String(),
+		Earned:        earned,
+		GuaranteePaid: int32(topUp),
+		SettledAt:     sql.NullTime{Time: now, Valid: true},
+	})
+
+	return err
+}
+
+// shiftGuarantee - the guarantee pro-rated by time online during the shift
+func shiftGuarantee(guarantee int, online, length time.Duration) int {
+	if length <= 0 || online >= length {
+		return guarantee
+	}
+	if online <= 0 {
+		return 0
+	}
+
+	return int(int64(guarantee) * int64(online) / int64(length))
+}
+
+func isShiftRuleErr(err error) bool {
+	for _, ruleErr := range []error{
+		ErrShiftNotFound,
+		ErrShiftBookingNotFound,
+		ErrShiftStarted,
+		ErrShiftFull,
+		ErrShiftOverlap,
+		ErrShiftNotVerified,
+		ErrShiftCancelTooLate,
+		ErrShiftBookingClosed,
+		ErrShiftCheckInClosed,
+		ErrNotInShiftZone,
+	} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseShift(s sqlc.Shift, zoneName string, booked int32) *model.Shift {
+	return &model.Shift{
+		ID:        s.ID,
+		ZoneID:    s.ZoneID,
+		ZoneName:  zoneName,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		Capacity:  int(s.Capacity),
+		Booked:    int(booked),
+		Guarantee: int(s.Guarantee),
+	}
+}
+
+func parseShiftBooking(b sqlc.ShiftBooking, shift *model.Shift) *model.ShiftBooking {
+	booking := &model.ShiftBooking{
+		ID:            b.ID,
+		CourierID:     b.CourierID,
+		Shift:         shift,
+		Status:        model.ShiftBookingStatus(b.Status),
+		Earned:        int(b.Earned),
+		GuaranteePaid: int(b.GuaranteePaid),
+		CreatedAt:     &b.CreatedAt,
+	}
+	if b.CheckedInAt.Valid {
+		booking.CheckedInAt = &b.CheckedInAt.Time
+	}
+
+	return booking
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrTipNotAllowed     = errors.New("payment repository: only completed trips can be tipped")
+	ErrInvalidTipAmount  = errors.New("payment repository: tip amount must be positive")
+	ErrTripAlreadyTipped = errors.New("payment repository: a trip can only be tipped once")
+)
+
+// CreateTip - tip the trip courier through the method the trip was paid with.
+// Trips paid in cash or not paid at all are tipped from the wallet. The
+// payments table keeps a single pending or paid tip per trip.
+func (p *PaymentRepository) CreateTip(
+	userID uuid.UUID,
+	trip model.Trip,
+	amount int,
+) (*model.Payment, error) {
+	ctx := context.Background()
+
+	if trip.UserID != userID {
+		return nil, ErrPaymentTripNotAllowed
+	}
+	if trip.Status != model.TripStatusComplete ||
+		trip.CourierID == nil ||
+		trip.CourierID.String() == internal.ZERO_UUID {
+		return nil, ErrTipNotAllowed
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidTipAmount
+	}
+
+	tripPayment, err := p.store.GetTripPayment(ctx, uuid.NullUUID{UUID: trip.ID, Valid: true})
+	if err != nil && err != sql.ErrNoRows {
+		p.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("get tipped trip payment")
+		return nil, err
+	}
+
+	if err == nil {
+		switch model.PaymentProvider(tripPayment.Provider) {
+		case model.PaymentProviderMpesa:
+			return p.mpesaCharge(userID, &trip.ID, model.PaymentPurposeTip, amount, tripPayment.Phone.String)
+		case model.PaymentProviderPaystack:
+			// Same card as the trip, or a checkout if it wasn't saved
+			var cardID *uuid.UUID
+			if tripPayment.CardID.Valid {
+				cardID = &tripPayment.CardID.UUID
+			}
+			return p.cardCharge(userID, &trip.ID, model.PaymentPurposeTip, amount, cardID)
+		}
+	}
+
+	return p.walletTip(userID, trip, amount)
+}
+
+func (p *PaymentRepository) walletTip(
+	userID uuid.UUID,
+	trip model.Trip,
+	amount int,
+) (*model.Payment, error) {
+	var payment sqlc.Payment
+	ctx := context.Background()
+
+	courier, err := p.store.GetCourierByID(ctx, *trip.CourierID)
+	if err != nil {
+		p.log.WithFields(logrus.Fields{
+			"courier_id": *trip.CourierID,
+		}).WithError(err).Errorf("get tip courier")
+		return nil, err
+	}
+
+	err = store.WithTx(ctx, func(q *sqlc.Queries) error {
+		created, err := q.CreatePayment(ctx, sqlc.CreatePaymentParams{
+			TripID:   uuid.NullUUID{UUID: trip.ID, Valid: true},
+			UserID:   userID,
+			Provider: model.PaymentProviderWallet.String(),
+			Purpose:  model.PaymentPurposeTip.String(),
+			Amount:   int32(amount),
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindTip,
+			reference:   fmt.Sprintf("tip:%s", trip.ID),
+			tripID:      &trip.ID,
+			paymentID:   &created.ID,
+			description: "Trip tip",
+			legs: []ledgerLeg{
+				userLeg(userID, model.WalletAccountCash, -amount),
+				userLeg(courier.UserID.UUID, model.WalletAccountEarnings, amount),
+			},
+		}); err != nil {
+			return err
+		}
+
+		payment, err = q.SetPaymentStatus(ctx, sqlc.SetPaymentStatusParams{
+			ID:     created.ID,
+			Status: model.PaymentStatusPaid.String(),
+		})
+		return err
+	})
+	if err != nil {
+		if openErr := openPaymentConflict(model.PaymentPurposeTip, err); openErr != nil {
+			return nil, openErr
+		}
+		if !errors.Is(err, ErrInsufficientBalance) {
+			p.log.WithFields(logrus.Fields{
+				"trip_id": trip.ID,
+				"amount":  amount,
+			}).WithError(err).Errorf("create wallet tip")
+		}
+		return nil, err
+	}
+
+	return parsePayment(payment), nil
+}
+
+// settleTip - the whole tip goes to the courier once it's paid
+func settleTip(q *sqlc.Queries, payment sqlc.Payment) error {
+	ctx := context.Background()
+
+	trip, err := q.GetTrip(ctx, payment.TripID.UUID)
+	if err != nil {
+		return err
+	}
+
+	courier, err := q.GetCourierByID(ctx, trip.CourierID.UUID)
+	if err != nil {
+		return err
+	}
+
+	_, err = postLedger(q, ledgerPosting{
+		kind:        model.LedgerTransactionKindTip,
+		reference:   fmt.Sprintf("tip:%s", trip.ID),
+		tripID:      &trip.ID,
+		paymentID:   &payment.ID,
+		description: "Trip tip",
+		legs: []ledgerLeg{
+			platformLeg(platformCash, -int(payment.Amount)),
+			userLeg(courier.UserID.UUID, model.WalletAccountEarnings, int(payment.Amount)),
+		},
+	})
+
+	return err
+}
@@ -6,26 +6,36 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
 	"github.com/edwinlomolo/uzi-api/store/sqlc"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+// pickupRadius - meters around pickup couriers are matched from
+const pickupRadius = 2000
+
 var (
 	ErrCourierAlreadyAssigned = errors.New("trip repository: courier has active trip")
 	ErrCourierTripNotFound    = errors.New("trip repository: courier trip not found")
+	ErrTripCancelNotAllowed   = errors.New("trip repository: trip can't be cancelled after pickup")
+	ErrTripPathNotAllowed     = errors.New("trip repository: only the trip customer and courier can see its path")
+	ErrTripCompleteNotAllowed = errors.New("trip repository: only the assigned courier can complete a trip en route")
 )
 
 type TripRepository struct {
 	redis    *redis.Client
 	location internal.LocationController
 	cache    internal.Cache
+	index    internal.CourierIndex
 	mu       sync.Mutex
 	p        internal.Pricing
+	pricer   *PricerRepository
 	store    *sqlc.Queries
 	log      *logrus.Logger
 }
@@ -36,18 +46,39 @@ func (t *TripRepository) Init(q *sqlc.Queries) {
 	t.redis = internal.GetCache().GetRedis()
 	t.location = internal.GetLocationController()
 	t.cache = internal.GetCache()
+	t.index = internal.GetCourierIndex()
 	t.mu = sync.Mutex{}
 	t.p = internal.GetPricer()
+	t.pricer = pr
 	t.store = q
 	t.log = internal.GetLogger()
 }
 
-func (t *TripRepository) FindAvailableCourier(pickup model.GpsInput) (*model.Courier, error) {
+func (t *TripRepository) FindAvailableCourier(
+	pickup model.GpsInput,
+	productID uuid.UUID,
+) (*model.Courier, error) {
+	ctx := context.Background()
+
+	nearby, err := t.index.NearbyCouriers(ctx, pickup, pickupRadius)
+	if err != nil || len(nearby) == 0 {
+		return nil, err
+	}
+
 	args := sqlc.FindAvailableCourierParams{
-		Point:  fmt.Sprintf("SRID=4326;POINT(%.8f %.8f)", pickup.Lng, pickup.Lat),
-		Radius: 2000,
+		Ids:       make([]uuid.UUID, len(nearby)),
+		Distances: make([]float64, len(nearby)),
+		Now:       time.Now().UTC(),
+		ProductID: uuid.NullUUID{UUID: productID, Valid: true},
+	}
+	locations := make(map[uuid.UUID]model.Gps, len(nearby))
+	for i, item := range nearby {
+		args.Ids[i] = item.ID
+		args.Distances[i] = item.Distance
+		locations[item.ID] = item.Location
 	}
-	c, err := t.store.FindAvailableCourier(context.Background(), args)
+
+	c, err := t.store.FindAvailableCourier(ctx, args)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	} else if err != nil {
@@ -55,11 +86,12 @@ func (t *TripRepository) FindAvailableCourier(pickup model.GpsInput) (*model.Cou
 		return nil, err
 	}
 
+	location := locations[c.ID]
 	return &model.Courier{
 		ID:        c.ID,
 		UserID:    c.UserID.UUID,
 		ProductID: c.ProductID.UUID,
-		Location:  model.ParsePostgisLocation(c.Location),
+		Location:  &location,
 	}, nil
 }
 
@@ -74,6 +106,7 @@ func (t *TripRepository) AssignCourierToTrip(tripID, courierID uuid.UUID) error
 		return err
 	}
 
+	ctx := context.Background()
 	args := sqlc.AssignCourierToTripParams{
 		ID: courierID,
 		TripID: uuid.NullUUID{
@@ -81,58 +114,103 @@ func (t *TripRepository) AssignCourierToTrip(tripID, courierID uuid.UUID) error
 			Valid: true,
 		},
 	}
-	_, assignCourierErr := t.store.AssignCourierToTrip(
-		context.Background(),
-		args,
-	)
-	if assignCourierErr != nil {
+	courierArgs := sqlc.AssignTripToCourierParams{
+		ID:        tripID,
+		CourierID: courierID,
+	}
+
+	// Couriers stay busy until the trip is done with them
+	if assignErr := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.AssignCourierToTrip(ctx, args); err != nil {
+			return err
+		}
+
+		if _, err := q.AssignTripToCourier(ctx, courierArgs); err != nil {
+			return err
+		}
+
+		return setCourierStatus(q, courierID, model.CourierStatusBusy, transitionTrip, &tripID)
+	}); assignErr != nil {
 		t.log.WithFields(logrus.Fields{
 			"courier_id": courierID,
-			"trip_id":    args.TripID.UUID,
-			"error":      assignCourierErr,
+			"trip_id":    tripID,
+			"error":      assignErr,
 		}).Errorf("assign courier")
-		return assignCourierErr
+		return assignErr
 	}
 
-	courierArgs := sqlc.AssignTripToCourierParams{
-		ID: tripID,
-		CourierID: uuid.NullUUID{
-			UUID:  courierID,
-			Valid: true,
-		},
+	t.indexCourierTrip(ctx, courierID, &tripID)
+
+	return nil
+}
+
+// indexCourierTrip - Postgres already has the assignment so a failed index
+// write doesn't fail the caller. Misses are read back from couriers.trip_id,
+// a trip left behind is checked against Postgres wherever pings use it.
+func (t *TripRepository) indexCourierTrip(ctx context.Context, courierID uuid.UUID, tripID *uuid.UUID) {
+	if err := t.index.SetCourierTrip(ctx, courierID, tripID); err != nil {
+		t.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+			"trip_id":    tripID,
+		}).WithError(err).Errorf("index courier trip")
 	}
-	_, assignTripErr := t.store.AssignTripToCourier(
-		context.Background(),
-		courierArgs,
-	)
-	if assignTripErr != nil {
+}
+
+// UnassignTrip - free the courier once the trip is done with them
+func (t *TripRepository) UnassignTrip(tripID, courierID uuid.UUID) error {
+	ctx := context.Background()
+
+	if err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		return releaseCourier(q, tripID, courierID)
+	}); err != nil {
 		t.log.WithFields(logrus.Fields{
-			"courier_id": courierArgs.CourierID.UUID,
 			"trip_id":    tripID,
-			"error":      assignTripErr,
-		}).Errorf("assign trip")
-		return assignTripErr
+			"courier_id": courierID,
+			"error":      err,
+		}).Errorf("unassign trip")
+		return err
 	}
 
+	t.indexCourierTrip(ctx, courierID, nil)
+
 	return nil
 }
 
-func (t *TripRepository) UnassignTrip(courierID uuid.UUID) error {
-	if _, err := t.store.UnassignCourierTrip(
-		context.Background(),
-		courierID,
-	); err != nil {
+// ReleaseCourierTrip - take a trip back from the courier it's assigned to so
+// it can be matched again
+func (t *TripRepository) ReleaseCourierTrip(tripID, courierID uuid.UUID) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.ClearTripCourier(ctx, sqlc.ClearTripCourierParams{
+			ID:        tripID,
+			CourierID: uuid.NullUUID{UUID: courierID, Valid: true},
+		}); err == sql.ErrNoRows {
+			return ErrCourierTripNotFound
+		} else if err != nil {
+			return err
+		}
+
+		return releaseCourier(q, tripID, courierID)
+	})
+	if err != nil {
 		t.log.WithFields(logrus.Fields{
+			"trip_id":    tripID,
 			"courier_id": courierID,
-			"error":      err,
-		}).Errorf("unassign trip")
+		}).WithError(err).Errorf("release courier trip")
 		return err
 	}
 
+	t.indexCourierTrip(ctx, courierID, nil)
+
 	return nil
 }
 
 func (t *TripRepository) CreateTrip(args sqlc.CreateTripParams) (*model.Trip, error) {
+	if args.CollectAmount < 0 {
+		return nil, ErrInvalidCollectAmount
+	}
+
 	createTrip, err := t.store.CreateTrip(context.Background(), args)
 	if err != nil {
 		uziErr := fmt.Errorf("%s:%v", "create trip", err)
@@ -171,15 +249,19 @@ func (t *TripRepository) GetTripProduct(productID uuid.UUID) (*model.Product, er
 	}, nil
 }
 
-func (t *TripRepository) CreateTripCost(tripID uuid.UUID, cost int) error {
+func (t *TripRepository) CreateTripCost(tripID uuid.UUID, cost int, route model.TripRoute) error {
 	args := sqlc.CreateTripCostParams{
-		ID:   tripID,
-		Cost: int32(cost),
+		ID:       tripID,
+		Cost:     int32(cost),
+		Distance: int32(route.Distance),
+		Duration: int32(route.Duration),
 	}
 	if _, err := t.store.CreateTripCost(context.Background(), args); err != nil {
 		t.log.WithFields(logrus.Fields{
-			"trip_id": tripID,
-			"cost":    cost,
+			"trip_id":  tripID,
+			"cost":     cost,
+			"distance": route.Distance,
+			"duration": route.Duration,
 		}).WithError(err).Errorf("trip repository: create trip cost")
 		return err
 	}
@@ -209,33 +291,69 @@ func (t *TripRepository) SetTripStatus(tripID uuid.UUID, status model.TripStatus
 	return nil
 }
 
+// CompleteCourierTrip - move an en route trip to complete for its courier
+// along with any cash they collected. Only one caller wins so the trip
+// settles once.
+func (t *TripRepository) CompleteCourierTrip(tripID, courierID uuid.UUID, collected *int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ctx := context.Background()
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		trip, err := q.CompleteCourierTrip(ctx, sqlc.CompleteCourierTripParams{
+			ID:        tripID,
+			CourierID: uuid.NullUUID{UUID: courierID, Valid: true},
+		})
+		if err == sql.ErrNoRows {
+			return ErrTripCompleteNotAllowed
+		} else if err != nil {
+			return err
+		}
+
+		return collectTripCash(q, trip, collected)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrTripCompleteNotAllowed) &&
+			!errors.Is(err, ErrCollectedAmountRequired) &&
+			!errors.Is(err, ErrCollectedAmountMismatch) {
+			t.log.WithFields(logrus.Fields{
+				"trip_id":    tripID,
+				"courier_id": courierID,
+			}).WithError(err).Errorf("complete courier trip")
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (t *TripRepository) GetCourierNearPickupPoint(pickup model.GpsInput) ([]*model.Courier, error) {
-	var couriers []*model.Courier
+	couriers := make([]*model.Courier, 0)
 
-	args := sqlc.GetCourierNearPickupPointParams{
-		Point: fmt.Sprintf(
-			"SRID=4326;POINT(%.8f %.8f)",
-			pickup.Lng,
-			pickup.Lat,
-		),
-		Radius: 2000,
+	nearby, err := t.index.NearbyCouriers(context.Background(), pickup, pickupRadius)
+	if err != nil || len(nearby) == 0 {
+		return couriers, err
 	}
-	foundCouriers, err := t.store.GetCourierNearPickupPoint(
-		context.Background(),
-		args,
-	)
-	if err == sql.ErrNoRows {
-		return make([]*model.Courier, 0), nil
-	} else if err != nil {
+
+	ids := make([]uuid.UUID, len(nearby))
+	locations := make(map[uuid.UUID]model.Gps, len(nearby))
+	for i, item := range nearby {
+		ids[i] = item.ID
+		locations[item.ID] = item.Location
+	}
+
+	foundCouriers, err := t.store.GetCourierNearPickupPoint(context.Background(), ids)
+	if err != nil {
 		t.log.WithError(err).Errorf("courier new pickup point")
 		return nil, err
 	}
 
 	for _, item := range foundCouriers {
+		location := locations[item.ID]
 		courier := &model.Courier{
 			ID:        item.ID,
 			ProductID: item.ProductID.UUID,
-			Location:  model.ParsePostgisLocation(item.Location),
+			Location:  &location,
 		}
 
 		couriers = append(couriers, courier)
@@ -353,19 +471,98 @@ func (t *TripRepository) GetTrip(tripID uuid.UUID) (*model.Trip, error) {
 		return nil, err
 	}
 
-	return &model.Trip{
+	foundTrip := &model.Trip{
 		ID:              trip.ID,
 		Status:          model.TripStatus(trip.Status),
 		CourierID:       &trip.CourierID.UUID,
+		UserID:          trip.UserID,
 		ProductID:       trip.ProductID,
 		Cost:            int(trip.Cost),
+		Distance:        int(trip.Distance),
+		Duration:        int(trip.Duration),
+		Discount:        int(trip.Discount),
+		CollectAmount:   int(trip.CollectAmount),
+		CreatedAt:       &trip.CreatedAt,
 		StartLocation:   model.ParsePostgisLocation(trip.StartLocation),
 		EndLocation:     model.ParsePostgisLocation(trip.EndLocation),
 		ConfirmedPickup: model.ParsePostgisLocation(trip.ConfirmedPickup),
+	}
+	if trip.PromotionID.Valid {
+		foundTrip.PromotionID = &trip.PromotionID.UUID
+	}
+	if trip.DiscountFundedBy.Valid {
+		fundedBy := model.DiscountFunder(trip.DiscountFundedBy.String)
+		foundTrip.DiscountFundedBy = &fundedBy
+	}
+	if trip.CollectedAmount.Valid {
+		collected := int(trip.CollectedAmount.Int32)
+		foundTrip.CollectedAmount = &collected
+	}
+	if trip.ActualDistance.Valid {
+		actual := int(trip.ActualDistance.Int32)
+		foundTrip.ActualDistance = &actual
+	}
+
+	return foundTrip, nil
+}
+
+// GetTripPath - path the courier drove on the trip. Points reach the history
+// on each location flush.
+func (t *TripRepository) GetTripPath(userID, tripID uuid.UUID, admin bool) (*model.TripPath, error) {
+	ctx := context.Background()
+
+	trip, err := t.store.GetTrip(ctx, tripID)
+	if err == sql.ErrNoRows {
+		return nil, ErrTripPathNotAllowed
+	} else if err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get trip path trip")
+		return nil, err
+	}
+
+	if !admin {
+		party, err := isTripParty(t.store, userID, trip.UserID, trip.CourierID)
+		if err != nil {
+			t.log.WithFields(logrus.Fields{
+				"trip_id": tripID,
+			}).WithError(err).Errorf("get trip path courier")
+			return nil, err
+		}
+		if !party {
+			return nil, ErrTripPathNotAllowed
+		}
+	}
+
+	path, err := t.store.GetTripPath(ctx, sqlc.GetTripPathParams{
+		TripID: uuid.NullUUID{UUID: tripID, Valid: true},
+		Since:  trip.CreatedAt,
+	})
+	if err != nil {
+		t.log.WithFields(logrus.Fields{
+			"trip_id": tripID,
+		}).WithError(err).Errorf("get trip path")
+		return nil, err
+	}
+
+	return &model.TripPath{
+		TripID:   tripID,
+		Points:   int(path.Points),
+		Distance: int(path.Distance),
+		Polyline: path.Polyline,
+		Geojson:  path.Geojson,
 	}, nil
 }
 
 func (t *TripRepository) GetCourierLocation(courierID uuid.UUID) (*model.Gps, error) {
+	tracked, err := t.index.GetCourier(context.Background(), courierID)
+	if err != nil {
+		return nil, err
+	}
+	if tracked != nil {
+		return &tracked.Location, nil
+	}
+
 	courierGps, err := t.store.GetCourierLocation(context.Background(), courierID)
 	if err != nil {
 		t.log.WithFields(logrus.Fields{
@@ -425,7 +622,11 @@ func (t *TripRepository) getNearbyAvailableCourierProducts(params sqlc.GetNearby
 	return nearbyProducts, nil
 }
 
-func (t *TripRepository) GetNearbyAvailableProducts(params sqlc.GetNearbyAvailableCourierProductsParams, tripDistance int) ([]*model.Product, error) {
+func (t *TripRepository) GetNearbyAvailableProducts(
+	params sqlc.GetNearbyAvailableCourierProductsParams,
+	pickup model.Gps,
+	tripRoute model.TripRoute,
+) ([]*model.Product, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -434,13 +635,57 @@ func (t *TripRepository) GetNearbyAvailableProducts(params sqlc.GetNearbyAvailab
 		return nil, nearbyErr
 	}
 
+	productIDs := make([]uuid.UUID, 0, len(nearbys))
+	for _, item := range nearbys {
+		productIDs = append(productIDs, item.ID)
+	}
+	multipliers, err := t.pricer.GetPricingMultipliers(productIDs, pickup, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	for _, item := range nearbys {
-		item.Price = t.p.CalculateTripCost(
-			int(item.WeightClass),
-			tripDistance,
-			item.Name != "UziX",
+		multiplier := multipliers[item.ID]
+		item.Price = t.p.ApplyMultiplier(
+			t.p.CalculateTripCost(
+				int(item.WeightClass),
+				tripRoute.Distance,
+				tripRoute.Duration,
+				tripRoute.StaticDuration,
+				item.Name != "UziX",
+			),
+			multiplier,
 		)
 	}
 
 	return nearbys, nil
 }
+
+func (t *TripRepository) GetPricingMultiplier(
+	productID uuid.UUID,
+	pickup model.Gps,
+	at time.Time,
+) (float64, error) {
+	return t.pricer.GetPricingMultiplier(productID, pickup, at)
+}
+
+// isTripParty - the user booked the trip or is the courier on it
+func isTripParty(
+	q *sqlc.Queries,
+	userID, customerID uuid.UUID,
+	courierID uuid.NullUUID,
+) (bool, error) {
+	if userID == customerID {
+		return true, nil
+	}
+	if !courierID.Valid {
+		return false, nil
+	}
+
+	courier, err := q.GetCourierByID(context.Background(), courierID.UUID)
+	if err != nil {
+		return false, err
+	}
+
+	return courier.UserID.Valid && courier.UserID.UUID == userID, nil
+}
@@ -3,22 +3,37 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/edwinlomolo/uzi-api/config"
 	"github.com/edwinlomolo/uzi-api/gql/model"
 	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
 	"github.com/edwinlomolo/uzi-api/store/sqlc"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrUploadNotFound          = errors.New("upload repository: courier document not found")
+	ErrRejectionReasonRequired = errors.New("upload repository: rejected documents need a reason")
+	ErrInvalidExpiry           = errors.New("upload repository: document expiry must be in the future")
+)
+
+const documentExpiredReason = "Document expired, upload a renewed copy"
+
 type UploadRepository struct {
-	store *sqlc.Queries
-	log   *logrus.Logger
+	store  *sqlc.Queries
+	config config.Document
+	log    *logrus.Logger
 }
 
 func (u *UploadRepository) Init(q *sqlc.Queries) {
 	u.store = q
+	u.config = config.Config.Document
 	u.log = internal.GetLogger()
 }
 
@@ -69,7 +84,7 @@ func (u *UploadRepository) createCourierUpload(reason, uri string, id uuid.UUID)
 		return getErr
 	}
 
-	return u.updateUploadUri(courierUpload.Uri, courierUpload.ID)
+	return u.updateUploadUri(uri, courierUpload.ID)
 }
 
 func (u *UploadRepository) updateUploadUri(uri string, ID uuid.UUID) error {
@@ -97,26 +112,151 @@ func (u *UploadRepository) updateUploadUri(uri string, ID uuid.UUID) error {
 	return nil
 }
 
-func (u *UploadRepository) updateUploadVerificationStatus(
-	id uuid.UUID,
+// ReviewCourierUpload - approve or reject a courier document and move the
+// courier onboarding along. Approved documents that expire carry their expiry.
+func (u *UploadRepository) ReviewCourierUpload(
+	reviewerID, uploadID uuid.UUID,
 	status model.UploadVerificationStatus,
-) error {
-	args := sqlc.UpdateUploadParams{
-		ID: id,
-		Verification: sql.NullString{
-			String: status.String(),
-			Valid:  true,
-		},
+	reason string,
+	expiresAt *time.Time,
+) (*model.Uploads, error) {
+	ctx := context.Background()
+
+	if status == model.UploadVerificationStatusRejected && strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectionReasonRequired
 	}
-	if _, updateErr := u.store.UpdateUpload(context.Background(), args); updateErr != nil {
-		u.log.WithFields(logrus.Fields{
-			"upload_id": id,
-			"status":    status.String(),
-		}).WithError(updateErr).Errorf("update upload verification status")
-		return updateErr
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
 	}
 
-	return nil
+	var reviewed sqlc.Upload
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		upload, err := q.GetUploadForUpdate(ctx, uploadID)
+		if err == sql.ErrNoRows || (err == nil && !upload.CourierID.Valid) {
+			return ErrUploadNotFound
+		} else if err != nil {
+			return err
+		}
+
+		args := sqlc.ReviewUploadParams{
+			ID:           upload.ID,
+			Verification: status.String(),
+			ReviewedBy:   uuid.NullUUID{UUID: reviewerID, Valid: true},
+		}
+		if status == model.UploadVerificationStatusRejected {
+			args.RejectionReason = sql.NullString{String: strings.TrimSpace(reason), Valid: true}
+		} else if expiresAt != nil {
+			args.ExpiresAt = sql.NullTime{Time: expiresAt.UTC(), Valid: true}
+		}
+		reviewed, err = q.ReviewUpload(ctx, args)
+		if err != nil {
+			return err
+		}
+
+		return syncCourierVerification(q, upload.CourierID.UUID)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrUploadNotFound) {
+			u.log.WithFields(logrus.Fields{
+				"upload_id": uploadID,
+				"status":    status,
+			}).WithError(err).Errorf("review courier upload")
+		}
+		return nil, err
+	}
+
+	return parseUpload(reviewed), nil
+}
+
+func (u *UploadRepository) GetPendingCourierUploads(limit, offset int) ([]*model.Uploads, error) {
+	uploads := make([]*model.Uploads, 0)
+
+	pending, err := u.store.GetPendingCourierUploads(context.Background(), sqlc.GetPendingCourierUploadsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		u.log.WithError(err).Errorf("get pending courier uploads")
+		return nil, err
+	}
+
+	for _, item := range pending {
+		uploads = append(uploads, parseUpload(item))
+	}
+
+	return uploads, nil
+}
+
+// GetExpiringCourierUploads - verified courier documents expiring within the
+// warning window that the courier has not been reminded about yet. Marks them
+// reminded so each document is only warned about once per review.
+func (u *UploadRepository) GetExpiringCourierUploads(now time.Time) ([]*model.Uploads, error) {
+	ctx := context.Background()
+	uploads := make([]*model.Uploads, 0)
+
+	warnBefore := now.AddDate(0, 0, u.config.ExpiryWarningDays)
+	expiring, err := u.store.GetExpiringCourierUploads(ctx, sqlc.GetExpiringCourierUploadsParams{
+		Now:        sql.NullTime{Time: now.UTC(), Valid: true},
+		WarnBefore: sql.NullTime{Time: warnBefore.UTC(), Valid: true},
+	})
+	if err != nil {
+		u.log.WithError(err).Errorf("get expiring courier uploads")
+		return nil, err
+	}
+
+	for _, upload := range expiring {
+		if err := u.store.SetUploadExpiryWarned(ctx, sqlc.SetUploadExpiryWarnedParams{
+			ID:             upload.ID,
+			ExpiryWarnedAt: sql.NullTime{Time: now.UTC(), Valid: true},
+		}); err != nil {
+			u.log.WithFields(logrus.Fields{
+				"upload_id": upload.ID,
+			}).WithError(err).Errorf("set upload expiry warned")
+			continue
+		}
+		uploads = append(uploads, parseUpload(upload))
+	}
+
+	return uploads, nil
+}
+
+// ExpireCourierUploads - reject verified courier documents past their expiry
+// and send the couriers back to onboarding until they upload renewed copies
+func (u *UploadRepository) ExpireCourierUploads(now time.Time) ([]*model.Uploads, error) {
+	ctx := context.Background()
+	uploads := make([]*model.Uploads, 0)
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		expired, err := q.ExpireCourierUploads(ctx, sqlc.ExpireCourierUploadsParams{
+			Reason: sql.NullString{String: documentExpiredReason, Valid: true},
+			Now:    now.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		synced := make(map[uuid.UUID]bool)
+		for _, upload := range expired {
+			uploads = append(uploads, parseUpload(upload))
+
+			courierID := upload.CourierID.UUID
+			if synced[courierID] {
+				continue
+			}
+			if err := syncCourierVerification(q, courierID); err != nil {
+				return err
+			}
+			synced[courierID] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		u.log.WithError(err).Errorf("expire courier uploads")
+		return nil, err
+	}
+
+	return uploads, nil
 }
 
 func (u *UploadRepository) createUserUpload(reason, uri string, ID uuid.UUID) error {
@@ -155,7 +295,7 @@ func (u *UploadRepository) createUserUpload(reason, uri string, ID uuid.UUID) er
 		return uziErr
 	}
 
-	return u.updateUploadUri(foundUpload.Uri, foundUpload.ID)
+	return u.updateUploadUri(uri, foundUpload.ID)
 }
 
 func (u *UploadRepository) GetCourierUploads(
@@ -173,15 +313,33 @@ func (u *UploadRepository) GetCourierUploads(
 	}
 
 	for _, i := range uplds {
-		upload := &model.Uploads{
-			ID:           i.ID,
-			URI:          i.Uri,
-			Type:         i.Type,
-			Verification: model.UploadVerificationStatus(i.Verification),
-		}
-
-		uploads = append(uploads, upload)
+		uploads = append(uploads, parseUpload(i))
 	}
 
 	return uploads, nil
 }
+
+func parseUpload(u sqlc.Upload) *model.Uploads {
+	upload := &model.Uploads{
+		ID:           u.ID,
+		URI:          u.Uri,
+		Type:         u.Type,
+		Verification: model.UploadVerificationStatus(u.Verification),
+		CreatedAt:    &u.CreatedAt,
+		UpdatedAt:    &u.UpdatedAt,
+	}
+	if u.RejectionReason.Valid {
+		upload.RejectionReason = &u.RejectionReason.String
+	}
+	if u.ExpiresAt.Valid {
+		upload.ExpiresAt = &u.ExpiresAt.Time
+	}
+	if u.CourierID.Valid {
+		upload.CourierID = &u.CourierID.UUID
+	}
+	if u.UserID.Valid {
+		upload.UserID = &u.UserID.UUID
+	}
+
+	return upload
+}
@@ -19,19 +19,25 @@ var (
 )
 
 type SigninInput struct {
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Phone     string `json:"phone"`
-	Courier   bool   `json:"courier"`
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	Phone        string `json:"phone"`
+	Courier      bool   `json:"courier"`
+	ReferralCode string `json:"referralCode"`
+	DeviceID     string `json:"deviceId"`
 }
 type UserRepository struct {
-	jwt   internal.JwtService
-	store *sqlc.Queries
-	log   *logrus.Logger
+	jwt      internal.JwtService
+	referral *ReferralRepository
+	store    *sqlc.Queries
+	log      *logrus.Logger
 }
 
 func (u *UserRepository) Init(q *sqlc.Queries) {
+	rr := &ReferralRepository{}
+	rr.Init(q)
 	u.jwt = internal.NewJwtClient()
+	u.referral = rr
 	u.store = q
 	u.log = internal.GetLogger()
 }
@@ -103,6 +109,14 @@ func (u *UserRepository) createUser(user SigninInput) (*model.User, error) {
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		Phone:     user.Phone,
+		ReferralCode: sql.NullString{
+			String: newReferralCode(),
+			Valid:  true,
+		},
+		DeviceID: sql.NullString{
+			String: user.DeviceID,
+			Valid:  len(user.DeviceID) > 0,
+		},
 	}
 	newUser, newUserErr := u.store.CreateUser(context.Background(), createArgs)
 	if newUserErr != nil {
@@ -118,11 +132,16 @@ func (u *UserRepository) createUser(user SigninInput) (*model.User, error) {
 		}
 	}
 
+	if referralErr := u.referral.AttributeReferral(newUser.ID, user); referralErr != nil {
+		return nil, referralErr
+	}
+
 	return &model.User{
-		ID:        newUser.ID,
-		FirstName: newUser.FirstName,
-		LastName:  newUser.LastName,
-		Phone:     newUser.Phone,
+		ID:           newUser.ID,
+		FirstName:    newUser.FirstName,
+		LastName:     newUser.LastName,
+		Phone:        newUser.Phone,
+		ReferralCode: &newUser.ReferralCode.String,
 	}, nil
 }
 
@@ -137,12 +156,18 @@ func (u *UserRepository) getUserByPhone(phone string) (*model.User, error) {
 		return nil, getErr
 	}
 
-	return &model.User{
-		ID:        foundUser.ID,
-		FirstName: foundUser.FirstName,
-		LastName:  foundUser.LastName,
-		Phone:     foundUser.Phone,
-	}, nil
+	found := &model.User{
+		ID:           foundUser.ID,
+		FirstName:    foundUser.FirstName,
+		LastName:     foundUser.LastName,
+		Phone:        foundUser.Phone,
+		ReferralCode: &foundUser.ReferralCode.String,
+	}
+	if foundUser.Rating.Valid {
+		found.Rating = &foundUser.Rating.Float64
+	}
+
+	return found, nil
 }
 
 func (u *UserRepository) GetUserByPhone(phone string) (*model.User, error) {
@@ -163,12 +188,18 @@ func (u *UserRepository) findUserByID(id uuid.UUID) (*model.User, error) {
 		return nil, getErr
 	}
 
-	return &model.User{
-		ID:        foundUser.ID,
-		FirstName: foundUser.FirstName,
-		LastName:  foundUser.LastName,
-		Phone:     foundUser.Phone,
-	}, nil
+	found := &model.User{
+		ID:           foundUser.ID,
+		FirstName:    foundUser.FirstName,
+		LastName:     foundUser.LastName,
+		Phone:        foundUser.Phone,
+		ReferralCode: &foundUser.ReferralCode.String,
+	}
+	if foundUser.Rating.Valid {
+		found.Rating = &foundUser.Rating.Float64
+	}
+
+	return found, nil
 }
 
 func (u *UserRepository) FindUserByID(id uuid.UUID) (*model.User, error) {
@@ -194,6 +225,11 @@ func (u *UserRepository) OnboardUser(user SigninInput) (*model.User, error) {
 		return nil, onboardErr
 	}
 
+	// Referral code can be entered while onboarding too
+	if referralErr := u.referral.AttributeReferral(newUser.ID, user); referralErr != nil {
+		return nil, referralErr
+	}
+
 	statusArgs := sqlc.SetOnboardingStatusParams{
 		Phone:      user.Phone,
 		Onboarding: false,
@@ -206,10 +242,11 @@ func (u *UserRepository) OnboardUser(user SigninInput) (*model.User, error) {
 	}
 
 	return &model.User{
-		ID:        newUser.ID,
-		FirstName: newUser.FirstName,
-		LastName:  newUser.LastName,
-		Phone:     newUser.Phone,
+		ID:           newUser.ID,
+		FirstName:    newUser.FirstName,
+		LastName:     newUser.LastName,
+		Phone:        newUser.Phone,
+		ReferralCode: &newUser.ReferralCode.String,
 	}, nil
 }
 
@@ -292,6 +329,7 @@ func (u *UserRepository) getSession(userID uuid.UUID) (*model.Session, error) {
 		FirstName:     &user.FirstName,
 		LastName:      &user.LastName,
 		Phone:         foundSess.Phone,
+		ReferralCode:  user.ReferralCode,
 		UserAgent:     foundSess.UserAgent,
 		Token:         sessionJwt,
 		CourierStatus: &courierStatus,
@@ -350,6 +388,7 @@ func (u *UserRepository) createNewSession(
 		FirstName:     &user.FirstName,
 		LastName:      &user.LastName,
 		Phone:         newSession.Phone,
+		ReferralCode:  user.ReferralCode,
 		UserAgent:     newSession.UserAgent,
 		Token:         sessionJwt,
 		CourierStatus: &courierStatus,
@@ -398,6 +437,21 @@ func (u *UserRepository) isCourier(userID uuid.UUID) (bool, error) {
 	return isCourier.Bool, nil
 }
 
+// IsAdmin - ops/finance staff flagged directly in the database
+func (u *UserRepository) IsAdmin(userID uuid.UUID) (bool, error) {
+	isAdmin, err := u.store.IsUserAdmin(context.Background(), userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		u.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("is admin check")
+		return false, err
+	}
+
+	return isAdmin, nil
+}
+
 func (u *UserRepository) getRelevantCourierData(userID uuid.UUID) (bool, model.CourierStatus, error) {
 
 	courierStatus, courierStatusErr := u.getCourierStatus(userID)
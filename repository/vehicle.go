@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrVehicleNotFound     = errors.New("vehicle repository: vehicle not found")
+	ErrInvalidVehicle      = errors.New("vehicle repository: vehicle needs a plate, make, colour and capacity")
+	ErrPlateRegistered     = errors.New("vehicle repository: plate already registered")
+	ErrVehicleNotApproved  = errors.New("vehicle repository: only approved vehicles can be used")
+	ErrVehicleSwitchOnTrip = errors.New("vehicle repository: finish the current trip before switching vehicles")
+)
+
+var plateSeparators = regexp.MustCompile(`[\s-]+`)
+
+type VehicleRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (v *VehicleRepository) Init(q *sqlc.Queries) {
+	v.store = q
+	v.log = internal.GetLogger()
+}
+
+// RegisterVehicle - add a vehicle for admins, and the fleet if the courier
+// rides for one, to approve. It becomes the active vehicle unless the courier
+// already rides an approved one.
+func (v *VehicleRepository) RegisterVehicle(courierID uuid.UUID, input model.VehicleInput) (*model.Vehicle, error) {
+	ctx := context.Background()
+
+	plate := strings.ToUpper(plateSeparators.ReplaceAllString(strings.TrimSpace(input.Plate), ""))
+	vehicleMake := strings.TrimSpace(input.Make)
+	colour := strings.TrimSpace(input.Colour)
+	if !input.Type.IsValid() || plate == "" || vehicleMake == "" || colour == "" || input.Capacity <= 0 {
+		return nil, ErrInvalidVehicle
+	}
+
+	var (
+		vehicle sqlc.Vehicle
+		active  uuid.NullUUID
+	)
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.GetProductByID(ctx, input.ProductID); err == sql.ErrNoRows {
+			return ErrProductNotFound
+		} else if err != nil {
+			return err
+		}
+
+		registered, err := q.IsPlateRegistered(ctx, plate)
+		if err != nil {
+			return err
+		}
+		if registered {
+			return ErrPlateRegistered
+		}
+
+		courier, err := q.GetCourierOnboarding(ctx, courierID)
+		if err != nil {
+			return err
+		}
+		active = courier.VehicleID
+
+		vehicle, err = q.CreateVehicle(ctx, sqlc.CreateVehicleParams{
+			CourierID: courierID,
+			ProductID: input.ProductID,
+			Type:      input.Type.String(),
+			Plate:     plate,
+			Make:      vehicleMake,
+			Colour:    colour,
+			Capacity:  int32(input.Capacity),
+			FleetID:   courier.FleetID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if courier.VehicleID.Valid &&
+			courier.VehicleStatus.String == model.VehicleStatusApproved.String() &&
+			courier.FleetApproved {
+			return nil
+		}
+
+		active = uuid.NullUUID{UUID: vehicle.ID, Valid: true}
+		return setCourierVehicle(q, courierID, vehicle)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrProductNotFound) && !errors.Is(err, ErrPlateRegistered) {
+			v.log.WithFields(logrus.Fields{
+				"courier_id": courierID,
+				"plate":      plate,
+			}).WithError(err).Errorf("register vehicle")
+		}
+		return nil, err
+	}
+
+	return parseVehicle(vehicle, active), nil
+}
+
+// SwitchVehicle - ride another approved vehicle, and serve its product
+func (v *VehicleRepository) SwitchVehicle(courierID, vehicleID uuid.UUID) (*model.Vehicle, error) {
+	ctx := context.Background()
+
+	var vehicle sqlc.Vehicle
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		vehicle, err = q.GetVehicle(ctx, vehicleID)
+		if err == sql.ErrNoRows || (err == nil && vehicle.CourierID != courierID) {
+			return ErrVehicleNotFound
+		} else if err != nil {
+			return err
+		}
+		if vehicle.Status != model.VehicleStatusApproved.String() ||
+			(vehicle.FleetID.Valid && !vehicle.FleetApprovedAt.Valid) {
+			return ErrVehicleNotApproved
+		}
+
+		courier, err := q.GetCourierOnboarding(ctx, courierID)
+		if err != nil {
+			return err
+		}
+		if courier.TripID.Valid {
+			return ErrVehicleSwitchOnTrip
+		}
+
+		return setCourierVehicle(q, courierID, vehicle)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrVehicleNotFound) &&
+			!errors.Is(err, ErrVehicleNotApproved) &&
+			!errors.Is(err, ErrVehicleSwitchOnTrip) {
+			v.log.WithFields(logrus.Fields{
+				"courier_id": courierID,
+				"vehicle_id": vehicleID,
+			}).WithError(err).Errorf("switch vehicle")
+		}
+		return nil, err
+	}
+
+	return parseVehicle(vehicle, uuid.NullUUID{UUID: vehicle.ID, Valid: true}), nil
+}
+
+// ReviewVehicle - approve or reject a courier vehicle. Reviewing the active
+// vehicle moves the courier verification along.
+func (v *VehicleRepository) ReviewVehicle(
+	reviewerID, vehicleID uuid.UUID,
+	status model.VehicleStatus,
+	reason string,
+) (*model.Vehicle, error) {
+	ctx := context.Background()
+
+	if status == model.VehicleStatusRejected && strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	var (
+		reviewed sqlc.Vehicle
+		active   uuid.NullUUID
+	)
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		vehicle, err := q.GetVehicleForUpdate(ctx, vehicleID)
+		if err == sql.ErrNoRows {
+			return ErrVehicleNotFound
+		} else if err != nil {
+			return err
+		}
+
+		args := sqlc.ReviewVehicleParams{
+			ID:         vehicle.ID,
+			Status:     status.String(),
+			ReviewedBy: uuid.NullUUID{UUID: reviewerID, Valid: true},
+		}
+		if status == model.VehicleStatusRejected {
+			args.RejectionReason = sql.NullString{String: strings.TrimSpace(reason), Valid: true}
+		}
+		reviewed, err = q.ReviewVehicle(ctx, args)
+		if err != nil {
+			return err
+		}
+
+		courier, err := q.GetCourierOnboarding(ctx, vehicle.CourierID)
+		if err != nil {
+			return err
+		}
+		active = courier.VehicleID
+		if active.UUID != vehicle.ID {
+			return nil
+		}
+
+		return syncCourierVerification(q, vehicle.CourierID)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrVehicleNotFound) {
+			v.log.WithFields(logrus.Fields{
+				"vehicle_id": vehicleID,
+				"status":     status,
+			}).WithError(err).Errorf("review vehicle")
+		}
+		return nil, err
+	}
+
+	return parseVehicle(reviewed, active), nil
+}
+
+func (v *VehicleRepository) GetVehicle(vehicleID uuid.UUID) (*model.Vehicle, error) {
+	ctx := context.Background()
+
+	vehicle, err := v.store.GetVehicle(ctx, vehicleID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		v.log.WithFields(logrus.Fields{
+			"vehicle_id": vehicleID,
+		}).WithError(err).Errorf("get vehicle")
+		return nil, err
+	}
+
+	courier, err := v.store.GetCourierOnboarding(ctx, vehicle.CourierID)
+	if err != nil {
+		v.log.WithFields(logrus.Fields{
+			"courier_id": vehicle.CourierID,
+		}).WithError(err).Errorf("get vehicle courier")
+		return nil, err
+	}
+
+	return parseVehicle(vehicle, courier.VehicleID), nil
+}
+
+func (v *VehicleRepository) GetCourierVehicles(courierID uuid.UUID) ([]*model.Vehicle, error) {
+	ctx := context.Background()
+	vehicles := make([]*model.Vehicle, 0)
+
+	courier, err := v.store.GetCourierOnboarding(ctx, courierID)
+	if err != nil {
+		v.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get vehicles courier")
+		return nil, err
+	}
+
+	items, err := v.store.GetCourierVehicles(ctx, courierID)
+	if err != nil {
+		v.log.WithFields(logrus.Fields{
+			"courier_id": courierID,
+		}).WithError(err).Errorf("get courier vehicles")
+		return nil, err
+	}
+
+	for _, item := range items {
+		vehicles = append(vehicles, parseVehicle(item, courier.VehicleID))
+	}
+
+	return vehicles, nil
+}
+
+func (v *VehicleRepository) GetPendingVehicles(limit, offset int) ([]*model.Vehicle, error) {
+	vehicles := make([]*model.Vehicle, 0)
+
+	pending, err := v.store.GetPendingVehicles(context.Background(), sqlc.GetPendingVehiclesParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		v.log.WithError(err).Errorf("get pending vehicles")
+		return nil, err
+	}
+
+	for _, item := range pending {
+		vehicles = append(vehicles, parseVehicle(item.Vehicle, item.ActiveVehicleID))
+	}
+
+	return vehicles, nil
+}
+
+// setCourierVehicle - make the vehicle the courier's active one, switching
+// the product they serve and the documents it needs.
+// Must run inside store.WithTx.
+func setCourierVehicle(q *sqlc.Queries, courierID uuid.UUID, vehicle sqlc.Vehicle) error {
+	if _, err := q.SetCourierVehicle(context.Background(), sqlc.SetCourierVehicleParams{
+		ID:        courierID,
+		VehicleID: uuid.NullUUID{UUID: vehicle.ID, Valid: true},
+		ProductID: uuid.NullUUID{UUID: vehicle.ProductID, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	return syncCourierVerification(q, courierID)
+}
+
+func parseVehicle(v sqlc.Vehicle, active uuid.NullUUID) *model.Vehicle {
+	vehicle := &model.Vehicle{
+		ID:        v.ID,
+		CourierID: v.CourierID,
+		ProductID: v.ProductID,
+		Type:      model.VehicleType(v.Type),
+		Plate:     v.Plate,
+		Make:      v.Make,
+		Colour:    v.Colour,
+		Capacity:  int(v.Capacity),
+		Status:    model.VehicleStatus(v.Status),
+		Active:    active.Valid && active.UUID == v.ID,
+		CreatedAt: &v.CreatedAt,
+		UpdatedAt: &v.UpdatedAt,
+	}
+	if v.RejectionReason.Valid {
+		vehicle.RejectionReason = &v.RejectionReason.String
+	}
+	if v.FleetID.Valid {
+		vehicle.FleetID = &v.FleetID.UUID
+	}
+	if v.FleetApprovedAt.Valid {
+		vehicle.FleetApprovedAt = &v.FleetApprovedAt.Time
+	}
+
+	return vehicle
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/edwinlomolo/uzi-api/gql/model"
+	"github.com/edwinlomolo/uzi-api/internal"
+	"github.com/edwinlomolo/uzi-api/store"
+	"github.com/edwinlomolo/uzi-api/store/sqlc"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type WalletRepository struct {
+	store *sqlc.Queries
+	log   *logrus.Logger
+}
+
+func (w *WalletRepository) Init(q *sqlc.Queries) {
+	w.store = q
+	w.log = internal.GetLogger()
+}
+
+func (w *WalletRepository) GetWallet(userID uuid.UUID) (*model.Wallet, error) {
+	wallet := &model.Wallet{}
+
+	accounts, err := w.store.GetUserLedgerAccounts(
+		context.Background(),
+		uuid.NullUUID{UUID: userID, Valid: true},
+	)
+	if err != nil {
+		w.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get user ledger accounts")
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		switch model.WalletAccount(account.Kind) {
+		case model.WalletAccountCash:
+			wallet.Balance = int(account.Balance)
+		case model.WalletAccountHold:
+			wallet.Held = int(account.Balance)
+		case model.WalletAccountPromo:
+			wallet.PromoBalance = int(account.Balance)
+		case model.WalletAccountEarnings:
+			wallet.Earnings = int(account.Balance)
+		case model.WalletAccountCashHeld:
+			wallet.CashHeld = -int(account.Balance)
+		}
+	}
+
+	return wallet, nil
+}
+
+func (w *WalletRepository) GetWalletStatement(
+	userID uuid.UUID,
+	limit, offset int,
+) ([]*model.WalletEntry, error) {
+	var statement []*model.WalletEntry
+
+	entries, err := w.store.GetUserLedgerStatement(context.Background(), sqlc.GetUserLedgerStatementParams{
+		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		w.log.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).WithError(err).Errorf("get user ledger statement")
+		return nil, err
+	}
+
+	for _, item := range entries {
+		entry := &model.WalletEntry{
+			ID:          item.ID,
+			Account:     model.WalletAccount(item.Account),
+			Kind:        model.LedgerTransactionKind(item.Kind),
+			Amount:      int(item.Amount),
+			Balance:     int(item.Balance),
+			Description: item.Description,
+			CreatedAt:   &item.CreatedAt,
+		}
+		if item.TripID.Valid {
+			entry.TripID = &item.TripID.UUID
+		}
+
+		statement = append(statement, entry)
+	}
+
+	return statement, nil
+}
+
+// CaptureTripHold - charge the wallet hold for a completed trip and release
+// whatever the final cost didn't use
+func (w *WalletRepository) CaptureTripHold(trip model.Trip) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payment, err := q.GetPaidTripPaymentForUpdate(ctx, uuid.NullUUID{UUID: trip.ID, Valid: true})
+		if err == sql.ErrNoRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if payment.Provider != model.PaymentProviderWallet.String() {
+			return nil
+		}
+
+		held, err := q.GetTripHeldAmount(ctx, sqlc.GetTripHeldAmountParams{
+			TripID: uuid.NullUUID{UUID: trip.ID, Valid: true},
+			UserID: uuid.NullUUID{UUID: trip.UserID, Valid: true},
+		})
+		if err != nil || held <= 0 {
+			return err
+		}
+
+		charge := int(held)
+		if due := trip.Cost - trip.Discount; due < charge {
+			charge = due
+		}
+
+		if charge > 0 {
+			if _, err := postLedger(q, ledgerPosting{
+				kind:        model.LedgerTransactionKindTripCharge,
+				reference:   fmt.Sprintf("charge:%s", trip.ID),
+				tripID:      &trip.ID,
+				paymentID:   &payment.ID,
+				description: "Trip charge",
+				legs: []ledgerLeg{
+					userLeg(trip.UserID, model.WalletAccountHold, -charge),
+					platformLeg(platformRevenue, charge),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if remainder := int(held) - charge; remainder > 0 {
+			if _, err := postLedger(q, ledgerPosting{
+				kind:        model.LedgerTransactionKindHoldRelease,
+				reference:   fmt.Sprintf("release:%s", trip.ID),
+				tripID:      &trip.ID,
+				paymentID:   &payment.ID,
+				description: "Unused trip hold",
+				legs: []ledgerLeg{
+					userLeg(trip.UserID, model.WalletAccountHold, -remainder),
+					userLeg(trip.UserID, model.WalletAccountCash, remainder),
+				},
+			}); err != nil {
+				return err
+			}
+
+			_, err = q.RefundPayment(ctx, sqlc.RefundPaymentParams{
+				ID:             payment.ID,
+				Status:         payment.Status,
+				RefundedAmount: int32(remainder),
+			})
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		w.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("capture trip hold")
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseTripHold - give the wallet hold back for a cancelled trip
+func (w *WalletRepository) ReleaseTripHold(trip model.Trip) error {
+	ctx := context.Background()
+
+	err := store.WithTx(ctx, func(q *sqlc.Queries) error {
+		payment, err := q.GetPaidTripPaymentForUpdate(ctx, uuid.NullUUID{UUID: trip.ID, Valid: true})
+		if err == sql.ErrNoRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if payment.Provider != model.PaymentProviderWallet.String() {
+			return nil
+		}
+
+		_, err = releaseTripHold(q, trip, payment)
+		return err
+	})
+	if err != nil {
+		w.log.WithFields(logrus.Fields{
+			"trip_id": trip.ID,
+		}).WithError(err).Errorf("release trip hold")
+		return err
+	}
+
+	return nil
+}
+
+func releaseTripHold(
+	q *sqlc.Queries,
+	trip model.Trip,
+	payment sqlc.Payment,
+) (sqlc.Payment, error) {
+	ctx := context.Background()
+
+	held, err := q.GetTripHeldAmount(ctx, sqlc.GetTripHeldAmountParams{
+		TripID: uuid.NullUUID{UUID: trip.ID, Valid: true},
+		UserID: uuid.NullUUID{UUID: trip.UserID, Valid: true},
+	})
+	if err != nil {
+		return payment, err
+	}
+
+	if held > 0 {
+		if _, err := postLedger(q, ledgerPosting{
+			kind:        model.LedgerTransactionKindHoldRelease,
+			reference:   fmt.Sprintf("release:%s", trip.ID),
+			tripID:      &trip.ID,
+			paymentID:   &payment.ID,
+			description: "Cancelled trip hold",
+			legs: []ledgerLeg{
+				userLeg(trip.UserID, model.WalletAccountHold, -int(held)),
+				userLeg(trip.UserID, model.WalletAccountCash, int(held)),
+			},
+		}); err != nil {
+			return payment, err
+		}
+	}
+
+	return q.RefundPayment(ctx, sqlc.RefundPaymentParams{
+		ID:             payment.ID,
+		Status:         model.PaymentStatusRefunded.String(),
+		RefundedAmount: held,
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/edwinlomolo/uzi-api/config"
+	"github.com/edwinlomolo/uzi-api/controllers"
 	"github.com/edwinlomolo/uzi-api/gql"
 	"github.com/edwinlomolo/uzi-api/gql/resolvers"
 	"github.com/edwinlomolo/uzi-api/handler"
@@ -35,6 +36,8 @@ func main() {
 	q, _ := store.InitializeStorage()
 	// Redis cache client
 	internal.NewCache()
+	// Live courier positions
+	internal.NewCourierIndex()
 
 	isProd := func() bool {
 		return config.Config.Server.Env == "production" ||
@@ -55,8 +58,19 @@ func main() {
 	// Internal services
 	internal.NewPricer()
 	internal.NewUploader()
+	internal.NewMpesa()
+	internal.NewPaystack()
 
 	srv := gqlHandler.New(gql.NewExecutableSchema(resolvers.New(q)))
+
+	// Background jobs
+	go controllers.GetPayoutController().SchedulePayouts()
+	go controllers.GetUploadController().ScheduleExpiryChecks()
+	go controllers.GetCourierController().ScheduleLocationFlush()
+	go controllers.GetCourierController().ScheduleBreadcrumbPartitions()
+	go controllers.GetCourierController().ScheduleHeartbeatChecks()
+	go controllers.GetShiftController().ScheduleShiftSettlement()
+
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
@@ -102,6 +116,11 @@ func main() {
 		r.Post("/courier/upload/document", handler.UploadDocument())
 		r.Get("/ipinfo", handler.Ipinfo())
 		r.Post("/account/delete", handler.SoftDeleteAccount())
+		r.Post("/webhooks/mpesa", handler.MpesaCallback())
+		r.Post("/webhooks/paystack", handler.PaystackWebhook())
+		r.Post("/webhooks/mpesa/b2c/result", handler.MpesaB2CResult())
+		r.Post("/webhooks/mpesa/b2c/timeout", handler.MpesaB2CResult())
+		r.Post("/webhooks/mpesa/b2c/status", handler.MpesaB2CStatus())
 	})
 	r.Get("/", playground.Handler("GraphQL playground", "/api/graphql"))
 	r.Handle("/subscription", srv)
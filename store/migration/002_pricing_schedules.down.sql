@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pricing_schedules;
+DROP TABLE IF EXISTS public_holidays;
+DROP TABLE IF EXISTS zones CASCADE;
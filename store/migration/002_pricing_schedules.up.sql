@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS zones (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) UNIQUE NOT NULL,
+  boundary GEOGRAPHY(POLYGON, 4326) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS zones_gix ON zones USING GIST(boundary);
+
+CREATE TABLE IF NOT EXISTS pricing_schedules (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) NOT NULL,
+  kind VARCHAR(10) NOT NULL,
+  multiplier DOUBLE PRECISION NOT NULL DEFAULT 1,
+  start_time TIME,
+  end_time TIME,
+  product_id UUID REFERENCES products ON DELETE CASCADE,
+  zone_id UUID REFERENCES zones ON DELETE CASCADE,
+  valid_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  valid_until TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS public_holidays (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) NOT NULL,
+  day DATE UNIQUE NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO pricing_schedules (
+  name, kind, multiplier, start_time, end_time
+) VALUES
+  ('Night', 'NIGHT', 1.3, '22:00', '05:00'),
+  ('Weekend', 'WEEKEND', 1.1, NULL, NULL),
+  ('Public holiday', 'HOLIDAY', 1.2, NULL, NULL);
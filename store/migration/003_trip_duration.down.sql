@@ -0,0 +1,2 @@
+ALTER TABLE trips DROP COLUMN IF EXISTS duration;
+ALTER TABLE trips DROP COLUMN IF EXISTS distance;
@@ -0,0 +1,2 @@
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS distance INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS duration INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE trips DROP COLUMN IF EXISTS discount_funded_by;
+ALTER TABLE trips DROP COLUMN IF EXISTS discount;
+ALTER TABLE trips DROP COLUMN IF EXISTS promotion_id;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
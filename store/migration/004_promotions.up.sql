@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS promotions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  code VARCHAR(20) UNIQUE NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  discount_type VARCHAR(10) NOT NULL,
+  discount_value INTEGER NOT NULL,
+  max_discount INTEGER,
+  per_user_limit INTEGER NOT NULL DEFAULT 1,
+  budget INTEGER,
+  budget_used INTEGER NOT NULL DEFAULT 0,
+  first_trip_only BOOLEAN NOT NULL DEFAULT false,
+  funded_by VARCHAR(20) NOT NULL DEFAULT 'PLATFORM',
+  product_id UUID REFERENCES products ON DELETE CASCADE,
+  zone_id UUID REFERENCES zones ON DELETE CASCADE,
+  starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ends_at TIMESTAMP,
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (budget IS NULL OR budget_used <= budget)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  promotion_id UUID NOT NULL REFERENCES promotions ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  trip_id UUID UNIQUE NOT NULL REFERENCES trips ON DELETE CASCADE,
+  amount INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS promotion_redemptions_user_idx ON promotion_redemptions(promotion_id, user_id);
+
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS promotion_id UUID REFERENCES promotions ON DELETE SET NULL;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS discount_funded_by VARCHAR(20);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS referrals;
+ALTER TABLE users DROP COLUMN IF EXISTS promo_balance;
+ALTER TABLE users DROP COLUMN IF EXISTS device_id;
+ALTER TABLE users DROP COLUMN IF EXISTS referral_code;
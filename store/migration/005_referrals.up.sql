@@ -0,0 +1,21 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(10) UNIQUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS device_id VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS promo_balance INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users
+SET referral_code = UPPER(SUBSTRING(REPLACE(id::text, '-', ''), 1, 8))
+WHERE referral_code IS NULL;
+
+CREATE TABLE IF NOT EXISTS referrals (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  referrer_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  referee_id UUID UNIQUE NOT NULL REFERENCES users ON DELETE CASCADE,
+  kind VARCHAR(10) NOT NULL DEFAULT 'USER',
+  status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+  device_id VARCHAR(100),
+  rewarded_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS referrals_device_idx ON referrals(device_id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS payments (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  trip_id UUID NOT NULL REFERENCES trips ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  provider VARCHAR(20) NOT NULL DEFAULT 'MPESA',
+  amount INTEGER NOT NULL,
+  phone VARCHAR(20),
+  status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+  merchant_request_id VARCHAR(100),
+  checkout_request_id VARCHAR(100) UNIQUE,
+  receipt VARCHAR(50),
+  result_code INTEGER,
+  result_desc TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS payments_trip_idx ON payments(trip_id);
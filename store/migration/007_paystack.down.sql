@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS payment_cards;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
+ALTER TABLE payments DROP COLUMN IF EXISTS authorization_url;
+ALTER TABLE payments DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,27 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reference VARCHAR(100) UNIQUE;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS authorization_url TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_amount INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS payment_cards (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  email VARCHAR(100) NOT NULL,
+  authorization_code VARCHAR(100) NOT NULL,
+  signature VARCHAR(100) NOT NULL,
+  card_type VARCHAR(50) NOT NULL,
+  last4 VARCHAR(4) NOT NULL,
+  exp_month VARCHAR(2) NOT NULL,
+  exp_year VARCHAR(4) NOT NULL,
+  bank VARCHAR(100),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (user_id, signature)
+);
+
+CREATE TABLE IF NOT EXISTS payment_events (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  provider VARCHAR(20) NOT NULL,
+  event_key VARCHAR(150) UNIQUE NOT NULL,
+  event VARCHAR(50) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS promo_balance INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users u
+SET promo_balance = a.balance
+FROM ledger_accounts a
+WHERE a.user_id = u.id AND a.kind = 'PROMO';
+
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS ledger_accounts;
+DROP FUNCTION IF EXISTS ledger_append_only;
+
+DELETE FROM payments WHERE trip_id IS NULL;
+ALTER TABLE payments DROP COLUMN IF EXISTS purpose;
+ALTER TABLE payments ALTER COLUMN trip_id SET NOT NULL;
@@ -0,0 +1,85 @@
+ALTER TABLE payments ALTER COLUMN trip_id DROP NOT NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS purpose VARCHAR(10) NOT NULL DEFAULT 'TRIP';
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID REFERENCES users,
+  kind VARCHAR(20) NOT NULL,
+  balance INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (user_id, kind),
+  -- Platform accounts may run negative, user wallets never
+  CONSTRAINT ledger_accounts_user_balance_check CHECK (user_id IS NULL OR balance >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ledger_accounts_platform_idx ON ledger_accounts(kind) WHERE user_id IS NULL;
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  kind VARCHAR(20) NOT NULL,
+  reference VARCHAR(150) UNIQUE NOT NULL,
+  trip_id UUID,
+  payment_id UUID,
+  description TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ledger_transactions_trip_idx ON ledger_transactions(trip_id);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  transaction_id UUID NOT NULL REFERENCES ledger_transactions,
+  account_id UUID NOT NULL REFERENCES ledger_accounts,
+  amount INTEGER NOT NULL,
+  balance INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_account_idx ON ledger_entries(account_id, created_at);
+
+CREATE OR REPLACE FUNCTION ledger_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_transactions_append_only
+BEFORE UPDATE OR DELETE ON ledger_transactions
+FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+CREATE TRIGGER ledger_entries_append_only
+BEFORE UPDATE OR DELETE ON ledger_entries
+FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+INSERT INTO ledger_accounts (kind) VALUES
+('PLATFORM_CASH'),
+('PLATFORM_REVENUE'),
+('PLATFORM_PROMO'),
+('PLATFORM_REFERRAL')
+ON CONFLICT (kind) WHERE user_id IS NULL DO NOTHING;
+
+-- Carry referral promo balances over into the ledger
+INSERT INTO ledger_accounts (user_id, kind, balance)
+SELECT id, 'PROMO', promo_balance FROM users
+WHERE promo_balance > 0;
+
+INSERT INTO ledger_transactions (kind, reference, description)
+SELECT 'REFERRAL_REWARD', 'opening:promo_balance', 'Opening promo balances'
+WHERE EXISTS (SELECT 1 FROM users WHERE promo_balance > 0);
+
+INSERT INTO ledger_entries (transaction_id, account_id, amount, balance)
+SELECT t.id, a.id, a.balance, a.balance
+FROM ledger_transactions t, ledger_accounts a
+WHERE t.reference = 'opening:promo_balance' AND a.kind = 'PROMO';
+
+UPDATE ledger_accounts
+SET balance = -(SELECT COALESCE(SUM(promo_balance), 0) FROM users)
+WHERE kind = 'PLATFORM_REFERRAL' AND user_id IS NULL;
+
+INSERT INTO ledger_entries (transaction_id, account_id, amount, balance)
+SELECT t.id, a.id, a.balance, a.balance
+FROM ledger_transactions t, ledger_accounts a
+WHERE t.reference = 'opening:promo_balance' AND a.kind = 'PLATFORM_REFERRAL' AND a.user_id IS NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS promo_balance;
@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS ledger_accounts_user_balance_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_user_balance_check CHECK (user_id IS NULL OR balance >= 0) NOT VALID;
@@ -0,0 +1,9 @@
+-- Courier earnings can go negative when penalties outrun the payout
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS ledger_accounts_user_balance_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_user_balance_check CHECK (user_id IS NULL OR kind = 'EARNINGS' OR balance >= 0);
+
+INSERT INTO ledger_accounts (kind) VALUES
+('PLATFORM_BONUS')
+ON CONFLICT (kind) WHERE user_id IS NULL DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payout_entries;
+DROP TABLE IF EXISTS payouts;
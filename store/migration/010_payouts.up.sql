@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS payouts (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  amount INTEGER NOT NULL,
+  phone VARCHAR(20) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  originator_conversation_id VARCHAR(100) UNIQUE,
+  conversation_id VARCHAR(100),
+  receipt VARCHAR(50),
+  result_code INTEGER,
+  result_desc TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One payout in flight per courier
+CREATE UNIQUE INDEX IF NOT EXISTS payouts_open_idx ON payouts(courier_id) WHERE status IN ('PENDING', 'PROCESSING');
+CREATE INDEX IF NOT EXISTS payouts_status_idx ON payouts(status, next_attempt_at);
+
+-- Earnings entries settled by a payout
+CREATE TABLE IF NOT EXISTS payout_entries (
+  payout_id UUID NOT NULL REFERENCES payouts ON DELETE CASCADE,
+  entry_id UUID UNIQUE NOT NULL REFERENCES ledger_entries,
+  PRIMARY KEY (payout_id, entry_id)
+);
+
+INSERT INTO ledger_accounts (kind) VALUES
+('PLATFORM_PAYOUT')
+ON CONFLICT (kind) WHERE user_id IS NULL DO NOTHING;
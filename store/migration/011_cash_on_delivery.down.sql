@@ -0,0 +1,5 @@
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS ledger_accounts_user_balance_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_user_balance_check CHECK (user_id IS NULL OR kind = 'EARNINGS' OR balance >= 0) NOT VALID;
+
+ALTER TABLE trips DROP COLUMN IF EXISTS collected_amount;
+ALTER TABLE trips DROP COLUMN IF EXISTS collect_amount;
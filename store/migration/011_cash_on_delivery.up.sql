@@ -0,0 +1,6 @@
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS collect_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS collected_amount INTEGER;
+
+-- Cash couriers collect on delivery is owed to us until netted or remitted
+ALTER TABLE ledger_accounts DROP CONSTRAINT IF EXISTS ledger_accounts_user_balance_check;
+ALTER TABLE ledger_accounts ADD CONSTRAINT ledger_accounts_user_balance_check CHECK (user_id IS NULL OR kind IN ('EARNINGS', 'CASH_HELD') OR balance >= 0);
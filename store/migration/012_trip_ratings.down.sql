@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE users DROP COLUMN IF EXISTS rating;
+ALTER TABLE couriers DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE couriers DROP COLUMN IF EXISTS rating;
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS ratings INTEGER NOT NULL DEFAULT 0;
+DROP TABLE IF EXISTS trip_ratings;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS trip_ratings (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  trip_id UUID NOT NULL REFERENCES trips ON DELETE CASCADE,
+  target VARCHAR(10) NOT NULL,
+  rater_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  ratee_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  tags TEXT[] NOT NULL DEFAULT '{}',
+  comment TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sender rates the courier and the courier rates the sender once per trip
+CREATE UNIQUE INDEX IF NOT EXISTS trip_ratings_trip_target_idx ON trip_ratings(trip_id, target);
+CREATE INDEX IF NOT EXISTS trip_ratings_ratee_idx ON trip_ratings(ratee_id, target, created_at DESC);
+
+-- Rolling averages, NULL until the first rating
+ALTER TABLE couriers DROP COLUMN IF EXISTS ratings;
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS rating DOUBLE PRECISION;
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS rating DOUBLE PRECISION;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE trips DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE trips DROP COLUMN IF EXISTS picked_up_at;
+ALTER TABLE trips DROP COLUMN IF EXISTS arrived_at;
+ALTER TABLE trips DROP COLUMN IF EXISTS assigned_at;
+ALTER TABLE couriers DROP COLUMN IF EXISTS tier;
+DROP TABLE IF EXISTS courier_points;
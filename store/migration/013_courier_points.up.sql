@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS courier_points (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  trip_id UUID REFERENCES trips ON DELETE SET NULL,
+  reason VARCHAR(20) NOT NULL,
+  points INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A trip awards or deducts each reason once
+CREATE UNIQUE INDEX IF NOT EXISTS courier_points_trip_reason_idx ON courier_points(courier_id, trip_id, reason);
+CREATE INDEX IF NOT EXISTS courier_points_courier_idx ON courier_points(courier_id, created_at DESC);
+
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS tier VARCHAR(10) NOT NULL DEFAULT 'BRONZE';
+
+-- Status timestamps for on-time and late arrival checks
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS arrived_at TIMESTAMP;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS picked_up_at TIMESTAMP;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
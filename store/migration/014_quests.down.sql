@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quest_trips;
+DROP TABLE IF EXISTS quest_progress;
+DROP TABLE IF EXISTS quests;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS quests (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  target_trips INTEGER NOT NULL CHECK (target_trips > 0),
+  reward INTEGER NOT NULL CHECK (reward > 0),
+  zone_id UUID REFERENCES zones ON DELETE CASCADE,
+  product_id UUID REFERENCES products ON DELETE CASCADE,
+  tier VARCHAR(10),
+  starts_at TIMESTAMP NOT NULL,
+  ends_at TIMESTAMP NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS quests_window_idx ON quests(starts_at, ends_at) WHERE active;
+
+CREATE TABLE IF NOT EXISTS quest_progress (
+  quest_id UUID NOT NULL REFERENCES quests ON DELETE CASCADE,
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  trips INTEGER NOT NULL DEFAULT 0,
+  completed_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (quest_id, courier_id)
+);
+
+-- Trips counted towards a quest, a trip counts once
+CREATE TABLE IF NOT EXISTS quest_trips (
+  quest_id UUID NOT NULL REFERENCES quests ON DELETE CASCADE,
+  trip_id UUID NOT NULL REFERENCES trips ON DELETE CASCADE,
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (quest_id, trip_id)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uploads_pending_idx;
+ALTER TABLE uploads DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE uploads DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE uploads DROP COLUMN IF EXISTS rejection_reason;
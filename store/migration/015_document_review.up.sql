@@ -0,0 +1,5 @@
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users ON DELETE SET NULL;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS uploads_pending_idx ON uploads(updated_at) WHERE courier_id IS NOT NULL AND verification = 'VERIFYING';
@@ -0,0 +1,20 @@
+-- Documents a courier needs verified to deliver with a product
+CREATE TABLE IF NOT EXISTS product_documents (
+  product_id UUID NOT NULL REFERENCES products ON DELETE CASCADE,
+  type VARCHAR(3) NOT NULL,
+  position INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (product_id, type)
+);
+
+-- Every courier needs a selfie and ID
+INSERT INTO product_documents (product_id, type, position)
+SELECT p.id, d.type, d.position FROM products p
+CROSS JOIN (VALUES ('DP', 1), ('ID', 2)) AS d(type, position)
+ON CONFLICT DO NOTHING;
+
+-- Motorised deliveries also need a licence, inspection and good conduct
+INSERT INTO product_documents (product_id, type, position)
+SELECT p.id, d.type, d.position FROM products p
+CROSS JOIN (VALUES ('LB', 3), ('VI', 4), ('PC', 5)) AS d(type, position)
+WHERE p.name IN ('UziBoda', 'Uzito')
+ON CONFLICT DO NOTHING;
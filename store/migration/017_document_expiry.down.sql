@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uploads_expiry_idx;
+ALTER TABLE uploads DROP COLUMN IF EXISTS expiry_warned_at;
+ALTER TABLE uploads DROP COLUMN IF EXISTS expires_at;
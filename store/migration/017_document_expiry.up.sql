@@ -0,0 +1,4 @@
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS expiry_warned_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS uploads_expiry_idx ON uploads(expires_at) WHERE courier_id IS NOT NULL AND verification = 'VERIFIED';
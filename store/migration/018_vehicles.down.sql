@@ -0,0 +1,2 @@
+ALTER TABLE couriers DROP COLUMN IF EXISTS vehicle_id;
+DROP TABLE IF EXISTS vehicles;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS vehicles (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  product_id UUID NOT NULL REFERENCES products ON DELETE CASCADE,
+  type VARCHAR(10) NOT NULL,
+  plate VARCHAR(20) NOT NULL,
+  make TEXT NOT NULL,
+  colour TEXT NOT NULL,
+  -- Load capacity in kilograms
+  capacity INTEGER NOT NULL,
+  status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+  rejection_reason TEXT,
+  reviewed_by UUID REFERENCES users ON DELETE SET NULL,
+  reviewed_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS vehicles_courier_idx ON vehicles(courier_id);
+CREATE INDEX IF NOT EXISTS vehicles_pending_idx ON vehicles(created_at) WHERE status = 'PENDING';
+-- A plate belongs to one courier unless the vehicle was rejected
+CREATE UNIQUE INDEX IF NOT EXISTS vehicles_plate_idx ON vehicles(plate) WHERE status <> 'REJECTED';
+
+-- The active vehicle decides the product a courier serves
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS vehicle_id UUID REFERENCES vehicles ON DELETE SET NULL;
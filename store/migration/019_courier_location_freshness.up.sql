@@ -0,0 +1,2 @@
+-- Couriers need a recent GPS fix to go online
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS location_updated_at TIMESTAMP;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS couriers_heartbeat_idx;
+DROP TABLE IF EXISTS courier_status_transitions;
@@ -0,0 +1,14 @@
+-- Every courier status change, for shift and hours reporting
+CREATE TABLE IF NOT EXISTS courier_status_transitions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  from_status VARCHAR(10) NOT NULL,
+  to_status VARCHAR(10) NOT NULL,
+  -- MANUAL, TRIP, HEARTBEAT or VERIFICATION
+  reason VARCHAR(12) NOT NULL,
+  trip_id UUID REFERENCES trips ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS courier_status_transitions_courier_idx ON courier_status_transitions(courier_id, created_at);
+CREATE INDEX IF NOT EXISTS couriers_heartbeat_idx ON couriers(location_updated_at) WHERE status IN ('ONLINE', 'ON_BREAK');
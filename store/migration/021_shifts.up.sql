@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS shifts (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  zone_id UUID NOT NULL REFERENCES zones ON DELETE CASCADE,
+  starts_at TIMESTAMP NOT NULL,
+  ends_at TIMESTAMP NOT NULL,
+  capacity INTEGER NOT NULL,
+  -- Minimum trip earnings for couriers who work the shift, 0 for none
+  guarantee INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS shifts_zone_idx ON shifts(zone_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS shift_bookings (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  shift_id UUID NOT NULL REFERENCES shifts ON DELETE CASCADE,
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  -- BOOKED, CANCELLED, CHECKED_IN, COMPLETED or NO_SHOW
+  status VARCHAR(10) NOT NULL DEFAULT 'BOOKED',
+  checked_in_at TIMESTAMP,
+  cancelled_at TIMESTAMP,
+  earned INTEGER NOT NULL DEFAULT 0,
+  guarantee_paid INTEGER NOT NULL DEFAULT 0,
+  settled_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shift_bookings_courier_idx ON shift_bookings(shift_id, courier_id) WHERE status <> 'CANCELLED';
+CREATE INDEX IF NOT EXISTS shift_bookings_open_idx ON shift_bookings(courier_id) WHERE status IN ('BOOKED', 'CHECKED_IN');
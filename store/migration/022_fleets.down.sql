@@ -0,0 +1,7 @@
+ALTER TABLE vehicles DROP COLUMN IF EXISTS fleet_approved_at;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS fleet_approved_by;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS fleet_id;
+DROP INDEX IF EXISTS couriers_fleet_idx;
+ALTER TABLE couriers DROP COLUMN IF EXISTS fleet_id;
+DROP TABLE IF EXISTS fleet_managers;
+DROP TABLE IF EXISTS fleets;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS fleets (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name TEXT NOT NULL,
+  -- Fleet share of trip earnings is credited to the owner
+  owner_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  -- Percent of trip earnings net of commission the rider keeps
+  rider_share INTEGER NOT NULL DEFAULT 100 CHECK (rider_share BETWEEN 0 AND 100),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS fleet_managers (
+  fleet_id UUID NOT NULL REFERENCES fleets ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (fleet_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS fleet_managers_user_idx ON fleet_managers(user_id);
+
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS fleet_id UUID REFERENCES fleets ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS couriers_fleet_idx ON couriers(fleet_id);
+
+-- Fleet vehicles also need a fleet manager to approve the rider using them
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS fleet_id UUID REFERENCES fleets ON DELETE SET NULL;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS fleet_approved_by UUID REFERENCES users ON DELETE SET NULL;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS fleet_approved_at TIMESTAMP;
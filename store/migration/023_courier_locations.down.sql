@@ -0,0 +1,3 @@
+ALTER TABLE trips DROP COLUMN IF EXISTS actual_distance;
+DROP TABLE IF EXISTS courier_locations;
+DROP FUNCTION IF EXISTS create_courier_locations_partition(DATE);
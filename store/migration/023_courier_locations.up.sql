@@ -0,0 +1,40 @@
+-- Append only GPS breadcrumbs, flushed in batches from the live location index
+CREATE TABLE IF NOT EXISTS courier_locations (
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  -- Trip the courier was on when the point was recorded
+  trip_id UUID REFERENCES trips ON DELETE SET NULL,
+  location GEOGRAPHY NOT NULL,
+  -- Meters
+  accuracy REAL,
+  -- Meters per second
+  speed REAL,
+  -- Degrees clockwise from north
+  heading REAL,
+  recorded_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (courier_id, recorded_at)
+) PARTITION BY RANGE (recorded_at);
+
+CREATE INDEX IF NOT EXISTS courier_locations_trip_idx ON courier_locations(trip_id, recorded_at) WHERE trip_id IS NOT NULL;
+
+-- Monthly partitions are created ahead by the app, the default partition only
+-- catches points recorded outside them
+CREATE OR REPLACE FUNCTION create_courier_locations_partition(month DATE) RETURNS VOID AS $$
+DECLARE
+  start_at DATE := date_trunc('month', month)::DATE;
+BEGIN
+  EXECUTE format(
+    'CREATE TABLE IF NOT EXISTS %I PARTITION OF courier_locations FOR VALUES FROM (%L) TO (%L)',
+    'courier_locations_' || to_char(start_at, 'YYYY_MM'),
+    start_at,
+    (start_at + INTERVAL '1 month')::DATE
+  );
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS courier_locations_default PARTITION OF courier_locations DEFAULT;
+SELECT create_courier_locations_partition(CURRENT_DATE);
+SELECT create_courier_locations_partition((CURRENT_DATE + INTERVAL '1 month')::DATE);
+
+-- Meters driven according to the recorded path
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS actual_distance INTEGER;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS payouts_open_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS payouts_open_idx ON payouts(courier_id) WHERE status IN ('PENDING', 'PROCESSING');
+
+ALTER TABLE payouts DROP COLUMN IF EXISTS status_conversation_id;
@@ -0,0 +1,5 @@
+-- Failed or timed out b2c results are checked with daraja before retrying
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS status_conversation_id VARCHAR(100) UNIQUE;
+
+DROP INDEX IF EXISTS payouts_open_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS payouts_open_idx ON payouts(courier_id) WHERE status IN ('PENDING', 'PROCESSING', 'VERIFYING');
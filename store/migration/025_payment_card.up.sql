@@ -0,0 +1,2 @@
+-- Card a paystack payment was charged to, so tips go on the same card
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS card_id UUID REFERENCES payment_cards(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS trips_fleet_idx;
+ALTER TABLE trips DROP COLUMN IF EXISTS vehicle_id;
+ALTER TABLE trips DROP COLUMN IF EXISTS fleet_id;
+DROP TABLE IF EXISTS fleet_invitations;
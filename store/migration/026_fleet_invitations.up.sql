@@ -0,0 +1,20 @@
+-- Couriers join a fleet by accepting a manager's invitation
+CREATE TABLE IF NOT EXISTS fleet_invitations (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  fleet_id UUID NOT NULL REFERENCES fleets ON DELETE CASCADE,
+  courier_id UUID NOT NULL REFERENCES couriers ON DELETE CASCADE,
+  invited_by UUID REFERENCES users ON DELETE SET NULL,
+  -- PENDING, ACCEPTED or DECLINED
+  status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+  responded_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS fleet_invitations_pending_idx ON fleet_invitations(fleet_id, courier_id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS fleet_invitations_courier_idx ON fleet_invitations(courier_id, created_at);
+
+-- Fleet and vehicle the courier rode for when they got the trip
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS fleet_id UUID REFERENCES fleets ON DELETE SET NULL;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS vehicle_id UUID REFERENCES vehicles ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS trips_fleet_idx ON trips(fleet_id, created_at);
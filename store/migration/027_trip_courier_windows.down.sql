@@ -0,0 +1 @@
+DROP INDEX IF EXISTS trips_courier_assigned_idx;
@@ -0,0 +1,2 @@
+-- Breadcrumbs find their trip by when the courier had it
+CREATE INDEX IF NOT EXISTS trips_courier_assigned_idx ON trips(courier_id, assigned_at) WHERE assigned_at IS NOT NULL;
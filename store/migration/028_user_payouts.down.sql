@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS payouts_open_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS payouts_open_idx ON payouts(courier_id) WHERE status IN ('PENDING', 'PROCESSING', 'VERIFYING');
+
+DELETE FROM payouts WHERE courier_id IS NULL;
+ALTER TABLE payouts ALTER COLUMN courier_id SET NOT NULL;
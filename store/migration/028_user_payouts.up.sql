@@ -0,0 +1,5 @@
+-- Fleet owners who don't ride are paid out too
+ALTER TABLE payouts ALTER COLUMN courier_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS payouts_open_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS payouts_open_idx ON payouts(user_id) WHERE status IN ('PENDING', 'PROCESSING', 'VERIFYING');
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payments_trip_tip_idx;
@@ -0,0 +1,2 @@
+-- A trip is tipped at most once, settled or on its way
+CREATE UNIQUE INDEX IF NOT EXISTS payments_trip_tip_idx ON payments(trip_id) WHERE purpose = 'TIP' AND status IN ('PENDING', 'PAID');
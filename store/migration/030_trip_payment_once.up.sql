@@ -0,0 +1,6 @@
+-- Abandoned checkouts shouldn't hold the trip
+UPDATE payments SET status = 'FAILED', result_desc = 'Checkout expired', updated_at = NOW()
+WHERE purpose = 'TRIP' AND status = 'PENDING' AND created_at <= NOW() - INTERVAL '2 minutes';
+
+-- A trip has one open payment, settled or on its way
+CREATE UNIQUE INDEX IF NOT EXISTS payments_trip_payment_idx ON payments(trip_id) WHERE purpose = 'TRIP' AND status IN ('PENDING', 'PAID');
@@ -6,11 +6,17 @@ INSERT INTO couriers (
 )
 RETURNING *;
 
+-- name: GetCourierStatusForUpdate :one
+SELECT status FROM couriers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
 -- name: SetCourierStatus :one
 UPDATE couriers
-SET status = $1
-WHERE user_id = $2
-RETURNING *;
+SET status = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id;
 
 -- name: GetCourierStatus :one
 SELECT status FROM
@@ -18,6 +24,12 @@ couriers
 WHERE user_id = $1
 LIMIT 1;
 
+-- name: GetCourierEligibility :one
+SELECT c.id, c.status, c.verified, c.vehicle_id, v.status AS vehicle_status, (v.fleet_id IS NULL OR v.fleet_approved_at IS NOT NULL)::BOOLEAN AS fleet_approved, c.location_updated_at FROM couriers c
+LEFT JOIN vehicles v ON v.id = c.vehicle_id
+WHERE c.user_id = $1
+LIMIT 1;
+
 -- name: IsCourier :one
 SELECT verified FROM
 couriers
@@ -25,22 +37,24 @@ WHERE user_id = $1
 LIMIT 1;
 
 -- name: GetCourierByUserID :one
-SELECT id, user_id, product_id, ST_AsGeoJSON(location) AS location FROM
+SELECT id, user_id, product_id, vehicle_id, rating, points, tier, ST_AsGeoJSON(location) AS location FROM
 couriers
 WHERE user_id = $1
 LIMIT 1;
 
 -- name: GetCourierByID :one
-SELECT id, trip_id, product_id, user_id, ST_AsGeoJSON(location) AS location FROM
+SELECT id, trip_id, product_id, vehicle_id, fleet_id, user_id, rating, points, tier, ST_AsGeoJSON(location) AS location FROM
 couriers
 WHERE id = $1
 LIMIT 1;
 
--- name: TrackCourierLocation :one
-UPDATE couriers
-SET location = sqlc.arg(location)
-WHERE user_id = $1
-RETURNING *;
+-- name: FlushCourierLocations :execrows
+UPDATE couriers c
+SET location = f.location::geography, location_updated_at = f.located_at
+FROM (
+  SELECT unnest(sqlc.arg(ids)::uuid[]) AS id, unnest(sqlc.arg(locations)::text[]) AS location, unnest(sqlc.arg(located_at)::timestamp[]) AS located_at
+) f
+WHERE c.id = f.id AND (c.location_updated_at IS NULL OR c.location_updated_at < f.located_at);
 
 -- name: GetProductByID :one
 SELECT id, icon, name, weight_class FROM products
@@ -68,9 +82,9 @@ LIMIT 1;
 
 -- name: CreateTrip :one
 INSERT INTO trips (
-  user_id, product_id, confirmed_pickup, start_location, end_location
+  user_id, product_id, confirmed_pickup, start_location, end_location, promotion_id, collect_amount
 ) VALUES (
-  $1, $2, $3, sqlc.arg(start_location), sqlc.arg(end_location)
+  $1, $2, $3, sqlc.arg(start_location), sqlc.arg(end_location), sqlc.narg(promotion_id), $4
 )
 RETURNING *;
 
@@ -82,18 +96,29 @@ WHERE c.product_id = p.id AND c.verified = 'true'
 ORDER BY p.relevance ASC;
 
 -- name: FindAvailableCourier :one
-SELECT id, user_id, product_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE ST_DWithin(location, sqlc.arg(point)::geography, sqlc.arg(radius)) AND status = 'ONLINE' AND verified = 'true' AND trip_id IS null
+-- Candidates and their distance from pickup come from the live location index
+SELECT c.id, c.user_id, c.product_id FROM couriers c
+JOIN (
+  SELECT unnest(sqlc.arg(ids)::uuid[]) AS id, unnest(sqlc.arg(distances)::float8[]) AS distance
+) n ON n.id = c.id
+WHERE c.status = 'ONLINE' AND c.verified = 'true' AND c.trip_id IS null
+AND c.product_id = sqlc.arg(product_id)
+-- Couriers checked in on a running shift go first
+ORDER BY EXISTS (
+  SELECT 1 FROM shift_bookings b
+  JOIN shifts s ON s.id = b.shift_id
+  WHERE b.courier_id = c.id AND b.status = 'CHECKED_IN' AND s.starts_at <= sqlc.arg(now) AND s.ends_at > sqlc.arg(now)
+) DESC,
+-- Better rated and higher tier couriers reach further, unrated ones count as 4 stars
+n.distance * (6 - COALESCE(c.rating, 4)) * CASE c.tier WHEN 'GOLD' THEN 0.8 WHEN 'SILVER' THEN 0.9 ELSE 1 END
 LIMIT 1;
 
 -- name: GetCourierNearPickupPoint :many
-SELECT id, product_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE ST_DWithin(location, sqlc.arg(point)::geography, sqlc.arg(radius)) AND status = 'ONLINE' AND verified = 'true';
+SELECT id, product_id FROM couriers
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND status = 'ONLINE' AND verified = 'true';
 
 -- name: GetTrip :one
-SELECT id, status, courier_id, cost, product_id, ST_AsGeoJSON(confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(start_location) AS start_location, ST_AsGeoJSON(end_location) AS end_location FROM trips
+SELECT id, status, courier_id, user_id, cost, distance, actual_distance, duration, promotion_id, discount, discount_funded_by, product_id, collect_amount, collected_amount, created_at, ST_AsGeoJSON(confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(start_location) AS start_location, ST_AsGeoJSON(end_location) AS end_location FROM trips
 WHERE id = $1
 LIMIT 1;
 
@@ -104,10 +129,11 @@ WHERE id = $2
 RETURNING *;
 
 -- name: AssignTripToCourier :one
-UPDATE trips
-SET courier_id = $1
-WHERE id = $2
-RETURNING *;
+UPDATE trips t
+SET courier_id = c.id, fleet_id = c.fleet_id, vehicle_id = c.vehicle_id
+FROM couriers c
+WHERE t.id = sqlc.arg(id) AND c.id = sqlc.arg(courier_id)
+RETURNING t.*;
 
 -- name: UnassignCourierTrip :one
 UPDATE couriers
@@ -117,16 +143,31 @@ RETURNING *;
 
 -- name: CreateTripCost :one
 UPDATE trips
-SET cost = $1
-WHERE id = $2
+SET cost = $1, distance = $2, duration = $3
+WHERE id = $4
 RETURNING *;
 
 -- name: SetTripStatus :one
 UPDATE trips
-SET status = $1
-WHERE id = $2
+SET status = sqlc.arg(status),
+  assigned_at = CASE WHEN sqlc.arg(status) = 'COURIER_ASSIGNED' THEN NOW() ELSE assigned_at END,
+  arrived_at = CASE WHEN sqlc.arg(status) = 'COURIER_ARRIVING' THEN COALESCE(arrived_at, NOW()) ELSE arrived_at END,
+  picked_up_at = CASE WHEN sqlc.arg(status) = 'COURIER_EN_ROUTE' THEN COALESCE(picked_up_at, NOW()) ELSE picked_up_at END,
+  completed_at = CASE WHEN sqlc.arg(status) = 'COMPLETE' THEN COALESCE(completed_at, NOW()) ELSE completed_at END
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: CompleteCourierTrip :one
+UPDATE trips
+SET status = 'COMPLETE', completed_at = COALESCE(completed_at, NOW())
+WHERE id = sqlc.arg(id) AND courier_id = sqlc.arg(courier_id) AND status = 'COURIER_EN_ROUTE'
+RETURNING *;
+
+-- name: GetCourierTripID :one
+SELECT trip_id FROM couriers
+WHERE id = $1
+LIMIT 1;
+
 -- name: GetCourierAssignedTrip :one
 SELECT * FROM couriers
 WHERE id = $1 AND trip_id = null
@@ -147,7 +188,7 @@ LIMIT 1;
 
 -- name: GetCourierTrip :one
 SELECT * FROM trips
-WHERE courier_id = $1
+WHERE id = $1 AND courier_id = $2
 LIMIT 1;
 
 -- name: CreateCourierUpload :one
@@ -170,7 +211,7 @@ WHERE courier_id = $1;
 
 -- name: UpdateUpload :one
 UPDATE uploads
-SET uri = COALESCE(sqlc.narg(uri), uri), verification = COALESCE(sqlc.narg(verification), verification)
+SET uri = COALESCE(sqlc.narg(uri), uri), verification = COALESCE(sqlc.narg(verification), verification), updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
@@ -193,9 +234,9 @@ WHERE courier_id = $1 AND type = 'DP';
 
 -- name: CreateUser :one
 INSERT INTO users (
-  first_name, last_name, phone
+  first_name, last_name, phone, referral_code, device_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -226,3 +267,986 @@ UPDATE users
 SET first_name = COALESCE($1, first_name), last_name = COALESCE($2, last_name)
 WHERE phone = $3
 RETURNING *;
+
+-- name: GetZoneByPoint :one
+SELECT id, name FROM zones
+WHERE ST_Covers(boundary, sqlc.arg(point)::geography)
+LIMIT 1;
+
+-- name: GetActivePricingSchedules :many
+SELECT id, name, kind, multiplier, start_time, end_time, product_id FROM pricing_schedules
+WHERE valid_from <= sqlc.arg(at) AND (valid_until IS NULL OR valid_until > sqlc.arg(at))
+AND (product_id IS NULL OR product_id = ANY(sqlc.arg(product_ids)::uuid[]))
+AND (zone_id IS NULL OR zone_id = sqlc.narg(zone_id));
+
+-- name: IsPublicHoliday :one
+SELECT EXISTS (
+  SELECT 1 FROM public_holidays
+  WHERE day = sqlc.arg(day)::date
+);
+
+-- name: GetPromotionByCode :one
+SELECT * FROM promotions
+WHERE code = $1
+LIMIT 1;
+
+-- name: GetPromotionForUpdate :one
+SELECT * FROM promotions
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: CountUserPromotionRedemptions :one
+SELECT COUNT(*) FROM promotion_redemptions
+WHERE promotion_id = $1 AND user_id = $2;
+
+-- name: CountUserCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = $1 AND status = 'COMPLETE';
+
+-- name: CountUserOtherTrips :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = sqlc.arg(user_id) AND id <> sqlc.arg(trip_id)
+AND status NOT IN ('CANCELLED', 'COURIER_NOT_FOUND');
+
+-- name: SpendPromotionBudget :one
+UPDATE promotions
+SET budget_used = budget_used + sqlc.arg(amount)
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePromotionRedemption :one
+INSERT INTO promotion_redemptions (
+  promotion_id, user_id, trip_id, amount
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: SetTripDiscount :one
+UPDATE trips
+SET discount = $1, discount_funded_by = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: GetUserByReferralCode :one
+SELECT * FROM users
+WHERE referral_code = $1
+LIMIT 1;
+
+-- name: GetUserReferral :one
+SELECT * FROM referrals
+WHERE referee_id = $1
+LIMIT 1;
+
+-- name: IsDeviceReferred :one
+SELECT EXISTS (
+  SELECT 1 FROM referrals
+  WHERE device_id = $1 AND status != 'REJECTED'
+);
+
+-- name: CreateReferral :one
+INSERT INTO referrals (
+  referrer_id, referee_id, kind, status, device_id
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetPendingReferral :one
+SELECT * FROM referrals
+WHERE referee_id = $1 AND kind = $2 AND status = 'PENDING'
+LIMIT 1;
+
+-- name: RewardReferral :one
+UPDATE referrals
+SET status = 'REWARDED', rewarded_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'PENDING'
+RETURNING *;
+
+-- name: GetTripDiscountForUpdate :one
+SELECT discount, discount_funded_by FROM trips
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: CountCourierCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE courier_id = $1 AND status = 'COMPLETE';
+
+-- name: CreatePayment :one
+INSERT INTO payments (
+  trip_id, user_id, provider, purpose, amount, phone, card_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: SetPaymentCard :exec
+UPDATE payments
+SET card_id = $1, updated_at = NOW()
+WHERE id = $2;
+
+-- name: SetPaymentCheckout :one
+UPDATE payments
+SET merchant_request_id = $1, checkout_request_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: GetPaymentByCheckoutIDForUpdate :one
+SELECT * FROM payments
+WHERE checkout_request_id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: SetPaymentResult :one
+UPDATE payments
+SET status = $1, receipt = $2, result_code = $3, result_desc = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING *;
+
+-- name: GetTripPayment :one
+SELECT * FROM payments
+WHERE trip_id = $1 AND purpose = 'TRIP'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: HasOpenTripPayment :one
+SELECT EXISTS (
+  SELECT 1 FROM payments
+  WHERE trip_id = $1 AND purpose = $2 AND status IN ('PENDING', 'PAID')
+);
+
+-- name: ExpireStaleTripPayments :exec
+UPDATE payments
+SET status = 'FAILED', result_desc = 'Checkout expired', updated_at = NOW()
+WHERE trip_id = $1 AND purpose = $2 AND status = 'PENDING'
+AND created_at <= NOW() - INTERVAL '2 minutes';
+
+-- name: SetPaymentReference :one
+UPDATE payments
+SET reference = $1, authorization_url = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: GetPaymentByReferenceForUpdate :one
+SELECT * FROM payments
+WHERE reference = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetPaidTripPaymentForUpdate :one
+SELECT * FROM payments
+WHERE trip_id = $1 AND purpose = 'TRIP' AND status = 'PAID'
+LIMIT 1
+FOR UPDATE;
+
+-- name: SetPaymentStatus :one
+UPDATE payments
+SET status = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: RefundPayment :one
+UPDATE payments
+SET status = $1, refunded_amount = refunded_amount + $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: SavePaymentCard :one
+INSERT INTO payment_cards (
+  user_id, email, authorization_code, signature, card_type, last4, exp_month, exp_year, bank
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (user_id, signature) DO UPDATE
+SET authorization_code = EXCLUDED.authorization_code, exp_month = EXCLUDED.exp_month, exp_year = EXCLUDED.exp_year, updated_at = NOW()
+RETURNING *;
+
+-- name: GetUserPaymentCards :many
+SELECT * FROM payment_cards
+WHERE user_id = $1
+ORDER BY updated_at DESC;
+
+-- name: GetUserPaymentCard :one
+SELECT * FROM payment_cards
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: CreatePaymentEvent :one
+INSERT INTO payment_events (
+  provider, event_key, event
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (event_key) DO NOTHING
+RETURNING *;
+
+-- name: EnsureLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  user_id, kind
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id, kind) DO NOTHING;
+
+-- name: GetUserLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE user_id = $1 AND kind = $2
+LIMIT 1;
+
+-- name: GetPlatformLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE user_id IS NULL AND kind = $1
+LIMIT 1;
+
+-- name: LockLedgerAccounts :many
+SELECT * FROM ledger_accounts
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: UpdateLedgerAccountBalance :one
+UPDATE ledger_accounts
+SET balance = balance + sqlc.arg(amount), updated_at = NOW()
+WHERE id = $1
+RETURNING balance;
+
+-- name: CreateLedgerTransaction :one
+INSERT INTO ledger_transactions (
+  kind, reference, trip_id, payment_id, description
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (reference) DO NOTHING
+RETURNING *;
+
+-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (
+  transaction_id, account_id, amount, balance
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetUserLedgerAccounts :many
+SELECT * FROM ledger_accounts
+WHERE user_id = $1;
+
+-- name: GetUserLedgerStatement :many
+SELECT e.id, e.amount, e.balance, e.created_at, a.kind AS account, t.kind, t.description, t.trip_id FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1
+ORDER BY e.created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetTripHeldAmount :one
+SELECT COALESCE(SUM(e.amount), 0)::integer AS held FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE t.trip_id = $1 AND a.user_id = $2 AND a.kind = 'HOLD';
+
+-- name: GetUserLedgerEntriesBetween :many
+SELECT e.id, e.amount, e.created_at, t.kind, t.trip_id FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1 AND a.kind = $2 AND e.created_at >= sqlc.arg(start_at) AND e.created_at < sqlc.arg(end_at)
+ORDER BY e.created_at;
+
+-- name: IsUserAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetPayoutCandidates :many
+-- Couriers and fleet owners alike are paid their earnings
+SELECT c.id AS courier_id, u.id AS user_id, u.phone FROM ledger_accounts a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN couriers c ON c.user_id = u.id
+WHERE a.kind = 'EARNINGS' AND a.balance >= sqlc.arg(minimum)
+AND NOT EXISTS (
+  SELECT 1 FROM payouts p
+  WHERE p.user_id = u.id AND p.status IN ('PENDING', 'PROCESSING', 'VERIFYING')
+);
+
+-- name: GetUnpaidEarningsEntries :many
+-- Earnings settled before the cutoff and every deduction so far, so
+-- deductions after the cutoff come out of this payout and no other
+SELECT e.id, e.amount FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE e.account_id = $1 AND (e.created_at < sqlc.arg(cutoff) OR e.amount < 0)
+AND t.kind IN ('TRIP_FARE', 'COMMISSION', 'FLEET_SHARE', 'TIP', 'BONUS', 'PENALTY', 'COD_SETTLEMENT', 'COD_REMITTANCE')
+AND NOT EXISTS (
+  SELECT 1 FROM payout_entries pe
+  WHERE pe.entry_id = e.id
+);
+
+-- name: CreatePayout :one
+INSERT INTO payouts (
+  courier_id, user_id, amount, phone
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: CreatePayoutEntries :exec
+INSERT INTO payout_entries (payout_id, entry_id)
+SELECT sqlc.arg(payout_id), unnest(sqlc.arg(entry_ids)::uuid[]);
+
+-- name: DeletePayoutEntries :exec
+DELETE FROM payout_entries
+WHERE payout_id = $1;
+
+-- name: GetDuePayouts :many
+SELECT id FROM payouts
+WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at;
+
+-- name: GetDuePayoutVerifications :many
+SELECT * FROM payouts
+WHERE status = 'VERIFYING' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at;
+
+-- name: GetPayoutForUpdate :one
+SELECT * FROM payouts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetPayoutByOriginatorIDForUpdate :one
+SELECT * FROM payouts
+WHERE originator_conversation_id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetPayoutByStatusConversationForUpdate :one
+SELECT * FROM payouts
+WHERE status_conversation_id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: SetPayoutSubmitted :one
+UPDATE payouts
+SET status = 'PROCESSING', attempts = attempts + 1, originator_conversation_id = $1, conversation_id = NULL, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: SetPayoutConversation :exec
+UPDATE payouts
+SET conversation_id = $1, updated_at = NOW()
+WHERE id = $2;
+
+-- name: SetPayoutVerifying :one
+UPDATE payouts
+SET status = 'VERIFYING', next_attempt_at = sqlc.arg(next_attempt_at), result_code = sqlc.arg(result_code), result_desc = sqlc.arg(result_desc), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetPayoutStatusConversation :exec
+UPDATE payouts
+SET status_conversation_id = sqlc.arg(status_conversation_id), next_attempt_at = sqlc.arg(next_attempt_at), updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: SetPayoutRetry :one
+UPDATE payouts
+SET status = 'PENDING', attempts = $1, next_attempt_at = $2, result_code = $3, result_desc = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING *;
+
+-- name: SetPayoutResult :one
+UPDATE payouts
+SET status = $1, attempts = $2, receipt = $3, result_code = $4, result_desc = $5, updated_at = NOW()
+WHERE id = $6
+RETURNING *;
+
+-- name: GetFailedPayouts :many
+SELECT * FROM payouts
+WHERE status = 'FAILED'
+ORDER BY updated_at DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: SetTripCollectedAmount :one
+UPDATE trips
+SET collected_amount = $1, updated_at = NOW()
+WHERE id = $2 AND collected_amount IS NULL
+RETURNING id;
+
+-- name: GetCouriersHoldingCash :many
+SELECT c.id AS courier_id, u.id AS user_id, u.first_name, u.last_name, u.phone, -a.balance AS cash_held, COALESCE(e.balance, 0)::integer AS earnings, a.updated_at FROM ledger_accounts a
+JOIN users u ON u.id = a.user_id
+JOIN couriers c ON c.user_id = u.id
+LEFT JOIN ledger_accounts e ON e.user_id = u.id AND e.kind = 'EARNINGS'
+WHERE a.kind = 'CASH_HELD' AND -a.balance >= sqlc.arg(minimum)
+ORDER BY a.balance;
+
+-- name: CreateTripRating :one
+INSERT INTO trip_ratings (
+  trip_id, target, rater_id, ratee_id, rating, tags, comment
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (trip_id, target) DO NOTHING
+RETURNING *;
+
+-- name: GetTripRatings :many
+SELECT * FROM trip_ratings
+WHERE trip_id = $1
+ORDER BY created_at;
+
+-- name: SetCourierRating :exec
+UPDATE couriers
+SET rating = (
+  SELECT AVG(r.rating)::DOUBLE PRECISION FROM (
+    SELECT rating FROM trip_ratings
+    WHERE ratee_id = sqlc.arg(user_id) AND target = 'COURIER'
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg(window_size)
+  ) r
+), rating_count = rating_count + 1, updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: SetUserRating :exec
+UPDATE users
+SET rating = (
+  SELECT AVG(r.rating)::DOUBLE PRECISION FROM (
+    SELECT rating FROM trip_ratings
+    WHERE ratee_id = sqlc.arg(user_id) AND target = 'SENDER'
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg(window_size)
+  ) r
+), rating_count = rating_count + 1, updated_at = NOW()
+WHERE id = sqlc.arg(user_id);
+
+-- name: CreateCourierPoints :one
+INSERT INTO courier_points (
+  courier_id, trip_id, reason, points
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (courier_id, trip_id, reason) DO NOTHING
+RETURNING *;
+
+-- name: AddCourierPoints :one
+UPDATE couriers
+SET points = GREATEST(points + sqlc.arg(points), 0), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING points;
+
+-- name: SetCourierTier :exec
+UPDATE couriers
+SET tier = $1
+WHERE id = $2;
+
+-- name: GetCourierPoints :many
+SELECT * FROM courier_points
+WHERE courier_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: GetCourierCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE courier_id = $1 AND status = 'COMPLETE';
+
+-- name: GetTripTimeline :one
+SELECT id, courier_id, duration, assigned_at, arrived_at, picked_up_at, completed_at FROM trips
+WHERE id = $1
+LIMIT 1;
+
+-- name: ClearTripCourier :one
+UPDATE trips
+SET courier_id = NULL, fleet_id = NULL, vehicle_id = NULL, assigned_at = NULL, arrived_at = NULL
+WHERE id = $1 AND courier_id = $2
+RETURNING id;
+
+-- name: CreateQuest :one
+INSERT INTO quests (
+  name, description, target_trips, reward, zone_id, product_id, tier, starts_at, ends_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetEligibleQuests :many
+SELECT * FROM quests
+WHERE active AND starts_at <= sqlc.arg(at) AND ends_at > sqlc.arg(at)
+AND (product_id IS NULL OR product_id = sqlc.arg(product_id))
+AND (zone_id IS NULL OR zone_id = sqlc.narg(zone_id))
+AND (tier IS NULL OR tier = sqlc.arg(tier));
+
+-- name: CreateQuestTrip :one
+INSERT INTO quest_trips (
+  quest_id, trip_id, courier_id
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (quest_id, trip_id) DO NOTHING
+RETURNING quest_id;
+
+-- name: AddQuestProgress :one
+INSERT INTO quest_progress (
+  quest_id, courier_id, trips
+) VALUES (
+  $1, $2, 1
+)
+ON CONFLICT (quest_id, courier_id) DO UPDATE
+SET trips = quest_progress.trips + 1, updated_at = NOW()
+RETURNING *;
+
+-- name: SetQuestCompleted :one
+UPDATE quest_progress
+SET completed_at = NOW(), updated_at = NOW()
+WHERE quest_id = $1 AND courier_id = $2 AND completed_at IS NULL
+RETURNING *;
+
+-- name: GetCourierQuests :many
+SELECT q.*, COALESCE(p.trips, 0)::integer AS progress, p.completed_at FROM quests q
+LEFT JOIN quest_progress p ON p.quest_id = q.id AND p.courier_id = sqlc.arg(courier_id)
+WHERE q.active AND q.ends_at > NOW()
+AND (q.product_id IS NULL OR q.product_id = sqlc.arg(product_id))
+AND (q.tier IS NULL OR q.tier = sqlc.arg(tier))
+ORDER BY q.ends_at;
+
+-- name: GetPendingCourierUploads :many
+SELECT * FROM uploads
+WHERE courier_id IS NOT NULL AND verification = 'VERIFYING'
+ORDER BY updated_at
+LIMIT $1
+OFFSET $2;
+
+-- name: GetUploadForUpdate :one
+SELECT * FROM uploads
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ReviewUpload :one
+UPDATE uploads
+SET verification = $1, rejection_reason = $2, reviewed_by = $3, expires_at = $4, expiry_warned_at = NULL, reviewed_at = NOW(), updated_at = NOW()
+WHERE id = $5
+RETURNING *;
+
+-- name: SetCourierVerified :one
+UPDATE couriers
+SET verified = sqlc.arg(verified),
+  status = CASE
+    WHEN sqlc.arg(verified)::boolean AND status = 'ONBOARDING' THEN 'OFFLINE'
+    WHEN NOT sqlc.arg(verified)::boolean THEN 'ONBOARDING'
+    ELSE status
+  END,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetProductDocuments :many
+SELECT type FROM product_documents
+WHERE product_id = $1
+ORDER BY position;
+
+-- name: CountMissingCourierDocuments :one
+SELECT COUNT(*) FROM product_documents d
+LEFT JOIN uploads u ON u.courier_id = sqlc.arg(courier_id) AND u.type = d.type AND u.verification = 'VERIFIED'
+WHERE d.product_id = sqlc.arg(product_id) AND u.id IS NULL;
+
+-- name: GetCourierOnboarding :one
+SELECT c.id, c.product_id, c.vehicle_id, c.fleet_id, c.trip_id, c.status, c.verified, v.status AS vehicle_status, (v.fleet_id IS NULL OR v.fleet_approved_at IS NOT NULL)::BOOLEAN AS fleet_approved FROM couriers c
+LEFT JOIN vehicles v ON v.id = c.vehicle_id
+WHERE c.id = $1
+LIMIT 1;
+
+-- name: SetCourierVehicle :one
+UPDATE couriers
+SET vehicle_id = $1, product_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id;
+
+-- name: GetProducts :many
+SELECT * FROM products
+ORDER BY relevance;
+
+-- name: GetExpiringCourierUploads :many
+SELECT * FROM uploads
+WHERE courier_id IS NOT NULL AND verification = 'VERIFIED' AND expiry_warned_at IS NULL
+AND expires_at > sqlc.arg(now) AND expires_at <= sqlc.arg(warn_before)
+ORDER BY expires_at;
+
+-- name: SetUploadExpiryWarned :exec
+UPDATE uploads
+SET expiry_warned_at = $1
+WHERE id = $2;
+
+-- name: ExpireCourierUploads :many
+UPDATE uploads
+SET verification = 'REJECTED', rejection_reason = sqlc.arg(reason), updated_at = sqlc.arg(now)
+WHERE courier_id IS NOT NULL AND verification = 'VERIFIED' AND expires_at <= sqlc.arg(now)
+RETURNING *;
+
+-- name: CountExpiredCourierDocuments :one
+SELECT COUNT(*) FROM uploads u
+JOIN couriers c ON c.id = u.courier_id
+JOIN product_documents d ON d.product_id = c.product_id AND d.type = u.type
+WHERE c.user_id = sqlc.arg(user_id) AND u.expires_at <= sqlc.arg(now);
+
+-- name: CreateVehicle :one
+INSERT INTO vehicles (
+  courier_id, product_id, type, plate, make, colour, capacity, fleet_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: IsPlateRegistered :one
+SELECT EXISTS (
+  SELECT 1 FROM vehicles
+  WHERE plate = $1 AND status <> 'REJECTED'
+);
+
+-- name: GetVehicle :one
+SELECT * FROM vehicles
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetVehicleForUpdate :one
+SELECT * FROM vehicles
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetCourierVehicles :many
+SELECT * FROM vehicles
+WHERE courier_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetPendingVehicles :many
+SELECT sqlc.embed(v), c.vehicle_id AS active_vehicle_id FROM vehicles v
+JOIN couriers c ON c.id = v.courier_id
+WHERE v.status = 'PENDING'
+ORDER BY v.created_at
+LIMIT $1
+OFFSET $2;
+
+-- name: ReviewVehicle :one
+UPDATE vehicles
+SET status = $1, rejection_reason = $2, reviewed_by = $3, reviewed_at = NOW(), updated_at = NOW()
+WHERE id = $4
+RETURNING *;
+
+-- name: CreateCourierStatusTransition :exec
+INSERT INTO courier_status_transitions (
+  courier_id, from_status, to_status, reason, trip_id
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: SetStaleCouriersOffline :many
+UPDATE couriers c
+SET status = 'OFFLINE', updated_at = NOW()
+FROM couriers prev
+WHERE c.id = prev.id AND prev.status IN ('ONLINE', 'ON_BREAK')
+AND (prev.location_updated_at IS NULL OR prev.location_updated_at < sqlc.arg(stale_before))
+RETURNING c.id, prev.status AS from_status;
+
+-- name: CreateShift :one
+INSERT INTO shifts (
+  zone_id, starts_at, ends_at, capacity, guarantee
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetShiftForUpdate :one
+SELECT * FROM shifts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetUpcomingShifts :many
+SELECT sqlc.embed(s), z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings b
+  WHERE b.shift_id = s.id AND b.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shifts s
+JOIN zones z ON z.id = s.zone_id
+WHERE s.starts_at > sqlc.arg(now) AND (sqlc.narg(zone_id)::uuid IS NULL OR s.zone_id = sqlc.narg(zone_id))
+ORDER BY s.starts_at;
+
+-- name: CountShiftBookings :one
+SELECT COUNT(*) FROM shift_bookings
+WHERE shift_id = $1 AND status IN ('BOOKED', 'CHECKED_IN');
+
+-- name: CountOverlappingShiftBookings :one
+SELECT COUNT(*) FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+WHERE b.courier_id = sqlc.arg(courier_id) AND b.status IN ('BOOKED', 'CHECKED_IN')
+AND s.starts_at < sqlc.arg(ends_at) AND s.ends_at > sqlc.arg(starts_at);
+
+-- name: CreateShiftBooking :one
+INSERT INTO shift_bookings (
+  shift_id, courier_id
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetShiftBooking :one
+SELECT sqlc.embed(b), sqlc.embed(s), z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings o
+  WHERE o.shift_id = s.id AND o.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+JOIN zones z ON z.id = s.zone_id
+WHERE b.id = $1
+LIMIT 1;
+
+-- name: GetCourierShiftBookings :many
+SELECT sqlc.embed(b), sqlc.embed(s), z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings o
+  WHERE o.shift_id = s.id AND o.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+JOIN zones z ON z.id = s.zone_id
+WHERE b.courier_id = $1
+ORDER BY s.starts_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetShiftBookingForUpdate :one
+SELECT * FROM shift_bookings
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: CancelShiftBooking :one
+UPDATE shift_bookings
+SET status = 'CANCELLED', cancelled_at = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: CheckInShiftBooking :one
+UPDATE shift_bookings
+SET status = 'CHECKED_IN', checked_in_at = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: IsCourierInZone :one
+SELECT EXISTS (
+  SELECT 1 FROM couriers c
+  JOIN zones z ON ST_Covers(z.boundary, c.location)
+  WHERE c.id = sqlc.arg(courier_id) AND z.id = sqlc.arg(zone_id) AND c.location_updated_at >= sqlc.arg(located_after)
+);
+
+-- name: GetEndedShiftBookings :many
+SELECT b.id FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+WHERE b.status IN ('BOOKED', 'CHECKED_IN') AND s.ends_at <= $1
+ORDER BY s.ends_at;
+
+-- name: GetCourierEarningsBetween :one
+SELECT COALESCE(SUM(e.amount), 0)::integer AS earned FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1 AND a.kind = 'EARNINGS' AND t.kind IN ('TRIP_FARE', 'COMMISSION')
+AND e.created_at >= sqlc.arg(start_at) AND e.created_at < sqlc.arg(end_at);
+
+-- name: GetCourierOnlineSecondsBetween :one
+WITH spans AS (
+  SELECT to_status, created_at AS started_at,
+    LEAD(created_at, 1, sqlc.arg(end_at)::timestamp) OVER (ORDER BY created_at) AS ended_at
+  FROM courier_status_transitions t
+  WHERE t.courier_id = sqlc.arg(courier_id) AND t.created_at < sqlc.arg(end_at)::timestamp
+  AND t.created_at >= COALESCE((
+    SELECT MAX(p.created_at) FROM courier_status_transitions p
+    WHERE p.courier_id = sqlc.arg(courier_id) AND p.created_at <= sqlc.arg(start_at)::timestamp
+  ), sqlc.arg(start_at)::timestamp)
+)
+SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (
+  LEAST(ended_at, sqlc.arg(end_at)::timestamp) - GREATEST(started_at, sqlc.arg(start_at)::timestamp)
+))), 0)::bigint AS online_seconds FROM spans
+WHERE to_status IN ('ONLINE', 'BUSY') AND ended_at > sqlc.arg(start_at)::timestamp;
+
+-- name: SettleShiftBooking :one
+UPDATE shift_bookings
+SET status = $1, earned = $2, guarantee_paid = $3, settled_at = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING *;
+
+-- name: GetZoneName :one
+SELECT name FROM zones
+WHERE id = $1
+LIMIT 1;
+
+-- name: CreateFleet :one
+INSERT INTO fleets (
+  name, owner_id, rider_share
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetFleet :one
+SELECT * FROM fleets
+WHERE id = $1
+LIMIT 1;
+
+-- name: SetFleetRiderShare :one
+UPDATE fleets
+SET rider_share = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: AddFleetManager :exec
+INSERT INTO fleet_managers (
+  fleet_id, user_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: IsFleetManager :one
+SELECT EXISTS (
+  SELECT 1 FROM fleet_managers
+  WHERE fleet_id = $1 AND user_id = $2
+);
+
+-- name: OwnsFleet :one
+SELECT EXISTS (
+  SELECT 1 FROM fleets
+  WHERE owner_id = $1
+);
+
+-- name: GetManagedFleets :many
+SELECT f.* FROM fleets f
+JOIN fleet_managers m ON m.fleet_id = f.id
+WHERE m.user_id = $1
+ORDER BY f.name;
+
+-- name: SetCourierFleet :one
+UPDATE couriers
+SET fleet_id = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id;
+
+-- name: ClearCourierVehicle :one
+UPDATE couriers
+SET vehicle_id = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING id;
+
+-- name: GetCourierByPhone :one
+SELECT c.id, c.fleet_id FROM couriers c
+JOIN users u ON u.id = c.user_id
+WHERE u.phone = $1
+LIMIT 1;
+
+-- name: GetFleetRiders :many
+SELECT c.id AS courier_id, c.user_id, u.first_name, u.last_name, u.phone, c.status, ST_AsGeoJSON(c.location) AS location, c.location_updated_at, c.trip_id, c.vehicle_id FROM couriers c
+JOIN users u ON u.id = c.user_id
+WHERE c.fleet_id = $1
+ORDER BY u.first_name, u.last_name;
+
+-- name: GetFleetTrips :many
+SELECT t.id, t.status, t.courier_id, t.user_id, t.cost, t.distance, t.duration, t.discount, t.product_id, t.collect_amount, t.created_at, ST_AsGeoJSON(t.confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(t.start_location) AS start_location, ST_AsGeoJSON(t.end_location) AS end_location FROM trips t
+WHERE t.fleet_id = $1
+ORDER BY t.created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetFleetPendingVehicles :many
+SELECT sqlc.embed(v), c.vehicle_id AS active_vehicle_id FROM vehicles v
+JOIN couriers c ON c.id = v.courier_id
+WHERE v.fleet_id = $1 AND v.fleet_approved_at IS NULL AND v.status <> 'REJECTED'
+ORDER BY v.created_at;
+
+-- name: ApproveFleetVehicle :one
+UPDATE vehicles
+SET fleet_approved_by = $1, fleet_approved_at = NOW(), updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: GetTripFleet :one
+SELECT t.fleet_id, (v.fleet_id IS NOT NULL AND v.fleet_id = t.fleet_id)::BOOLEAN AS fleet_vehicle FROM trips t
+LEFT JOIN vehicles v ON v.id = t.vehicle_id
+WHERE t.id = $1
+LIMIT 1;
+
+-- name: CreateFleetInvitation :one
+INSERT INTO fleet_invitations (
+  fleet_id, courier_id, invited_by
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (fleet_id, courier_id) WHERE status = 'PENDING' DO UPDATE
+SET invited_by = EXCLUDED.invited_by, updated_at = NOW()
+RETURNING *;
+
+-- name: GetCourierFleetInvitations :many
+SELECT i.id, i.fleet_id, f.name AS fleet_name, f.rider_share, i.status, i.created_at FROM fleet_invitations i
+JOIN fleets f ON f.id = i.fleet_id
+JOIN couriers c ON c.id = i.courier_id
+WHERE c.user_id = $1 AND i.status = 'PENDING'
+ORDER BY i.created_at DESC;
+
+-- name: GetCourierFleetInvitationForUpdate :one
+SELECT i.* FROM fleet_invitations i
+JOIN couriers c ON c.id = i.courier_id
+WHERE i.id = $1 AND c.user_id = $2
+LIMIT 1
+FOR UPDATE OF i;
+
+-- name: RespondFleetInvitation :one
+UPDATE fleet_invitations
+SET status = $1, responded_at = NOW(), updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: CreateCourierBreadcrumbs :many
+-- Points buffered offline belong to the trip the courier was on when they
+-- were recorded, not when they were uploaded. Returns the completed trips
+-- that got points after the fact.
+WITH inserted AS (
+  INSERT INTO courier_locations (
+    courier_id, trip_id, location, accuracy, speed, heading, recorded_at
+  )
+  SELECT p.courier_id, (
+    SELECT t.id FROM trips t
+    WHERE t.courier_id = p.courier_id AND t.assigned_at <= p.recorded_at
+    AND t.status NOT IN ('CANCELLED', 'COURIER_NOT_FOUND')
+    AND (t.completed_at IS NULL OR t.completed_at >= p.recorded_at)
+    ORDER BY t.assigned_at DESC
+    LIMIT 1
+  ), ST_SetSRID(ST_MakePoint(p.lng, p.lat), 4326)::geography, p.accuracy, p.speed, p.heading, p.recorded_at
+  FROM jsonb_to_recordset(sqlc.arg(points)::jsonb) AS p(
+    courier_id UUID, lat FLOAT8, lng FLOAT8, accuracy REAL, speed REAL, heading REAL, recorded_at TIMESTAMP
+  )
+  ORDER BY p.recorded_at
+  ON CONFLICT DO NOTHING
+  RETURNING trip_id
+)
+SELECT DISTINCT i.trip_id FROM inserted i
+JOIN trips t ON t.id = i.trip_id
+WHERE t.status = 'COMPLETE';
+
+-- name: CreateCourierLocationsPartition :exec
+SELECT create_courier_locations_partition(sqlc.arg(month)::DATE);
+
+-- name: GetTripPath :one
+SELECT COUNT(*)::INTEGER AS points,
+  COALESCE(ST_Length(ST_MakeLine(location::geometry ORDER BY recorded_at)::geography), 0)::INTEGER AS distance,
+  COALESCE(ST_AsEncodedPolyline(ST_MakeLine(location::geometry ORDER BY recorded_at)), '')::TEXT AS polyline,
+  COALESCE(ST_AsGeoJSON(ST_MakeLine(location::geometry ORDER BY recorded_at)), '')::TEXT AS geojson
+FROM courier_locations
+WHERE trip_id = sqlc.arg(trip_id) AND recorded_at >= sqlc.arg(since);
+
+-- name: RecordTripDistances :execrows
+UPDATE trips t
+SET actual_distance = COALESCE((
+  SELECT ST_Length(ST_MakeLine(l.location::geometry ORDER BY l.recorded_at)::geography)
+  FROM courier_locations l
+  WHERE l.trip_id = t.id AND l.recorded_at >= t.created_at
+), 0), updated_at = NOW()
+WHERE t.status = 'COMPLETE' AND (
+  (t.actual_distance IS NULL AND t.completed_at >= sqlc.arg(completed_after) AND t.completed_at < sqlc.arg(completed_before))
+  -- Late points change the distance of trips already measured
+  OR t.id = ANY(sqlc.arg(trip_ids)::uuid[])
+);
@@ -12,19 +12,203 @@ import (
 )
 
 type Courier struct {
+	ID                uuid.UUID       `json:"id"`
+	Verified          sql.NullBool    `json:"verified"`
+	Status            string          `json:"status"`
+	Location          interface{}     `json:"location"`
+	Points            int32           `json:"points"`
+	UserID            uuid.NullUUID   `json:"user_id"`
+	ProductID         uuid.NullUUID   `json:"product_id"`
+	TripID            uuid.NullUUID   `json:"trip_id"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	Rating            sql.NullFloat64 `json:"rating"`
+	RatingCount       int32           `json:"rating_count"`
+	Tier              string          `json:"tier"`
+	VehicleID         uuid.NullUUID   `json:"vehicle_id"`
+	LocationUpdatedAt sql.NullTime    `json:"location_updated_at"`
+	FleetID           uuid.NullUUID   `json:"fleet_id"`
+}
+
+type CourierLocation struct {
+	CourierID  uuid.UUID       `json:"courier_id"`
+	TripID     uuid.NullUUID   `json:"trip_id"`
+	Location   interface{}     `json:"location"`
+	Accuracy   sql.NullFloat64 `json:"accuracy"`
+	Speed      sql.NullFloat64 `json:"speed"`
+	Heading    sql.NullFloat64 `json:"heading"`
+	RecordedAt time.Time       `json:"recorded_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type CourierLocationsDefault struct {
+	CourierID  uuid.UUID       `json:"courier_id"`
+	TripID     uuid.NullUUID   `json:"trip_id"`
+	Location   interface{}     `json:"location"`
+	Accuracy   sql.NullFloat64 `json:"accuracy"`
+	Speed      sql.NullFloat64 `json:"speed"`
+	Heading    sql.NullFloat64 `json:"heading"`
+	RecordedAt time.Time       `json:"recorded_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type CourierPoint struct {
 	ID        uuid.UUID     `json:"id"`
-	Verified  sql.NullBool  `json:"verified"`
-	Status    string        `json:"status"`
-	Location  interface{}   `json:"location"`
-	Ratings   int32         `json:"ratings"`
+	CourierID uuid.UUID     `json:"courier_id"`
+	TripID    uuid.NullUUID `json:"trip_id"`
+	Reason    string        `json:"reason"`
 	Points    int32         `json:"points"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type CourierStatusTransition struct {
+	ID         uuid.UUID     `json:"id"`
+	CourierID  uuid.UUID     `json:"courier_id"`
+	FromStatus string        `json:"from_status"`
+	ToStatus   string        `json:"to_status"`
+	Reason     string        `json:"reason"`
+	TripID     uuid.NullUUID `json:"trip_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type Fleet struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	OwnerID    uuid.UUID `json:"owner_id"`
+	RiderShare int32     `json:"rider_share"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type FleetInvitation struct {
+	ID          uuid.UUID     `json:"id"`
+	FleetID     uuid.UUID     `json:"fleet_id"`
+	CourierID   uuid.UUID     `json:"courier_id"`
+	InvitedBy   uuid.NullUUID `json:"invited_by"`
+	Status      string        `json:"status"`
+	RespondedAt sql.NullTime  `json:"responded_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type FleetManager struct {
+	FleetID   uuid.UUID `json:"fleet_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID        uuid.UUID     `json:"id"`
 	UserID    uuid.NullUUID `json:"user_id"`
-	ProductID uuid.NullUUID `json:"product_id"`
-	TripID    uuid.NullUUID `json:"trip_id"`
+	Kind      string        `json:"kind"`
+	Balance   int32         `json:"balance"`
 	CreatedAt time.Time     `json:"created_at"`
 	UpdatedAt time.Time     `json:"updated_at"`
 }
 
+type LedgerEntry struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	AccountID     uuid.UUID `json:"account_id"`
+	Amount        int32     `json:"amount"`
+	Balance       int32     `json:"balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LedgerTransaction struct {
+	ID          uuid.UUID     `json:"id"`
+	Kind        string        `json:"kind"`
+	Reference   string        `json:"reference"`
+	TripID      uuid.NullUUID `json:"trip_id"`
+	PaymentID   uuid.NullUUID `json:"payment_id"`
+	Description string        `json:"description"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type Payment struct {
+	ID                uuid.UUID      `json:"id"`
+	TripID            uuid.NullUUID  `json:"trip_id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	Provider          string         `json:"provider"`
+	Amount            int32          `json:"amount"`
+	Phone             sql.NullString `json:"phone"`
+	Status            string         `json:"status"`
+	MerchantRequestID sql.NullString `json:"merchant_request_id"`
+	CheckoutRequestID sql.NullString `json:"checkout_request_id"`
+	Receipt           sql.NullString `json:"receipt"`
+	ResultCode        sql.NullInt32  `json:"result_code"`
+	ResultDesc        sql.NullString `json:"result_desc"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	Reference         sql.NullString `json:"reference"`
+	AuthorizationUrl  sql.NullString `json:"authorization_url"`
+	RefundedAmount    int32          `json:"refunded_amount"`
+	Purpose           string         `json:"purpose"`
+	CardID            uuid.NullUUID  `json:"card_id"`
+}
+
+type PaymentCard struct {
+	ID                uuid.UUID      `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	Email             string         `json:"email"`
+	AuthorizationCode string         `json:"authorization_code"`
+	Signature         string         `json:"signature"`
+	CardType          string         `json:"card_type"`
+	Last4             string         `json:"last4"`
+	ExpMonth          string         `json:"exp_month"`
+	ExpYear           string         `json:"exp_year"`
+	Bank              sql.NullString `json:"bank"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+type PaymentEvent struct {
+	ID        uuid.UUID `json:"id"`
+	Provider  string    `json:"provider"`
+	EventKey  string    `json:"event_key"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Payout struct {
+	ID                       uuid.UUID      `json:"id"`
+	CourierID                uuid.NullUUID  `json:"courier_id"`
+	UserID                   uuid.UUID      `json:"user_id"`
+	Amount                   int32          `json:"amount"`
+	Phone                    string         `json:"phone"`
+	Status                   string         `json:"status"`
+	Attempts                 int32          `json:"attempts"`
+	NextAttemptAt            time.Time      `json:"next_attempt_at"`
+	OriginatorConversationID sql.NullString `json:"originator_conversation_id"`
+	ConversationID           sql.NullString `json:"conversation_id"`
+	Receipt                  sql.NullString `json:"receipt"`
+	ResultCode               sql.NullInt32  `json:"result_code"`
+	ResultDesc               sql.NullString `json:"result_desc"`
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+	StatusConversationID     sql.NullString `json:"status_conversation_id"`
+}
+
+type PayoutEntry struct {
+	PayoutID uuid.UUID `json:"payout_id"`
+	EntryID  uuid.UUID `json:"entry_id"`
+}
+
+type PricingSchedule struct {
+	ID         uuid.UUID     `json:"id"`
+	Name       string        `json:"name"`
+	Kind       string        `json:"kind"`
+	Multiplier float64       `json:"multiplier"`
+	StartTime  sql.NullTime  `json:"start_time"`
+	EndTime    sql.NullTime  `json:"end_time"`
+	ProductID  uuid.NullUUID `json:"product_id"`
+	ZoneID     uuid.NullUUID `json:"zone_id"`
+	ValidFrom  time.Time     `json:"valid_from"`
+	ValidUntil sql.NullTime  `json:"valid_until"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
 type Product struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
@@ -36,6 +220,83 @@ type Product struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type ProductDocument struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Type      string    `json:"type"`
+	Position  int32     `json:"position"`
+}
+
+type Promotion struct {
+	ID            uuid.UUID     `json:"id"`
+	Code          string        `json:"code"`
+	Description   string        `json:"description"`
+	DiscountType  string        `json:"discount_type"`
+	DiscountValue int32         `json:"discount_value"`
+	MaxDiscount   sql.NullInt32 `json:"max_discount"`
+	PerUserLimit  int32         `json:"per_user_limit"`
+	Budget        sql.NullInt32 `json:"budget"`
+	BudgetUsed    int32         `json:"budget_used"`
+	FirstTripOnly bool          `json:"first_trip_only"`
+	FundedBy      string        `json:"funded_by"`
+	ProductID     uuid.NullUUID `json:"product_id"`
+	ZoneID        uuid.NullUUID `json:"zone_id"`
+	StartsAt      time.Time     `json:"starts_at"`
+	EndsAt        sql.NullTime  `json:"ends_at"`
+	Active        bool          `json:"active"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type PromotionRedemption struct {
+	ID          uuid.UUID `json:"id"`
+	PromotionID uuid.UUID `json:"promotion_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	TripID      uuid.UUID `json:"trip_id"`
+	Amount      int32     `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PublicHoliday struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Day       time.Time `json:"day"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Quest struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	TargetTrips int32          `json:"target_trips"`
+	Reward      int32          `json:"reward"`
+	ZoneID      uuid.NullUUID  `json:"zone_id"`
+	ProductID   uuid.NullUUID  `json:"product_id"`
+	Tier        sql.NullString `json:"tier"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+	Active      bool           `json:"active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type QuestProgress struct {
+	QuestID     uuid.UUID    `json:"quest_id"`
+	CourierID   uuid.UUID    `json:"courier_id"`
+	Trips       int32        `json:"trips"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type QuestTrip struct {
+	QuestID   uuid.UUID `json:"quest_id"`
+	TripID    uuid.UUID `json:"trip_id"`
+	CourierID uuid.UUID `json:"courier_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Recipient struct {
 	ID        uuid.UUID      `json:"id"`
 	Name      string         `json:"name"`
@@ -48,6 +309,18 @@ type Recipient struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 }
 
+type Referral struct {
+	ID         uuid.UUID      `json:"id"`
+	ReferrerID uuid.UUID      `json:"referrer_id"`
+	RefereeID  uuid.UUID      `json:"referee_id"`
+	Kind       string         `json:"kind"`
+	Status     string         `json:"status"`
+	DeviceID   sql.NullString `json:"device_id"`
+	RewardedAt sql.NullTime   `json:"rewarded_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
 type Session struct {
 	ID        uuid.UUID `json:"id"`
 	Ip        string    `json:"ip"`
@@ -57,37 +330,126 @@ type Session struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type Shift struct {
+	ID        uuid.UUID `json:"id"`
+	ZoneID    uuid.UUID `json:"zone_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int32     `json:"capacity"`
+	Guarantee int32     `json:"guarantee"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ShiftBooking struct {
+	ID            uuid.UUID    `json:"id"`
+	ShiftID       uuid.UUID    `json:"shift_id"`
+	CourierID     uuid.UUID    `json:"courier_id"`
+	Status        string       `json:"status"`
+	CheckedInAt   sql.NullTime `json:"checked_in_at"`
+	CancelledAt   sql.NullTime `json:"cancelled_at"`
+	Earned        int32        `json:"earned"`
+	GuaranteePaid int32        `json:"guarantee_paid"`
+	SettledAt     sql.NullTime `json:"settled_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
 type Trip struct {
-	ID              uuid.UUID     `json:"id"`
-	StartLocation   interface{}   `json:"start_location"`
-	EndLocation     interface{}   `json:"end_location"`
-	ConfirmedPickup interface{}   `json:"confirmed_pickup"`
-	CourierID       uuid.NullUUID `json:"courier_id"`
-	UserID          uuid.UUID     `json:"user_id"`
-	ProductID       uuid.UUID     `json:"product_id"`
-	Cost            int32         `json:"cost"`
-	Status          string        `json:"status"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID               uuid.UUID      `json:"id"`
+	StartLocation    interface{}    `json:"start_location"`
+	EndLocation      interface{}    `json:"end_location"`
+	ConfirmedPickup  interface{}    `json:"confirmed_pickup"`
+	CourierID        uuid.NullUUID  `json:"courier_id"`
+	UserID           uuid.UUID      `json:"user_id"`
+	ProductID        uuid.UUID      `json:"product_id"`
+	Cost             int32          `json:"cost"`
+	Status           string         `json:"status"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Distance         int32          `json:"distance"`
+	Duration         int32          `json:"duration"`
+	PromotionID      uuid.NullUUID  `json:"promotion_id"`
+	Discount         int32          `json:"discount"`
+	DiscountFundedBy sql.NullString `json:"discount_funded_by"`
+	CollectAmount    int32          `json:"collect_amount"`
+	CollectedAmount  sql.NullInt32  `json:"collected_amount"`
+	AssignedAt       sql.NullTime   `json:"assigned_at"`
+	ArrivedAt        sql.NullTime   `json:"arrived_at"`
+	PickedUpAt       sql.NullTime   `json:"picked_up_at"`
+	CompletedAt      sql.NullTime   `json:"completed_at"`
+	ActualDistance   sql.NullInt32  `json:"actual_distance"`
+	FleetID          uuid.NullUUID  `json:"fleet_id"`
+	VehicleID        uuid.NullUUID  `json:"vehicle_id"`
+}
+
+type TripRating struct {
+	ID        uuid.UUID      `json:"id"`
+	TripID    uuid.UUID      `json:"trip_id"`
+	Target    string         `json:"target"`
+	RaterID   uuid.UUID      `json:"rater_id"`
+	RateeID   uuid.UUID      `json:"ratee_id"`
+	Rating    int16          `json:"rating"`
+	Tags      []string       `json:"tags"`
+	Comment   sql.NullString `json:"comment"`
+	CreatedAt time.Time      `json:"created_at"`
 }
 
 type Upload struct {
-	ID           uuid.UUID     `json:"id"`
-	Type         string        `json:"type"`
-	Uri          string        `json:"uri"`
-	Verification string        `json:"verification"`
-	CourierID    uuid.NullUUID `json:"courier_id"`
-	UserID       uuid.NullUUID `json:"user_id"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
+	ID              uuid.UUID      `json:"id"`
+	Type            string         `json:"type"`
+	Uri             string         `json:"uri"`
+	Verification    string         `json:"verification"`
+	CourierID       uuid.NullUUID  `json:"courier_id"`
+	UserID          uuid.NullUUID  `json:"user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	RejectionReason sql.NullString `json:"rejection_reason"`
+	ReviewedBy      uuid.NullUUID  `json:"reviewed_by"`
+	ReviewedAt      sql.NullTime   `json:"reviewed_at"`
+	ExpiresAt       sql.NullTime   `json:"expires_at"`
+	ExpiryWarnedAt  sql.NullTime   `json:"expiry_warned_at"`
 }
 
 type User struct {
-	ID         uuid.UUID `json:"id"`
-	FirstName  string    `json:"first_name"`
-	LastName   string    `json:"last_name"`
-	Phone      string    `json:"phone"`
-	Onboarding bool      `json:"onboarding"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID           uuid.UUID       `json:"id"`
+	FirstName    string          `json:"first_name"`
+	LastName     string          `json:"last_name"`
+	Phone        string          `json:"phone"`
+	Onboarding   bool            `json:"onboarding"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	ReferralCode sql.NullString  `json:"referral_code"`
+	DeviceID     sql.NullString  `json:"device_id"`
+	IsAdmin      bool            `json:"is_admin"`
+	Rating       sql.NullFloat64 `json:"rating"`
+	RatingCount  int32           `json:"rating_count"`
+}
+
+type Vehicle struct {
+	ID              uuid.UUID      `json:"id"`
+	CourierID       uuid.UUID      `json:"courier_id"`
+	ProductID       uuid.UUID      `json:"product_id"`
+	Type            string         `json:"type"`
+	Plate           string         `json:"plate"`
+	Make            string         `json:"make"`
+	Colour          string         `json:"colour"`
+	Capacity        int32          `json:"capacity"`
+	Status          string         `json:"status"`
+	RejectionReason sql.NullString `json:"rejection_reason"`
+	ReviewedBy      uuid.NullUUID  `json:"reviewed_by"`
+	ReviewedAt      sql.NullTime   `json:"reviewed_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	FleetID         uuid.NullUUID  `json:"fleet_id"`
+	FleetApprovedBy uuid.NullUUID  `json:"fleet_approved_by"`
+	FleetApprovedAt sql.NullTime   `json:"fleet_approved_at"`
+}
+
+type Zone struct {
+	ID        uuid.UUID   `json:"id"`
+	Name      string      `json:"name"`
+	Boundary  interface{} `json:"boundary"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
 }
@@ -7,47 +7,208 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddCourierPoints(ctx context.Context, arg AddCourierPointsParams) (int32, error)
+	AddFleetManager(ctx context.Context, arg AddFleetManagerParams) error
+	AddQuestProgress(ctx context.Context, arg AddQuestProgressParams) (QuestProgress, error)
+	ApproveFleetVehicle(ctx context.Context, arg ApproveFleetVehicleParams) (Vehicle, error)
 	AssignCourierToTrip(ctx context.Context, arg AssignCourierToTripParams) (Courier, error)
 	AssignTripToCourier(ctx context.Context, arg AssignTripToCourierParams) (Trip, error)
+	CancelShiftBooking(ctx context.Context, arg CancelShiftBookingParams) (ShiftBooking, error)
+	CheckInShiftBooking(ctx context.Context, arg CheckInShiftBookingParams) (ShiftBooking, error)
+	ClearCourierVehicle(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	ClearTripCourier(ctx context.Context, arg ClearTripCourierParams) (uuid.UUID, error)
+	CompleteCourierTrip(ctx context.Context, arg CompleteCourierTripParams) (Trip, error)
+	CountCourierCompletedTrips(ctx context.Context, courierID uuid.NullUUID) (int64, error)
+	CountExpiredCourierDocuments(ctx context.Context, arg CountExpiredCourierDocumentsParams) (int64, error)
+	CountMissingCourierDocuments(ctx context.Context, arg CountMissingCourierDocumentsParams) (int64, error)
+	CountOverlappingShiftBookings(ctx context.Context, arg CountOverlappingShiftBookingsParams) (int64, error)
+	CountShiftBookings(ctx context.Context, shiftID uuid.UUID) (int64, error)
+	CountUserCompletedTrips(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUserOtherTrips(ctx context.Context, arg CountUserOtherTripsParams) (int64, error)
+	CountUserPromotionRedemptions(ctx context.Context, arg CountUserPromotionRedemptionsParams) (int64, error)
 	CreateCourier(ctx context.Context, userID uuid.NullUUID) (Courier, error)
+	// Points buffered offline belong to the trip the courier was on when they
+	// were recorded, not when they were uploaded. Returns the completed trips
+	// that got points after the fact.
+	CreateCourierBreadcrumbs(ctx context.Context, points json.RawMessage) ([]uuid.NullUUID, error)
+	CreateCourierLocationsPartition(ctx context.Context, month time.Time) error
+	CreateCourierPoints(ctx context.Context, arg CreateCourierPointsParams) (CourierPoint, error)
+	CreateCourierStatusTransition(ctx context.Context, arg CreateCourierStatusTransitionParams) error
 	CreateCourierUpload(ctx context.Context, arg CreateCourierUploadParams) (Upload, error)
+	CreateFleet(ctx context.Context, arg CreateFleetParams) (Fleet, error)
+	CreateFleetInvitation(ctx context.Context, arg CreateFleetInvitationParams) (FleetInvitation, error)
+	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error)
+	CreateLedgerTransaction(ctx context.Context, arg CreateLedgerTransactionParams) (LedgerTransaction, error)
+	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
+	CreatePaymentEvent(ctx context.Context, arg CreatePaymentEventParams) (PaymentEvent, error)
+	CreatePayout(ctx context.Context, arg CreatePayoutParams) (Payout, error)
+	CreatePayoutEntries(ctx context.Context, arg CreatePayoutEntriesParams) error
+	CreatePromotionRedemption(ctx context.Context, arg CreatePromotionRedemptionParams) (PromotionRedemption, error)
+	CreateQuest(ctx context.Context, arg CreateQuestParams) (Quest, error)
+	CreateQuestTrip(ctx context.Context, arg CreateQuestTripParams) (uuid.UUID, error)
 	CreateRecipient(ctx context.Context, arg CreateRecipientParams) (Recipient, error)
+	CreateReferral(ctx context.Context, arg CreateReferralParams) (Referral, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateShift(ctx context.Context, arg CreateShiftParams) (Shift, error)
+	CreateShiftBooking(ctx context.Context, arg CreateShiftBookingParams) (ShiftBooking, error)
 	CreateTrip(ctx context.Context, arg CreateTripParams) (Trip, error)
 	CreateTripCost(ctx context.Context, arg CreateTripCostParams) (Trip, error)
+	CreateTripRating(ctx context.Context, arg CreateTripRatingParams) (TripRating, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserUpload(ctx context.Context, arg CreateUserUploadParams) (Upload, error)
+	CreateVehicle(ctx context.Context, arg CreateVehicleParams) (Vehicle, error)
+	DeletePayoutEntries(ctx context.Context, payoutID uuid.UUID) error
+	EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) error
+	ExpireCourierUploads(ctx context.Context, arg ExpireCourierUploadsParams) ([]Upload, error)
+	ExpireStaleTripPayments(ctx context.Context, arg ExpireStaleTripPaymentsParams) error
+	// Candidates and their distance from pickup come from the live location index
+	// Couriers checked in on a running shift go first
+	// Better rated and higher tier couriers reach further, unrated ones count as 4 stars
 	FindAvailableCourier(ctx context.Context, arg FindAvailableCourierParams) (FindAvailableCourierRow, error)
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	FlushCourierLocations(ctx context.Context, arg FlushCourierLocationsParams) (int64, error)
+	GetActivePricingSchedules(ctx context.Context, arg GetActivePricingSchedulesParams) ([]GetActivePricingSchedulesRow, error)
 	GetCourierAssignedTrip(ctx context.Context, id uuid.UUID) (Courier, error)
 	GetCourierAvatar(ctx context.Context, courierID uuid.NullUUID) (GetCourierAvatarRow, error)
 	GetCourierByID(ctx context.Context, id uuid.UUID) (GetCourierByIDRow, error)
+	GetCourierByPhone(ctx context.Context, phone string) (GetCourierByPhoneRow, error)
 	GetCourierByUserID(ctx context.Context, userID uuid.NullUUID) (GetCourierByUserIDRow, error)
+	GetCourierCompletedTrips(ctx context.Context, courierID uuid.NullUUID) (int64, error)
+	GetCourierEarningsBetween(ctx context.Context, arg GetCourierEarningsBetweenParams) (int32, error)
+	GetCourierEligibility(ctx context.Context, userID uuid.NullUUID) (GetCourierEligibilityRow, error)
+	GetCourierFleetInvitationForUpdate(ctx context.Context, arg GetCourierFleetInvitationForUpdateParams) (FleetInvitation, error)
+	GetCourierFleetInvitations(ctx context.Context, userID uuid.NullUUID) ([]GetCourierFleetInvitationsRow, error)
 	GetCourierLocation(ctx context.Context, id uuid.UUID) (interface{}, error)
-	GetCourierNearPickupPoint(ctx context.Context, arg GetCourierNearPickupPointParams) ([]GetCourierNearPickupPointRow, error)
+	GetCourierNearPickupPoint(ctx context.Context, ids []uuid.UUID) ([]GetCourierNearPickupPointRow, error)
+	GetCourierOnboarding(ctx context.Context, id uuid.UUID) (GetCourierOnboardingRow, error)
+	GetCourierOnlineSecondsBetween(ctx context.Context, arg GetCourierOnlineSecondsBetweenParams) (int64, error)
+	GetCourierPoints(ctx context.Context, arg GetCourierPointsParams) ([]CourierPoint, error)
+	GetCourierQuests(ctx context.Context, arg GetCourierQuestsParams) ([]GetCourierQuestsRow, error)
+	GetCourierShiftBookings(ctx context.Context, arg GetCourierShiftBookingsParams) ([]GetCourierShiftBookingsRow, error)
 	GetCourierStatus(ctx context.Context, userID uuid.NullUUID) (string, error)
-	GetCourierTrip(ctx context.Context, courierID uuid.NullUUID) (Trip, error)
+	GetCourierStatusForUpdate(ctx context.Context, id uuid.UUID) (string, error)
+	GetCourierTrip(ctx context.Context, arg GetCourierTripParams) (Trip, error)
+	GetCourierTripID(ctx context.Context, id uuid.UUID) (uuid.NullUUID, error)
 	GetCourierUpload(ctx context.Context, arg GetCourierUploadParams) (Upload, error)
 	GetCourierUploads(ctx context.Context, courierID uuid.NullUUID) ([]Upload, error)
+	GetCourierVehicles(ctx context.Context, courierID uuid.UUID) ([]Vehicle, error)
+	GetCouriersHoldingCash(ctx context.Context, minimum int32) ([]GetCouriersHoldingCashRow, error)
+	GetDuePayoutVerifications(ctx context.Context) ([]Payout, error)
+	GetDuePayouts(ctx context.Context) ([]uuid.UUID, error)
+	GetEligibleQuests(ctx context.Context, arg GetEligibleQuestsParams) ([]Quest, error)
+	GetEndedShiftBookings(ctx context.Context, endsAt time.Time) ([]uuid.UUID, error)
+	GetExpiringCourierUploads(ctx context.Context, arg GetExpiringCourierUploadsParams) ([]Upload, error)
+	GetFailedPayouts(ctx context.Context, arg GetFailedPayoutsParams) ([]Payout, error)
+	GetFleet(ctx context.Context, id uuid.UUID) (Fleet, error)
+	GetFleetPendingVehicles(ctx context.Context, fleetID uuid.NullUUID) ([]GetFleetPendingVehiclesRow, error)
+	GetFleetRiders(ctx context.Context, fleetID uuid.NullUUID) ([]GetFleetRidersRow, error)
+	GetFleetTrips(ctx context.Context, arg GetFleetTripsParams) ([]GetFleetTripsRow, error)
+	GetManagedFleets(ctx context.Context, userID uuid.UUID) ([]Fleet, error)
 	GetNearbyAvailableCourierProducts(ctx context.Context, arg GetNearbyAvailableCourierProductsParams) ([]GetNearbyAvailableCourierProductsRow, error)
+	GetPaidTripPaymentForUpdate(ctx context.Context, tripID uuid.NullUUID) (Payment, error)
+	GetPaymentByCheckoutIDForUpdate(ctx context.Context, checkoutRequestID sql.NullString) (Payment, error)
+	GetPaymentByReferenceForUpdate(ctx context.Context, reference sql.NullString) (Payment, error)
+	GetPayoutByOriginatorIDForUpdate(ctx context.Context, originatorConversationID sql.NullString) (Payout, error)
+	GetPayoutByStatusConversationForUpdate(ctx context.Context, statusConversationID sql.NullString) (Payout, error)
+	// Couriers and fleet owners alike are paid their earnings
+	GetPayoutCandidates(ctx context.Context, minimum int32) ([]GetPayoutCandidatesRow, error)
+	GetPayoutForUpdate(ctx context.Context, id uuid.UUID) (Payout, error)
+	GetPendingCourierUploads(ctx context.Context, arg GetPendingCourierUploadsParams) ([]Upload, error)
+	GetPendingReferral(ctx context.Context, arg GetPendingReferralParams) (Referral, error)
+	GetPendingVehicles(ctx context.Context, arg GetPendingVehiclesParams) ([]GetPendingVehiclesRow, error)
+	GetPlatformLedgerAccount(ctx context.Context, kind string) (LedgerAccount, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (GetProductByIDRow, error)
+	GetProductDocuments(ctx context.Context, productID uuid.UUID) ([]string, error)
+	GetProducts(ctx context.Context) ([]Product, error)
+	GetPromotionByCode(ctx context.Context, code string) (Promotion, error)
+	GetPromotionForUpdate(ctx context.Context, id uuid.UUID) (Promotion, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetShiftBooking(ctx context.Context, id uuid.UUID) (GetShiftBookingRow, error)
+	GetShiftBookingForUpdate(ctx context.Context, id uuid.UUID) (ShiftBooking, error)
+	GetShiftForUpdate(ctx context.Context, id uuid.UUID) (Shift, error)
 	GetTrip(ctx context.Context, id uuid.UUID) (GetTripRow, error)
+	GetTripDiscountForUpdate(ctx context.Context, id uuid.UUID) (GetTripDiscountForUpdateRow, error)
+	GetTripFleet(ctx context.Context, id uuid.UUID) (GetTripFleetRow, error)
+	GetTripHeldAmount(ctx context.Context, arg GetTripHeldAmountParams) (int32, error)
+	GetTripPath(ctx context.Context, arg GetTripPathParams) (GetTripPathRow, error)
+	GetTripPayment(ctx context.Context, tripID uuid.NullUUID) (Payment, error)
+	GetTripRatings(ctx context.Context, tripID uuid.UUID) ([]TripRating, error)
 	GetTripRecipient(ctx context.Context, tripID uuid.NullUUID) (Recipient, error)
+	GetTripTimeline(ctx context.Context, id uuid.UUID) (GetTripTimelineRow, error)
+	// Earnings settled before the cutoff and every deduction so far, so
+	// deductions after the cutoff come out of this payout and no other
+	GetUnpaidEarningsEntries(ctx context.Context, arg GetUnpaidEarningsEntriesParams) ([]GetUnpaidEarningsEntriesRow, error)
+	GetUpcomingShifts(ctx context.Context, arg GetUpcomingShiftsParams) ([]GetUpcomingShiftsRow, error)
+	GetUploadForUpdate(ctx context.Context, id uuid.UUID) (Upload, error)
+	GetUserByReferralCode(ctx context.Context, referralCode sql.NullString) (User, error)
+	GetUserLedgerAccount(ctx context.Context, arg GetUserLedgerAccountParams) (LedgerAccount, error)
+	GetUserLedgerAccounts(ctx context.Context, userID uuid.NullUUID) ([]LedgerAccount, error)
+	GetUserLedgerEntriesBetween(ctx context.Context, arg GetUserLedgerEntriesBetweenParams) ([]GetUserLedgerEntriesBetweenRow, error)
+	GetUserLedgerStatement(ctx context.Context, arg GetUserLedgerStatementParams) ([]GetUserLedgerStatementRow, error)
+	GetUserPaymentCard(ctx context.Context, arg GetUserPaymentCardParams) (PaymentCard, error)
+	GetUserPaymentCards(ctx context.Context, userID uuid.UUID) ([]PaymentCard, error)
+	GetUserReferral(ctx context.Context, refereeID uuid.UUID) (Referral, error)
 	GetUserUpload(ctx context.Context, arg GetUserUploadParams) (Upload, error)
+	GetVehicle(ctx context.Context, id uuid.UUID) (Vehicle, error)
+	GetVehicleForUpdate(ctx context.Context, id uuid.UUID) (Vehicle, error)
+	GetZoneByPoint(ctx context.Context, point interface{}) (GetZoneByPointRow, error)
+	GetZoneName(ctx context.Context, id uuid.UUID) (string, error)
+	HasOpenTripPayment(ctx context.Context, arg HasOpenTripPaymentParams) (bool, error)
 	IsCourier(ctx context.Context, userID uuid.NullUUID) (sql.NullBool, error)
+	IsCourierInZone(ctx context.Context, arg IsCourierInZoneParams) (bool, error)
+	IsDeviceReferred(ctx context.Context, deviceID sql.NullString) (bool, error)
+	IsFleetManager(ctx context.Context, arg IsFleetManagerParams) (bool, error)
+	IsPlateRegistered(ctx context.Context, plate string) (bool, error)
+	IsPublicHoliday(ctx context.Context, day time.Time) (bool, error)
+	IsUserAdmin(ctx context.Context, id uuid.UUID) (bool, error)
 	IsUserOnboarding(ctx context.Context, id uuid.UUID) (bool, error)
-	SetCourierStatus(ctx context.Context, arg SetCourierStatusParams) (Courier, error)
+	LockLedgerAccounts(ctx context.Context, ids []uuid.UUID) ([]LedgerAccount, error)
+	OwnsFleet(ctx context.Context, ownerID uuid.UUID) (bool, error)
+	RecordTripDistances(ctx context.Context, arg RecordTripDistancesParams) (int64, error)
+	RefundPayment(ctx context.Context, arg RefundPaymentParams) (Payment, error)
+	RespondFleetInvitation(ctx context.Context, arg RespondFleetInvitationParams) (FleetInvitation, error)
+	ReviewUpload(ctx context.Context, arg ReviewUploadParams) (Upload, error)
+	ReviewVehicle(ctx context.Context, arg ReviewVehicleParams) (Vehicle, error)
+	RewardReferral(ctx context.Context, id uuid.UUID) (Referral, error)
+	SavePaymentCard(ctx context.Context, arg SavePaymentCardParams) (PaymentCard, error)
+	SetCourierFleet(ctx context.Context, arg SetCourierFleetParams) (uuid.UUID, error)
+	SetCourierRating(ctx context.Context, arg SetCourierRatingParams) error
+	SetCourierStatus(ctx context.Context, arg SetCourierStatusParams) (uuid.UUID, error)
+	SetCourierTier(ctx context.Context, arg SetCourierTierParams) error
+	SetCourierVehicle(ctx context.Context, arg SetCourierVehicleParams) (uuid.UUID, error)
+	SetCourierVerified(ctx context.Context, arg SetCourierVerifiedParams) (Courier, error)
+	SetFleetRiderShare(ctx context.Context, arg SetFleetRiderShareParams) (Fleet, error)
 	SetOnboardingStatus(ctx context.Context, arg SetOnboardingStatusParams) (User, error)
+	SetPaymentCard(ctx context.Context, arg SetPaymentCardParams) error
+	SetPaymentCheckout(ctx context.Context, arg SetPaymentCheckoutParams) (Payment, error)
+	SetPaymentReference(ctx context.Context, arg SetPaymentReferenceParams) (Payment, error)
+	SetPaymentResult(ctx context.Context, arg SetPaymentResultParams) (Payment, error)
+	SetPaymentStatus(ctx context.Context, arg SetPaymentStatusParams) (Payment, error)
+	SetPayoutConversation(ctx context.Context, arg SetPayoutConversationParams) error
+	SetPayoutResult(ctx context.Context, arg SetPayoutResultParams) (Payout, error)
+	SetPayoutRetry(ctx context.Context, arg SetPayoutRetryParams) (Payout, error)
+	SetPayoutStatusConversation(ctx context.Context, arg SetPayoutStatusConversationParams) error
+	SetPayoutSubmitted(ctx context.Context, arg SetPayoutSubmittedParams) (Payout, error)
+	SetPayoutVerifying(ctx context.Context, arg SetPayoutVerifyingParams) (Payout, error)
+	SetQuestCompleted(ctx context.Context, arg SetQuestCompletedParams) (QuestProgress, error)
+	SetStaleCouriersOffline(ctx context.Context, staleBefore sql.NullTime) ([]SetStaleCouriersOfflineRow, error)
+	SetTripCollectedAmount(ctx context.Context, arg SetTripCollectedAmountParams) (uuid.UUID, error)
+	SetTripDiscount(ctx context.Context, arg SetTripDiscountParams) (Trip, error)
 	SetTripStatus(ctx context.Context, arg SetTripStatusParams) (Trip, error)
-	TrackCourierLocation(ctx context.Context, arg TrackCourierLocationParams) (Courier, error)
+	SetUploadExpiryWarned(ctx context.Context, arg SetUploadExpiryWarnedParams) error
+	SetUserRating(ctx context.Context, arg SetUserRatingParams) error
+	SettleShiftBooking(ctx context.Context, arg SettleShiftBookingParams) (ShiftBooking, error)
+	SpendPromotionBudget(ctx context.Context, arg SpendPromotionBudgetParams) (Promotion, error)
 	UnassignCourierTrip(ctx context.Context, id uuid.UUID) (Courier, error)
+	UpdateLedgerAccountBalance(ctx context.Context, arg UpdateLedgerAccountBalanceParams) (int32, error)
 	UpdateUpload(ctx context.Context, arg UpdateUploadParams) (Upload, error)
 	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error)
 }
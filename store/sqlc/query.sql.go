@@ -8,16 +8,123 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const addCourierPoints = `-- name: AddCourierPoints :one
+UPDATE couriers
+SET points = GREATEST(points + $1, 0), updated_at = NOW()
+WHERE id = $2
+RETURNING points
+`
+
+type AddCourierPointsParams struct {
+	Points int32     `json:"points"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) AddCourierPoints(ctx context.Context, arg AddCourierPointsParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, addCourierPoints, arg.Points, arg.ID)
+	var points int32
+	err := row.Scan(&points)
+	return points, err
+}
+
+const addFleetManager = `-- name: AddFleetManager :exec
+INSERT INTO fleet_managers (
+  fleet_id, user_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type AddFleetManagerParams struct {
+	FleetID uuid.UUID `json:"fleet_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) AddFleetManager(ctx context.Context, arg AddFleetManagerParams) error {
+	_, err := q.db.ExecContext(ctx, addFleetManager, arg.FleetID, arg.UserID)
+	return err
+}
+
+const addQuestProgress = `-- name: AddQuestProgress :one
+INSERT INTO quest_progress (
+  quest_id, courier_id, trips
+) VALUES (
+  $1, $2, 1
+)
+ON CONFLICT (quest_id, courier_id) DO UPDATE
+SET trips = quest_progress.trips + 1, updated_at = NOW()
+RETURNING quest_id, courier_id, trips, completed_at, created_at, updated_at
+`
+
+type AddQuestProgressParams struct {
+	QuestID   uuid.UUID `json:"quest_id"`
+	CourierID uuid.UUID `json:"courier_id"`
+}
+
+func (q *Queries) AddQuestProgress(ctx context.Context, arg AddQuestProgressParams) (QuestProgress, error) {
+	row := q.db.QueryRowContext(ctx, addQuestProgress, arg.QuestID, arg.CourierID)
+	var i QuestProgress
+	err := row.Scan(
+		&i.QuestID,
+		&i.CourierID,
+		&i.Trips,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const approveFleetVehicle = `-- name: ApproveFleetVehicle :one
+UPDATE vehicles
+SET fleet_approved_by = $1, fleet_approved_at = NOW(), updated_at = NOW()
+WHERE id = $2
+RETURNING id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at
+`
+
+type ApproveFleetVehicleParams struct {
+	FleetApprovedBy uuid.NullUUID `json:"fleet_approved_by"`
+	ID              uuid.UUID     `json:"id"`
+}
+
+func (q *Queries) ApproveFleetVehicle(ctx context.Context, arg ApproveFleetVehicleParams) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, approveFleetVehicle, arg.FleetApprovedBy, arg.ID)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.ProductID,
+		&i.Type,
+		&i.Plate,
+		&i.Make,
+		&i.Colour,
+		&i.Capacity,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FleetID,
+		&i.FleetApprovedBy,
+		&i.FleetApprovedAt,
+	)
+	return i, err
+}
+
 const assignCourierToTrip = `-- name: AssignCourierToTrip :one
 UPDATE couriers
 SET trip_id = $1
 WHERE id = $2
-RETURNING id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at
+RETURNING id, verified, status, location, points, user_id, product_id, trip_id, created_at, updated_at, rating, rating_count, tier, vehicle_id, location_updated_at, fleet_id
 `
 
 type AssignCourierToTripParams struct {
@@ -33,31 +140,176 @@ func (q *Queries) AssignCourierToTrip(ctx context.Context, arg AssignCourierToTr
 		&i.Verified,
 		&i.Status,
 		&i.Location,
-		&i.Ratings,
 		&i.Points,
 		&i.UserID,
 		&i.ProductID,
 		&i.TripID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rating,
+		&i.RatingCount,
+		&i.Tier,
+		&i.VehicleID,
+		&i.LocationUpdatedAt,
+		&i.FleetID,
 	)
 	return i, err
 }
 
 const assignTripToCourier = `-- name: AssignTripToCourier :one
-UPDATE trips
-SET courier_id = $1
-WHERE id = $2
-RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at
+UPDATE trips t
+SET courier_id = c.id, fleet_id = c.fleet_id, vehicle_id = c.vehicle_id
+FROM couriers c
+WHERE t.id = $1 AND c.id = $2
+RETURNING t.id, t.start_location, t.end_location, t.confirmed_pickup, t.courier_id, t.user_id, t.product_id, t.cost, t.status, t.created_at, t.updated_at, t.distance, t.duration, t.promotion_id, t.discount, t.discount_funded_by, t.collect_amount, t.collected_amount, t.assigned_at, t.arrived_at, t.picked_up_at, t.completed_at, t.actual_distance, t.fleet_id, t.vehicle_id
 `
 
 type AssignTripToCourierParams struct {
+	ID        uuid.UUID `json:"id"`
+	CourierID uuid.UUID `json:"courier_id"`
+}
+
+func (q *Queries) AssignTripToCourier(ctx context.Context, arg AssignTripToCourierParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, assignTripToCourier, arg.ID, arg.CourierID)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.StartLocation,
+		&i.EndLocation,
+		&i.ConfirmedPickup,
+		&i.CourierID,
+		&i.UserID,
+		&i.ProductID,
+		&i.Cost,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
+	)
+	return i, err
+}
+
+const cancelShiftBooking = `-- name: CancelShiftBooking :one
+UPDATE shift_bookings
+SET status = 'CANCELLED', cancelled_at = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id, shift_id, courier_id, status, checked_in_at, cancelled_at, earned, guarantee_paid, settled_at, created_at, updated_at
+`
+
+type CancelShiftBookingParams struct {
+	CancelledAt sql.NullTime `json:"cancelled_at"`
+	ID          uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) CancelShiftBooking(ctx context.Context, arg CancelShiftBookingParams) (ShiftBooking, error) {
+	row := q.db.QueryRowContext(ctx, cancelShiftBooking, arg.CancelledAt, arg.ID)
+	var i ShiftBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ShiftID,
+		&i.CourierID,
+		&i.Status,
+		&i.CheckedInAt,
+		&i.CancelledAt,
+		&i.Earned,
+		&i.GuaranteePaid,
+		&i.SettledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const checkInShiftBooking = `-- name: CheckInShiftBooking :one
+UPDATE shift_bookings
+SET status = 'CHECKED_IN', checked_in_at = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id, shift_id, courier_id, status, checked_in_at, cancelled_at, earned, guarantee_paid, settled_at, created_at, updated_at
+`
+
+type CheckInShiftBookingParams struct {
+	CheckedInAt sql.NullTime `json:"checked_in_at"`
+	ID          uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) CheckInShiftBooking(ctx context.Context, arg CheckInShiftBookingParams) (ShiftBooking, error) {
+	row := q.db.QueryRowContext(ctx, checkInShiftBooking, arg.CheckedInAt, arg.ID)
+	var i ShiftBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ShiftID,
+		&i.CourierID,
+		&i.Status,
+		&i.CheckedInAt,
+		&i.CancelledAt,
+		&i.Earned,
+		&i.GuaranteePaid,
+		&i.SettledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const clearCourierVehicle = `-- name: ClearCourierVehicle :one
+UPDATE couriers
+SET vehicle_id = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING id
+`
+
+func (q *Queries) ClearCourierVehicle(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, clearCourierVehicle, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const clearTripCourier = `-- name: ClearTripCourier :one
+UPDATE trips
+SET courier_id = NULL, fleet_id = NULL, vehicle_id = NULL, assigned_at = NULL, arrived_at = NULL
+WHERE id = $1 AND courier_id = $2
+RETURNING id
+`
+
+type ClearTripCourierParams struct {
+	ID        uuid.UUID     `json:"id"`
 	CourierID uuid.NullUUID `json:"courier_id"`
+}
+
+func (q *Queries) ClearTripCourier(ctx context.Context, arg ClearTripCourierParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, clearTripCourier, arg.ID, arg.CourierID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const completeCourierTrip = `-- name: CompleteCourierTrip :one
+UPDATE trips
+SET status = 'COMPLETE', completed_at = COALESCE(completed_at, NOW())
+WHERE id = $1 AND courier_id = $2 AND status = 'COURIER_EN_ROUTE'
+RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id
+`
+
+type CompleteCourierTripParams struct {
 	ID        uuid.UUID     `json:"id"`
+	CourierID uuid.NullUUID `json:"courier_id"`
 }
 
-func (q *Queries) AssignTripToCourier(ctx context.Context, arg AssignTripToCourierParams) (Trip, error) {
-	row := q.db.QueryRowContext(ctx, assignTripToCourier, arg.CourierID, arg.ID)
+func (q *Queries) CompleteCourierTrip(ctx context.Context, arg CompleteCourierTripParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, completeCourierTrip, arg.ID, arg.CourierID)
 	var i Trip
 	err := row.Scan(
 		&i.ID,
@@ -71,17 +323,159 @@ func (q *Queries) AssignTripToCourier(ctx context.Context, arg AssignTripToCouri
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
 	)
 	return i, err
 }
 
+const countCourierCompletedTrips = `-- name: CountCourierCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE courier_id = $1 AND status = 'COMPLETE'
+`
+
+func (q *Queries) CountCourierCompletedTrips(ctx context.Context, courierID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCourierCompletedTrips, courierID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countExpiredCourierDocuments = `-- name: CountExpiredCourierDocuments :one
+SELECT COUNT(*) FROM uploads u
+JOIN couriers c ON c.id = u.courier_id
+JOIN product_documents d ON d.product_id = c.product_id AND d.type = u.type
+WHERE c.user_id = $1 AND u.expires_at <= $2
+`
+
+type CountExpiredCourierDocumentsParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Now    sql.NullTime  `json:"now"`
+}
+
+func (q *Queries) CountExpiredCourierDocuments(ctx context.Context, arg CountExpiredCourierDocumentsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExpiredCourierDocuments, arg.UserID, arg.Now)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMissingCourierDocuments = `-- name: CountMissingCourierDocuments :one
+SELECT COUNT(*) FROM product_documents d
+LEFT JOIN uploads u ON u.courier_id = $1 AND u.type = d.type AND u.verification = 'VERIFIED'
+WHERE d.product_id = $2 AND u.id IS NULL
+`
+
+type CountMissingCourierDocumentsParams struct {
+	CourierID uuid.NullUUID `json:"courier_id"`
+	ProductID uuid.UUID     `json:"product_id"`
+}
+
+func (q *Queries) CountMissingCourierDocuments(ctx context.Context, arg CountMissingCourierDocumentsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMissingCourierDocuments, arg.CourierID, arg.ProductID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOverlappingShiftBookings = `-- name: CountOverlappingShiftBookings :one
+SELECT COUNT(*) FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+WHERE b.courier_id = $1 AND b.status IN ('BOOKED', 'CHECKED_IN')
+AND s.starts_at < $2 AND s.ends_at > $3
+`
+
+type CountOverlappingShiftBookingsParams struct {
+	CourierID uuid.UUID `json:"courier_id"`
+	EndsAt    time.Time `json:"ends_at"`
+	StartsAt  time.Time `json:"starts_at"`
+}
+
+func (q *Queries) CountOverlappingShiftBookings(ctx context.Context, arg CountOverlappingShiftBookingsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOverlappingShiftBookings, arg.CourierID, arg.EndsAt, arg.StartsAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countShiftBookings = `-- name: CountShiftBookings :one
+SELECT COUNT(*) FROM shift_bookings
+WHERE shift_id = $1 AND status IN ('BOOKED', 'CHECKED_IN')
+`
+
+func (q *Queries) CountShiftBookings(ctx context.Context, shiftID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countShiftBookings, shiftID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserCompletedTrips = `-- name: CountUserCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = $1 AND status = 'COMPLETE'
+`
+
+func (q *Queries) CountUserCompletedTrips(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserCompletedTrips, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserOtherTrips = `-- name: CountUserOtherTrips :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = $1 AND id <> $2
+AND status NOT IN ('CANCELLED', 'COURIER_NOT_FOUND')
+`
+
+type CountUserOtherTripsParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	TripID uuid.UUID `json:"trip_id"`
+}
+
+func (q *Queries) CountUserOtherTrips(ctx context.Context, arg CountUserOtherTripsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserOtherTrips, arg.UserID, arg.TripID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserPromotionRedemptions = `-- name: CountUserPromotionRedemptions :one
+SELECT COUNT(*) FROM promotion_redemptions
+WHERE promotion_id = $1 AND user_id = $2
+`
+
+type CountUserPromotionRedemptionsParams struct {
+	PromotionID uuid.UUID `json:"promotion_id"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CountUserPromotionRedemptions(ctx context.Context, arg CountUserPromotionRedemptionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserPromotionRedemptions, arg.PromotionID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCourier = `-- name: CreateCourier :one
 INSERT INTO couriers (
   user_id
 ) VALUES (
   $1
 )
-RETURNING id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at
+RETURNING id, verified, status, location, points, user_id, product_id, trip_id, created_at, updated_at, rating, rating_count, tier, vehicle_id, location_updated_at, fleet_id
 `
 
 func (q *Queries) CreateCourier(ctx context.Context, userID uuid.NullUUID) (Courier, error) {
@@ -92,24 +486,152 @@ func (q *Queries) CreateCourier(ctx context.Context, userID uuid.NullUUID) (Cour
 		&i.Verified,
 		&i.Status,
 		&i.Location,
-		&i.Ratings,
 		&i.Points,
 		&i.UserID,
 		&i.ProductID,
 		&i.TripID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rating,
+		&i.RatingCount,
+		&i.Tier,
+		&i.VehicleID,
+		&i.LocationUpdatedAt,
+		&i.FleetID,
+	)
+	return i, err
+}
+
+const createCourierBreadcrumbs = `-- name: CreateCourierBreadcrumbs :many
+WITH inserted AS (
+  INSERT INTO courier_locations (
+    courier_id, trip_id, location, accuracy, speed, heading, recorded_at
+  )
+  SELECT p.courier_id, (
+    SELECT t.id FROM trips t
+    WHERE t.courier_id = p.courier_id AND t.assigned_at <= p.recorded_at
+    AND t.status NOT IN ('CANCELLED', 'COURIER_NOT_FOUND')
+    AND (t.completed_at IS NULL OR t.completed_at >= p.recorded_at)
+    ORDER BY t.assigned_at DESC
+    LIMIT 1
+  ), ST_SetSRID(ST_MakePoint(p.lng, p.lat), 4326)::geography, p.accuracy, p.speed, p.heading, p.recorded_at
+  FROM jsonb_to_recordset($1::jsonb) AS p(
+    courier_id UUID, lat FLOAT8, lng FLOAT8, accuracy REAL, speed REAL, heading REAL, recorded_at TIMESTAMP
+  )
+  ORDER BY p.recorded_at
+  ON CONFLICT DO NOTHING
+  RETURNING trip_id
+)
+SELECT DISTINCT i.trip_id FROM inserted i
+JOIN trips t ON t.id = i.trip_id
+WHERE t.status = 'COMPLETE'
+`
+
+// Points buffered offline belong to the trip the courier was on when they
+// were recorded, not when they were uploaded. Returns the completed trips
+// that got points after the fact.
+func (q *Queries) CreateCourierBreadcrumbs(ctx context.Context, points json.RawMessage) ([]uuid.NullUUID, error) {
+	rows, err := q.db.QueryContext(ctx, createCourierBreadcrumbs, points)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.NullUUID{}
+	for rows.Next() {
+		var trip_id uuid.NullUUID
+		if err := rows.Scan(&trip_id); err != nil {
+			return nil, err
+		}
+		items = append(items, trip_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createCourierLocationsPartition = `-- name: CreateCourierLocationsPartition :exec
+SELECT create_courier_locations_partition($1::DATE)
+`
+
+func (q *Queries) CreateCourierLocationsPartition(ctx context.Context, month time.Time) error {
+	_, err := q.db.ExecContext(ctx, createCourierLocationsPartition, month)
+	return err
+}
+
+const createCourierPoints = `-- name: CreateCourierPoints :one
+INSERT INTO courier_points (
+  courier_id, trip_id, reason, points
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (courier_id, trip_id, reason) DO NOTHING
+RETURNING id, courier_id, trip_id, reason, points, created_at
+`
+
+type CreateCourierPointsParams struct {
+	CourierID uuid.UUID     `json:"courier_id"`
+	TripID    uuid.NullUUID `json:"trip_id"`
+	Reason    string        `json:"reason"`
+	Points    int32         `json:"points"`
+}
+
+func (q *Queries) CreateCourierPoints(ctx context.Context, arg CreateCourierPointsParams) (CourierPoint, error) {
+	row := q.db.QueryRowContext(ctx, createCourierPoints,
+		arg.CourierID,
+		arg.TripID,
+		arg.Reason,
+		arg.Points,
+	)
+	var i CourierPoint
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.TripID,
+		&i.Reason,
+		&i.Points,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const createCourierStatusTransition = `-- name: CreateCourierStatusTransition :exec
+INSERT INTO courier_status_transitions (
+  courier_id, from_status, to_status, reason, trip_id
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type CreateCourierStatusTransitionParams struct {
+	CourierID  uuid.UUID     `json:"courier_id"`
+	FromStatus string        `json:"from_status"`
+	ToStatus   string        `json:"to_status"`
+	Reason     string        `json:"reason"`
+	TripID     uuid.NullUUID `json:"trip_id"`
+}
+
+func (q *Queries) CreateCourierStatusTransition(ctx context.Context, arg CreateCourierStatusTransitionParams) error {
+	_, err := q.db.ExecContext(ctx, createCourierStatusTransition,
+		arg.CourierID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.TripID,
+	)
+	return err
+}
+
 const createCourierUpload = `-- name: CreateCourierUpload :one
 INSERT INTO uploads (
   type, uri, courier_id, verification
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at
+RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at
 `
 
 type CreateCourierUploadParams struct {
@@ -136,466 +658,623 @@ func (q *Queries) CreateCourierUpload(ctx context.Context, arg CreateCourierUplo
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
 	)
 	return i, err
 }
 
-const createRecipient = `-- name: CreateRecipient :one
-INSERT INTO recipients (
-  name, building, unit, phone, trip_id, trip_note
+const createFleet = `-- name: CreateFleet :one
+INSERT INTO fleets (
+  name, owner_id, rider_share
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3
 )
-RETURNING id, name, building, unit, phone, trip_note, trip_id, created_at, updated_at
+RETURNING id, name, owner_id, rider_share, created_at, updated_at
 `
 
-type CreateRecipientParams struct {
-	Name     string         `json:"name"`
-	Building sql.NullString `json:"building"`
-	Unit     sql.NullString `json:"unit"`
-	Phone    string         `json:"phone"`
-	TripID   uuid.NullUUID  `json:"trip_id"`
-	TripNote string         `json:"trip_note"`
+type CreateFleetParams struct {
+	Name       string    `json:"name"`
+	OwnerID    uuid.UUID `json:"owner_id"`
+	RiderShare int32     `json:"rider_share"`
 }
 
-func (q *Queries) CreateRecipient(ctx context.Context, arg CreateRecipientParams) (Recipient, error) {
-	row := q.db.QueryRowContext(ctx, createRecipient,
-		arg.Name,
-		arg.Building,
-		arg.Unit,
-		arg.Phone,
-		arg.TripID,
-		arg.TripNote,
-	)
-	var i Recipient
+func (q *Queries) CreateFleet(ctx context.Context, arg CreateFleetParams) (Fleet, error) {
+	row := q.db.QueryRowContext(ctx, createFleet, arg.Name, arg.OwnerID, arg.RiderShare)
+	var i Fleet
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.Building,
-		&i.Unit,
-		&i.Phone,
-		&i.TripNote,
-		&i.TripID,
+		&i.OwnerID,
+		&i.RiderShare,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const createSession = `-- name: CreateSession :one
-INSERT INTO sessions (
-  id, ip, user_agent, phone
+const createFleetInvitation = `-- name: CreateFleetInvitation :one
+INSERT INTO fleet_invitations (
+  fleet_id, courier_id, invited_by
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3
 )
-RETURNING id, ip, user_agent, phone, created_at, updated_at
+ON CONFLICT (fleet_id, courier_id) WHERE status = 'PENDING' DO UPDATE
+SET invited_by = EXCLUDED.invited_by, updated_at = NOW()
+RETURNING id, fleet_id, courier_id, invited_by, status, responded_at, created_at, updated_at
 `
 
-type CreateSessionParams struct {
-	ID        uuid.UUID `json:"id"`
-	Ip        string    `json:"ip"`
-	UserAgent string    `json:"user_agent"`
-	Phone     string    `json:"phone"`
+type CreateFleetInvitationParams struct {
+	FleetID   uuid.UUID     `json:"fleet_id"`
+	CourierID uuid.UUID     `json:"courier_id"`
+	InvitedBy uuid.NullUUID `json:"invited_by"`
 }
 
-func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession,
-		arg.ID,
-		arg.Ip,
-		arg.UserAgent,
-		arg.Phone,
-	)
-	var i Session
+func (q *Queries) CreateFleetInvitation(ctx context.Context, arg CreateFleetInvitationParams) (FleetInvitation, error) {
+	row := q.db.QueryRowContext(ctx, createFleetInvitation, arg.FleetID, arg.CourierID, arg.InvitedBy)
+	var i FleetInvitation
 	err := row.Scan(
 		&i.ID,
-		&i.Ip,
-		&i.UserAgent,
-		&i.Phone,
+		&i.FleetID,
+		&i.CourierID,
+		&i.InvitedBy,
+		&i.Status,
+		&i.RespondedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const createTrip = `-- name: CreateTrip :one
-INSERT INTO trips (
-  user_id, product_id, confirmed_pickup, start_location, end_location
+const createLedgerEntry = `-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (
+  transaction_id, account_id, amount, balance
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4
 )
-RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at
+RETURNING id, transaction_id, account_id, amount, balance, created_at
 `
 
-type CreateTripParams struct {
-	UserID          uuid.UUID   `json:"user_id"`
-	ProductID       uuid.UUID   `json:"product_id"`
-	ConfirmedPickup interface{} `json:"confirmed_pickup"`
-	StartLocation   interface{} `json:"start_location"`
-	EndLocation     interface{} `json:"end_location"`
+type CreateLedgerEntryParams struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	AccountID     uuid.UUID `json:"account_id"`
+	Amount        int32     `json:"amount"`
+	Balance       int32     `json:"balance"`
 }
 
-func (q *Queries) CreateTrip(ctx context.Context, arg CreateTripParams) (Trip, error) {
-	row := q.db.QueryRowContext(ctx, createTrip,
-		arg.UserID,
-		arg.ProductID,
-		arg.ConfirmedPickup,
-		arg.StartLocation,
-		arg.EndLocation,
+func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error) {
+	row := q.db.QueryRowContext(ctx, createLedgerEntry,
+		arg.TransactionID,
+		arg.AccountID,
+		arg.Amount,
+		arg.Balance,
 	)
-	var i Trip
+	var i LedgerEntry
 	err := row.Scan(
 		&i.ID,
-		&i.StartLocation,
-		&i.EndLocation,
-		&i.ConfirmedPickup,
-		&i.CourierID,
-		&i.UserID,
-		&i.ProductID,
-		&i.Cost,
-		&i.Status,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.Balance,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const createTripCost = `-- name: CreateTripCost :one
-UPDATE trips
-SET cost = $1
-WHERE id = $2
-RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at
+const createLedgerTransaction = `-- name: CreateLedgerTransaction :one
+INSERT INTO ledger_transactions (
+  kind, reference, trip_id, payment_id, description
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (reference) DO NOTHING
+RETURNING id, kind, reference, trip_id, payment_id, description, created_at
 `
 
-type CreateTripCostParams struct {
-	Cost int32     `json:"cost"`
-	ID   uuid.UUID `json:"id"`
+type CreateLedgerTransactionParams struct {
+	Kind        string        `json:"kind"`
+	Reference   string        `json:"reference"`
+	TripID      uuid.NullUUID `json:"trip_id"`
+	PaymentID   uuid.NullUUID `json:"payment_id"`
+	Description string        `json:"description"`
 }
 
-func (q *Queries) CreateTripCost(ctx context.Context, arg CreateTripCostParams) (Trip, error) {
-	row := q.db.QueryRowContext(ctx, createTripCost, arg.Cost, arg.ID)
-	var i Trip
+func (q *Queries) CreateLedgerTransaction(ctx context.Context, arg CreateLedgerTransactionParams) (LedgerTransaction, error) {
+	row := q.db.QueryRowContext(ctx, createLedgerTransaction,
+		arg.Kind,
+		arg.Reference,
+		arg.TripID,
+		arg.PaymentID,
+		arg.Description,
+	)
+	var i LedgerTransaction
 	err := row.Scan(
 		&i.ID,
-		&i.StartLocation,
-		&i.EndLocation,
-		&i.ConfirmedPickup,
-		&i.CourierID,
-		&i.UserID,
-		&i.ProductID,
-		&i.Cost,
-		&i.Status,
+		&i.Kind,
+		&i.Reference,
+		&i.TripID,
+		&i.PaymentID,
+		&i.Description,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO users (
-  first_name, last_name, phone
+const createPayment = `-- name: CreatePayment :one
+INSERT INTO payments (
+  trip_id, user_id, provider, purpose, amount, phone, card_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
 `
 
-type CreateUserParams struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Phone     string `json:"phone"`
+type CreatePaymentParams struct {
+	TripID   uuid.NullUUID  `json:"trip_id"`
+	UserID   uuid.UUID      `json:"user_id"`
+	Provider string         `json:"provider"`
+	Purpose  string         `json:"purpose"`
+	Amount   int32          `json:"amount"`
+	Phone    sql.NullString `json:"phone"`
+	CardID   uuid.NullUUID  `json:"card_id"`
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.FirstName, arg.LastName, arg.Phone)
-	var i User
+func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, createPayment,
+		arg.TripID,
+		arg.UserID,
+		arg.Provider,
+		arg.Purpose,
+		arg.Amount,
+		arg.Phone,
+		arg.CardID,
+	)
+	var i Payment
 	err := row.Scan(
 		&i.ID,
-		&i.FirstName,
-		&i.LastName,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
 		&i.Phone,
-		&i.Onboarding,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
 	)
 	return i, err
 }
 
-const createUserUpload = `-- name: CreateUserUpload :one
-INSERT INTO uploads (
-  type, uri, user_id
+const createPaymentEvent = `-- name: CreatePaymentEvent :one
+INSERT INTO payment_events (
+  provider, event_key, event
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at
+ON CONFLICT (event_key) DO NOTHING
+RETURNING id, provider, event_key, event, created_at
 `
 
-type CreateUserUploadParams struct {
-	Type   string        `json:"type"`
-	Uri    string        `json:"uri"`
-	UserID uuid.NullUUID `json:"user_id"`
+type CreatePaymentEventParams struct {
+	Provider string `json:"provider"`
+	EventKey string `json:"event_key"`
+	Event    string `json:"event"`
 }
 
-func (q *Queries) CreateUserUpload(ctx context.Context, arg CreateUserUploadParams) (Upload, error) {
-	row := q.db.QueryRowContext(ctx, createUserUpload, arg.Type, arg.Uri, arg.UserID)
-	var i Upload
+func (q *Queries) CreatePaymentEvent(ctx context.Context, arg CreatePaymentEventParams) (PaymentEvent, error) {
+	row := q.db.QueryRowContext(ctx, createPaymentEvent, arg.Provider, arg.EventKey, arg.Event)
+	var i PaymentEvent
 	err := row.Scan(
 		&i.ID,
-		&i.Type,
-		&i.Uri,
-		&i.Verification,
-		&i.CourierID,
-		&i.UserID,
+		&i.Provider,
+		&i.EventKey,
+		&i.Event,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const findAvailableCourier = `-- name: FindAvailableCourier :one
-SELECT id, user_id, product_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE ST_DWithin(location, $1::geography, $2) AND status = 'ONLINE' AND verified = 'true' AND trip_id IS null
-LIMIT 1
+const createPayout = `-- name: CreatePayout :one
+INSERT INTO payouts (
+  courier_id, user_id, amount, phone
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id
 `
 
-type FindAvailableCourierParams struct {
-	Point  interface{} `json:"point"`
-	Radius interface{} `json:"radius"`
-}
-
-type FindAvailableCourierRow struct {
-	ID        uuid.UUID     `json:"id"`
-	UserID    uuid.NullUUID `json:"user_id"`
-	ProductID uuid.NullUUID `json:"product_id"`
-	Location  interface{}   `json:"location"`
+type CreatePayoutParams struct {
+	CourierID uuid.NullUUID `json:"courier_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Amount    int32         `json:"amount"`
+	Phone     string        `json:"phone"`
 }
 
-func (q *Queries) FindAvailableCourier(ctx context.Context, arg FindAvailableCourierParams) (FindAvailableCourierRow, error) {
-	row := q.db.QueryRowContext(ctx, findAvailableCourier, arg.Point, arg.Radius)
-	var i FindAvailableCourierRow
+func (q *Queries) CreatePayout(ctx context.Context, arg CreatePayoutParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, createPayout,
+		arg.CourierID,
+		arg.UserID,
+		arg.Amount,
+		arg.Phone,
+	)
+	var i Payout
 	err := row.Scan(
 		&i.ID,
+		&i.CourierID,
 		&i.UserID,
-		&i.ProductID,
-		&i.Location,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatusConversationID,
 	)
 	return i, err
 }
 
-const findByPhone = `-- name: FindByPhone :one
-SELECT id, first_name, last_name, phone, onboarding, created_at, updated_at FROM users
-WHERE phone = $1
-LIMIT 1
+const createPayoutEntries = `-- name: CreatePayoutEntries :exec
+INSERT INTO payout_entries (payout_id, entry_id)
+SELECT $1, unnest($2::uuid[])
 `
 
-func (q *Queries) FindByPhone(ctx context.Context, phone string) (User, error) {
-	row := q.db.QueryRowContext(ctx, findByPhone, phone)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.FirstName,
-		&i.LastName,
-		&i.Phone,
-		&i.Onboarding,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type CreatePayoutEntriesParams struct {
+	PayoutID uuid.UUID   `json:"payout_id"`
+	EntryIds []uuid.UUID `json:"entry_ids"`
 }
 
-const findUserByID = `-- name: FindUserByID :one
-SELECT id, first_name, last_name, phone, onboarding, created_at, updated_at FROM users
-WHERE id = $1
-LIMIT 1
+func (q *Queries) CreatePayoutEntries(ctx context.Context, arg CreatePayoutEntriesParams) error {
+	_, err := q.db.ExecContext(ctx, createPayoutEntries, arg.PayoutID, pq.Array(arg.EntryIds))
+	return err
+}
+
+const createPromotionRedemption = `-- name: CreatePromotionRedemption :one
+INSERT INTO promotion_redemptions (
+  promotion_id, user_id, trip_id, amount
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, promotion_id, user_id, trip_id, amount, created_at, updated_at
 `
 
-func (q *Queries) FindUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUserByID, id)
-	var i User
+type CreatePromotionRedemptionParams struct {
+	PromotionID uuid.UUID `json:"promotion_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	TripID      uuid.UUID `json:"trip_id"`
+	Amount      int32     `json:"amount"`
+}
+
+func (q *Queries) CreatePromotionRedemption(ctx context.Context, arg CreatePromotionRedemptionParams) (PromotionRedemption, error) {
+	row := q.db.QueryRowContext(ctx, createPromotionRedemption,
+		arg.PromotionID,
+		arg.UserID,
+		arg.TripID,
+		arg.Amount,
+	)
+	var i PromotionRedemption
 	err := row.Scan(
 		&i.ID,
-		&i.FirstName,
-		&i.LastName,
-		&i.Phone,
-		&i.Onboarding,
+		&i.PromotionID,
+		&i.UserID,
+		&i.TripID,
+		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getCourierAssignedTrip = `-- name: GetCourierAssignedTrip :one
-SELECT id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at FROM couriers
-WHERE id = $1 AND trip_id = null
-LIMIT 1
+const createQuest = `-- name: CreateQuest :one
+INSERT INTO quests (
+  name, description, target_trips, reward, zone_id, product_id, tier, starts_at, ends_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, name, description, target_trips, reward, zone_id, product_id, tier, starts_at, ends_at, active, created_at, updated_at
 `
 
-func (q *Queries) GetCourierAssignedTrip(ctx context.Context, id uuid.UUID) (Courier, error) {
-	row := q.db.QueryRowContext(ctx, getCourierAssignedTrip, id)
-	var i Courier
+type CreateQuestParams struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	TargetTrips int32          `json:"target_trips"`
+	Reward      int32          `json:"reward"`
+	ZoneID      uuid.NullUUID  `json:"zone_id"`
+	ProductID   uuid.NullUUID  `json:"product_id"`
+	Tier        sql.NullString `json:"tier"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+}
+
+func (q *Queries) CreateQuest(ctx context.Context, arg CreateQuestParams) (Quest, error) {
+	row := q.db.QueryRowContext(ctx, createQuest,
+		arg.Name,
+		arg.Description,
+		arg.TargetTrips,
+		arg.Reward,
+		arg.ZoneID,
+		arg.ProductID,
+		arg.Tier,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i Quest
 	err := row.Scan(
 		&i.ID,
-		&i.Verified,
-		&i.Status,
-		&i.Location,
-		&i.Ratings,
-		&i.Points,
-		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.TargetTrips,
+		&i.Reward,
+		&i.ZoneID,
 		&i.ProductID,
-		&i.TripID,
+		&i.Tier,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getCourierAvatar = `-- name: GetCourierAvatar :one
-SELECT id, uri FROM uploads
-WHERE courier_id = $1 AND type = 'DP'
+const createQuestTrip = `-- name: CreateQuestTrip :one
+INSERT INTO quest_trips (
+  quest_id, trip_id, courier_id
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (quest_id, trip_id) DO NOTHING
+RETURNING quest_id
 `
 
-type GetCourierAvatarRow struct {
-	ID  uuid.UUID `json:"id"`
-	Uri string    `json:"uri"`
+type CreateQuestTripParams struct {
+	QuestID   uuid.UUID `json:"quest_id"`
+	TripID    uuid.UUID `json:"trip_id"`
+	CourierID uuid.UUID `json:"courier_id"`
 }
 
-func (q *Queries) GetCourierAvatar(ctx context.Context, courierID uuid.NullUUID) (GetCourierAvatarRow, error) {
-	row := q.db.QueryRowContext(ctx, getCourierAvatar, courierID)
-	var i GetCourierAvatarRow
-	err := row.Scan(&i.ID, &i.Uri)
-	return i, err
+func (q *Queries) CreateQuestTrip(ctx context.Context, arg CreateQuestTripParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, createQuestTrip, arg.QuestID, arg.TripID, arg.CourierID)
+	var quest_id uuid.UUID
+	err := row.Scan(&quest_id)
+	return quest_id, err
 }
 
-const getCourierByID = `-- name: GetCourierByID :one
-SELECT id, trip_id, product_id, user_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE id = $1
-LIMIT 1
+const createRecipient = `-- name: CreateRecipient :one
+INSERT INTO recipients (
+  name, building, unit, phone, trip_id, trip_note
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, name, building, unit, phone, trip_note, trip_id, created_at, updated_at
 `
 
-type GetCourierByIDRow struct {
-	ID        uuid.UUID     `json:"id"`
-	TripID    uuid.NullUUID `json:"trip_id"`
-	ProductID uuid.NullUUID `json:"product_id"`
-	UserID    uuid.NullUUID `json:"user_id"`
-	Location  interface{}   `json:"location"`
+type CreateRecipientParams struct {
+	Name     string         `json:"name"`
+	Building sql.NullString `json:"building"`
+	Unit     sql.NullString `json:"unit"`
+	Phone    string         `json:"phone"`
+	TripID   uuid.NullUUID  `json:"trip_id"`
+	TripNote string         `json:"trip_note"`
 }
 
-func (q *Queries) GetCourierByID(ctx context.Context, id uuid.UUID) (GetCourierByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getCourierByID, id)
-	var i GetCourierByIDRow
+func (q *Queries) CreateRecipient(ctx context.Context, arg CreateRecipientParams) (Recipient, error) {
+	row := q.db.QueryRowContext(ctx, createRecipient,
+		arg.Name,
+		arg.Building,
+		arg.Unit,
+		arg.Phone,
+		arg.TripID,
+		arg.TripNote,
+	)
+	var i Recipient
 	err := row.Scan(
 		&i.ID,
+		&i.Name,
+		&i.Building,
+		&i.Unit,
+		&i.Phone,
+		&i.TripNote,
 		&i.TripID,
-		&i.ProductID,
-		&i.UserID,
-		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getCourierByUserID = `-- name: GetCourierByUserID :one
-SELECT id, user_id, product_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE user_id = $1
-LIMIT 1
+const createReferral = `-- name: CreateReferral :one
+INSERT INTO referrals (
+  referrer_id, referee_id, kind, status, device_id
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, referrer_id, referee_id, kind, status, device_id, rewarded_at, created_at, updated_at
 `
 
-type GetCourierByUserIDRow struct {
-	ID        uuid.UUID     `json:"id"`
-	UserID    uuid.NullUUID `json:"user_id"`
-	ProductID uuid.NullUUID `json:"product_id"`
-	Location  interface{}   `json:"location"`
+type CreateReferralParams struct {
+	ReferrerID uuid.UUID      `json:"referrer_id"`
+	RefereeID  uuid.UUID      `json:"referee_id"`
+	Kind       string         `json:"kind"`
+	Status     string         `json:"status"`
+	DeviceID   sql.NullString `json:"device_id"`
 }
 
-func (q *Queries) GetCourierByUserID(ctx context.Context, userID uuid.NullUUID) (GetCourierByUserIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getCourierByUserID, userID)
-	var i GetCourierByUserIDRow
+func (q *Queries) CreateReferral(ctx context.Context, arg CreateReferralParams) (Referral, error) {
+	row := q.db.QueryRowContext(ctx, createReferral,
+		arg.ReferrerID,
+		arg.RefereeID,
+		arg.Kind,
+		arg.Status,
+		arg.DeviceID,
+	)
+	var i Referral
 	err := row.Scan(
 		&i.ID,
-		&i.UserID,
-		&i.ProductID,
-		&i.Location,
+		&i.ReferrerID,
+		&i.RefereeID,
+		&i.Kind,
+		&i.Status,
+		&i.DeviceID,
+		&i.RewardedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getCourierLocation = `-- name: GetCourierLocation :one
-SELECT ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE id = $1
-LIMIT 1
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (
+  id, ip, user_agent, phone
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, ip, user_agent, phone, created_at, updated_at
 `
 
-func (q *Queries) GetCourierLocation(ctx context.Context, id uuid.UUID) (interface{}, error) {
-	row := q.db.QueryRowContext(ctx, getCourierLocation, id)
-	var location interface{}
-	err := row.Scan(&location)
-	return location, err
+type CreateSessionParams struct {
+	ID        uuid.UUID `json:"id"`
+	Ip        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Phone     string    `json:"phone"`
 }
 
-const getCourierNearPickupPoint = `-- name: GetCourierNearPickupPoint :many
-SELECT id, product_id, ST_AsGeoJSON(location) AS location FROM
-couriers
-WHERE ST_DWithin(location, $1::geography, $2) AND status = 'ONLINE' AND verified = 'true'
-`
-
-type GetCourierNearPickupPointParams struct {
-	Point  interface{} `json:"point"`
-	Radius interface{} `json:"radius"`
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.ID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Phone,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Ip,
+		&i.UserAgent,
+		&i.Phone,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-type GetCourierNearPickupPointRow struct {
-	ID        uuid.UUID     `json:"id"`
-	ProductID uuid.NullUUID `json:"product_id"`
-	Location  interface{}   `json:"location"`
+const createShift = `-- name: CreateShift :one
+INSERT INTO shifts (
+  zone_id, starts_at, ends_at, capacity, guarantee
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, zone_id, starts_at, ends_at, capacity, guarantee, created_at, updated_at
+`
+
+type CreateShiftParams struct {
+	ZoneID    uuid.UUID `json:"zone_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int32     `json:"capacity"`
+	Guarantee int32     `json:"guarantee"`
 }
 
-func (q *Queries) GetCourierNearPickupPoint(ctx context.Context, arg GetCourierNearPickupPointParams) ([]GetCourierNearPickupPointRow, error) {
-	rows, err := q.db.QueryContext(ctx, getCourierNearPickupPoint, arg.Point, arg.Radius)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetCourierNearPickupPointRow{}
-	for rows.Next() {
-		var i GetCourierNearPickupPointRow
-		if err := rows.Scan(&i.ID, &i.ProductID, &i.Location); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CreateShift(ctx context.Context, arg CreateShiftParams) (Shift, error) {
+	row := q.db.QueryRowContext(ctx, createShift,
+		arg.ZoneID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Capacity,
+		arg.Guarantee,
+	)
+	var i Shift
+	err := row.Scan(
+		&i.ID,
+		&i.ZoneID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Guarantee,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const getCourierStatus = `-- name: GetCourierStatus :one
-SELECT status FROM
-couriers
-WHERE user_id = $1
-LIMIT 1
+const createShiftBooking = `-- name: CreateShiftBooking :one
+INSERT INTO shift_bookings (
+  shift_id, courier_id
+) VALUES (
+  $1, $2
+)
+RETURNING id, shift_id, courier_id, status, checked_in_at, cancelled_at, earned, guarantee_paid, settled_at, created_at, updated_at
 `
 
-func (q *Queries) GetCourierStatus(ctx context.Context, userID uuid.NullUUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getCourierStatus, userID)
-	var status string
-	err := row.Scan(&status)
-	return status, err
+type CreateShiftBookingParams struct {
+	ShiftID   uuid.UUID `json:"shift_id"`
+	CourierID uuid.UUID `json:"courier_id"`
 }
 
-const getCourierTrip = `-- name: GetCourierTrip :one
-SELECT id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at FROM trips
-WHERE courier_id = $1
-LIMIT 1
+func (q *Queries) CreateShiftBooking(ctx context.Context, arg CreateShiftBookingParams) (ShiftBooking, error) {
+	row := q.db.QueryRowContext(ctx, createShiftBooking, arg.ShiftID, arg.CourierID)
+	var i ShiftBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ShiftID,
+		&i.CourierID,
+		&i.Status,
+		&i.CheckedInAt,
+		&i.CancelledAt,
+		&i.Earned,
+		&i.GuaranteePaid,
+		&i.SettledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createTrip = `-- name: CreateTrip :one
+INSERT INTO trips (
+  user_id, product_id, confirmed_pickup, start_location, end_location, promotion_id, collect_amount
+) VALUES (
+  $1, $2, $3, $5, $6, $7, $4
+)
+RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id
 `
 
-func (q *Queries) GetCourierTrip(ctx context.Context, courierID uuid.NullUUID) (Trip, error) {
-	row := q.db.QueryRowContext(ctx, getCourierTrip, courierID)
+type CreateTripParams struct {
+	UserID          uuid.UUID     `json:"user_id"`
+	ProductID       uuid.UUID     `json:"product_id"`
+	ConfirmedPickup interface{}   `json:"confirmed_pickup"`
+	CollectAmount   int32         `json:"collect_amount"`
+	StartLocation   interface{}   `json:"start_location"`
+	EndLocation     interface{}   `json:"end_location"`
+	PromotionID     uuid.NullUUID `json:"promotion_id"`
+}
+
+func (q *Queries) CreateTrip(ctx context.Context, arg CreateTripParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, createTrip,
+		arg.UserID,
+		arg.ProductID,
+		arg.ConfirmedPickup,
+		arg.CollectAmount,
+		arg.StartLocation,
+		arg.EndLocation,
+		arg.PromotionID,
+	)
 	var i Trip
 	err := row.Scan(
 		&i.ID,
@@ -609,121 +1288,508 @@ func (q *Queries) GetCourierTrip(ctx context.Context, courierID uuid.NullUUID) (
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
 	)
 	return i, err
 }
 
-const getCourierUpload = `-- name: GetCourierUpload :one
-SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at FROM
-uploads
-WHERE courier_id = $1 AND type = $2
-LIMIT 1
+const createTripCost = `-- name: CreateTripCost :one
+UPDATE trips
+SET cost = $1, distance = $2, duration = $3
+WHERE id = $4
+RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id
 `
 
-type GetCourierUploadParams struct {
-	CourierID uuid.NullUUID `json:"courier_id"`
-	Type      string        `json:"type"`
+type CreateTripCostParams struct {
+	Cost     int32     `json:"cost"`
+	Distance int32     `json:"distance"`
+	Duration int32     `json:"duration"`
+	ID       uuid.UUID `json:"id"`
 }
 
-func (q *Queries) GetCourierUpload(ctx context.Context, arg GetCourierUploadParams) (Upload, error) {
-	row := q.db.QueryRowContext(ctx, getCourierUpload, arg.CourierID, arg.Type)
-	var i Upload
+func (q *Queries) CreateTripCost(ctx context.Context, arg CreateTripCostParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, createTripCost,
+		arg.Cost,
+		arg.Distance,
+		arg.Duration,
+		arg.ID,
+	)
+	var i Trip
 	err := row.Scan(
 		&i.ID,
-		&i.Type,
-		&i.Uri,
-		&i.Verification,
+		&i.StartLocation,
+		&i.EndLocation,
+		&i.ConfirmedPickup,
 		&i.CourierID,
 		&i.UserID,
+		&i.ProductID,
+		&i.Cost,
+		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
 	)
 	return i, err
 }
 
-const getCourierUploads = `-- name: GetCourierUploads :many
-SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at FROM uploads
-WHERE courier_id = $1
+const createTripRating = `-- name: CreateTripRating :one
+INSERT INTO trip_ratings (
+  trip_id, target, rater_id, ratee_id, rating, tags, comment
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (trip_id, target) DO NOTHING
+RETURNING id, trip_id, target, rater_id, ratee_id, rating, tags, comment, created_at
 `
 
-func (q *Queries) GetCourierUploads(ctx context.Context, courierID uuid.NullUUID) ([]Upload, error) {
-	rows, err := q.db.QueryContext(ctx, getCourierUploads, courierID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Upload{}
-	for rows.Next() {
-		var i Upload
-		if err := rows.Scan(
-			&i.ID,
-			&i.Type,
-			&i.Uri,
-			&i.Verification,
-			&i.CourierID,
-			&i.UserID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type CreateTripRatingParams struct {
+	TripID  uuid.UUID      `json:"trip_id"`
+	Target  string         `json:"target"`
+	RaterID uuid.UUID      `json:"rater_id"`
+	RateeID uuid.UUID      `json:"ratee_id"`
+	Rating  int16          `json:"rating"`
+	Tags    []string       `json:"tags"`
+	Comment sql.NullString `json:"comment"`
 }
 
-const getNearbyAvailableCourierProducts = `-- name: GetNearbyAvailableCourierProducts :many
-SELECT c.id, c.product_id, p.id, p.name, p.description, p.weight_class, p.icon, p.relevance, p.created_at, p.updated_at FROM couriers c
-JOIN products p
-ON ST_DWithin(c.location, $1::geography, $2)
-WHERE c.product_id = p.id AND c.verified = 'true'
-ORDER BY p.relevance ASC
+func (q *Queries) CreateTripRating(ctx context.Context, arg CreateTripRatingParams) (TripRating, error) {
+	row := q.db.QueryRowContext(ctx, createTripRating,
+		arg.TripID,
+		arg.Target,
+		arg.RaterID,
+		arg.RateeID,
+		arg.Rating,
+		pq.Array(arg.Tags),
+		arg.Comment,
+	)
+	var i TripRating
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Target,
+		&i.RaterID,
+		&i.RateeID,
+		&i.Rating,
+		pq.Array(&i.Tags),
+		&i.Comment,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (
+  first_name, last_name, phone, referral_code, device_id
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count
 `
 
-type GetNearbyAvailableCourierProductsParams struct {
-	Point  interface{} `json:"point"`
-	Radius interface{} `json:"radius"`
+type CreateUserParams struct {
+	FirstName    string         `json:"first_name"`
+	LastName     string         `json:"last_name"`
+	Phone        string         `json:"phone"`
+	ReferralCode sql.NullString `json:"referral_code"`
+	DeviceID     sql.NullString `json:"device_id"`
 }
 
-type GetNearbyAvailableCourierProductsRow struct {
-	ID          uuid.UUID     `json:"id"`
-	ProductID   uuid.NullUUID `json:"product_id"`
-	ID_2        uuid.UUID     `json:"id_2"`
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	WeightClass int32         `json:"weight_class"`
-	Icon        string        `json:"icon"`
-	Relevance   int32         `json:"relevance"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.FirstName,
+		arg.LastName,
+		arg.Phone,
+		arg.ReferralCode,
+		arg.DeviceID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.Onboarding,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
+	)
+	return i, err
 }
 
-func (q *Queries) GetNearbyAvailableCourierProducts(ctx context.Context, arg GetNearbyAvailableCourierProductsParams) ([]GetNearbyAvailableCourierProductsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getNearbyAvailableCourierProducts, arg.Point, arg.Radius)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetNearbyAvailableCourierProductsRow{}
-	for rows.Next() {
-		var i GetNearbyAvailableCourierProductsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProductID,
-			&i.ID_2,
-			&i.Name,
-			&i.Description,
-			&i.WeightClass,
-			&i.Icon,
-			&i.Relevance,
+const createUserUpload = `-- name: CreateUserUpload :one
+INSERT INTO uploads (
+  type, uri, user_id
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at
+`
+
+type CreateUserUploadParams struct {
+	Type   string        `json:"type"`
+	Uri    string        `json:"uri"`
+	UserID uuid.NullUUID `json:"user_id"`
+}
+
+func (q *Queries) CreateUserUpload(ctx context.Context, arg CreateUserUploadParams) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, createUserUpload, arg.Type, arg.Uri, arg.UserID)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Uri,
+		&i.Verification,
+		&i.CourierID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+	)
+	return i, err
+}
+
+const createVehicle = `-- name: CreateVehicle :one
+INSERT INTO vehicles (
+  courier_id, product_id, type, plate, make, colour, capacity, fleet_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at
+`
+
+type CreateVehicleParams struct {
+	CourierID uuid.UUID     `json:"courier_id"`
+	ProductID uuid.UUID     `json:"product_id"`
+	Type      string        `json:"type"`
+	Plate     string        `json:"plate"`
+	Make      string        `json:"make"`
+	Colour    string        `json:"colour"`
+	Capacity  int32         `json:"capacity"`
+	FleetID   uuid.NullUUID `json:"fleet_id"`
+}
+
+func (q *Queries) CreateVehicle(ctx context.Context, arg CreateVehicleParams) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, createVehicle,
+		arg.CourierID,
+		arg.ProductID,
+		arg.Type,
+		arg.Plate,
+		arg.Make,
+		arg.Colour,
+		arg.Capacity,
+		arg.FleetID,
+	)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.ProductID,
+		&i.Type,
+		&i.Plate,
+		&i.Make,
+		&i.Colour,
+		&i.Capacity,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FleetID,
+		&i.FleetApprovedBy,
+		&i.FleetApprovedAt,
+	)
+	return i, err
+}
+
+const deletePayoutEntries = `-- name: DeletePayoutEntries :exec
+DELETE FROM payout_entries
+WHERE payout_id = $1
+`
+
+func (q *Queries) DeletePayoutEntries(ctx context.Context, payoutID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePayoutEntries, payoutID)
+	return err
+}
+
+const ensureLedgerAccount = `-- name: EnsureLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  user_id, kind
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id, kind) DO NOTHING
+`
+
+type EnsureLedgerAccountParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Kind   string        `json:"kind"`
+}
+
+func (q *Queries) EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) error {
+	_, err := q.db.ExecContext(ctx, ensureLedgerAccount, arg.UserID, arg.Kind)
+	return err
+}
+
+const expireCourierUploads = `-- name: ExpireCourierUploads :many
+UPDATE uploads
+SET verification = 'REJECTED', rejection_reason = $1, updated_at = $2
+WHERE courier_id IS NOT NULL AND verification = 'VERIFIED' AND expires_at <= $2
+RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at
+`
+
+type ExpireCourierUploadsParams struct {
+	Reason sql.NullString `json:"reason"`
+	Now    time.Time      `json:"now"`
+}
+
+func (q *Queries) ExpireCourierUploads(ctx context.Context, arg ExpireCourierUploadsParams) ([]Upload, error) {
+	rows, err := q.db.QueryContext(ctx, expireCourierUploads, arg.Reason, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Upload{}
+	for rows.Next() {
+		var i Upload
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Uri,
+			&i.Verification,
+			&i.CourierID,
+			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const expireStaleTripPayments = `-- name: ExpireStaleTripPayments :exec
+UPDATE payments
+SET status = 'FAILED', result_desc = 'Checkout expired', updated_at = NOW()
+WHERE trip_id = $1 AND purpose = $2 AND status = 'PENDING'
+AND created_at <= NOW() - INTERVAL '2 minutes'
+`
+
+type ExpireStaleTripPaymentsParams struct {
+	TripID  uuid.NullUUID `json:"trip_id"`
+	Purpose string        `json:"purpose"`
+}
+
+func (q *Queries) ExpireStaleTripPayments(ctx context.Context, arg ExpireStaleTripPaymentsParams) error {
+	_, err := q.db.ExecContext(ctx, expireStaleTripPayments, arg.TripID, arg.Purpose)
+	return err
+}
+
+const findAvailableCourier = `-- name: FindAvailableCourier :one
+SELECT c.id, c.user_id, c.product_id FROM couriers c
+JOIN (
+  SELECT unnest($1::uuid[]) AS id, unnest($2::float8[]) AS distance
+) n ON n.id = c.id
+WHERE c.status = 'ONLINE' AND c.verified = 'true' AND c.trip_id IS null
+AND c.product_id = $3
+ORDER BY EXISTS (
+  SELECT 1 FROM shift_bookings b
+  JOIN shifts s ON s.id = b.shift_id
+  WHERE b.courier_id = c.id AND b.status = 'CHECKED_IN' AND s.starts_at <= $4 AND s.ends_at > $4
+) DESC,
+n.distance * (6 - COALESCE(c.rating, 4)) * CASE c.tier WHEN 'GOLD' THEN 0.8 WHEN 'SILVER' THEN 0.9 ELSE 1 END
+LIMIT 1
+`
+
+type FindAvailableCourierParams struct {
+	Ids       []uuid.UUID   `json:"ids"`
+	Distances []float64     `json:"distances"`
+	ProductID uuid.NullUUID `json:"product_id"`
+	Now       time.Time     `json:"now"`
+}
+
+type FindAvailableCourierRow struct {
+	ID        uuid.UUID     `json:"id"`
+	UserID    uuid.NullUUID `json:"user_id"`
+	ProductID uuid.NullUUID `json:"product_id"`
+}
+
+// Candidates and their distance from pickup come from the live location index
+// Couriers checked in on a running shift go first
+// Better rated and higher tier couriers reach further, unrated ones count as 4 stars
+func (q *Queries) FindAvailableCourier(ctx context.Context, arg FindAvailableCourierParams) (FindAvailableCourierRow, error) {
+	row := q.db.QueryRowContext(ctx, findAvailableCourier,
+		pq.Array(arg.Ids),
+		pq.Array(arg.Distances),
+		arg.ProductID,
+		arg.Now,
+	)
+	var i FindAvailableCourierRow
+	err := row.Scan(&i.ID, &i.UserID, &i.ProductID)
+	return i, err
+}
+
+const findByPhone = `-- name: FindByPhone :one
+SELECT id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count FROM users
+WHERE phone = $1
+LIMIT 1
+`
+
+func (q *Queries) FindByPhone(ctx context.Context, phone string) (User, error) {
+	row := q.db.QueryRowContext(ctx, findByPhone, phone)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.Onboarding,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const findUserByID = `-- name: FindUserByID :one
+SELECT id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count FROM users
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) FindUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.Onboarding,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const flushCourierLocations = `-- name: FlushCourierLocations :execrows
+UPDATE couriers c
+SET location = f.location::geography, location_updated_at = f.located_at
+FROM (
+  SELECT unnest($1::uuid[]) AS id, unnest($2::text[]) AS location, unnest($3::timestamp[]) AS located_at
+) f
+WHERE c.id = f.id AND (c.location_updated_at IS NULL OR c.location_updated_at < f.located_at)
+`
+
+type FlushCourierLocationsParams struct {
+	Ids       []uuid.UUID `json:"ids"`
+	Locations []string    `json:"locations"`
+	LocatedAt []time.Time `json:"located_at"`
+}
+
+func (q *Queries) FlushCourierLocations(ctx context.Context, arg FlushCourierLocationsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, flushCourierLocations, pq.Array(arg.Ids), pq.Array(arg.Locations), pq.Array(arg.LocatedAt))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActivePricingSchedules = `-- name: GetActivePricingSchedules :many
+SELECT id, name, kind, multiplier, start_time, end_time, product_id FROM pricing_schedules
+WHERE valid_from <= $1 AND (valid_until IS NULL OR valid_until > $1)
+AND (product_id IS NULL OR product_id = ANY($2::uuid[]))
+AND (zone_id IS NULL OR zone_id = $3)
+`
+
+type GetActivePricingSchedulesParams struct {
+	At         time.Time     `json:"at"`
+	ProductIds []uuid.UUID   `json:"product_ids"`
+	ZoneID     uuid.NullUUID `json:"zone_id"`
+}
+
+type GetActivePricingSchedulesRow struct {
+	ID         uuid.UUID     `json:"id"`
+	Name       string        `json:"name"`
+	Kind       string        `json:"kind"`
+	Multiplier float64       `json:"multiplier"`
+	StartTime  sql.NullTime  `json:"start_time"`
+	EndTime    sql.NullTime  `json:"end_time"`
+	ProductID  uuid.NullUUID `json:"product_id"`
+}
+
+func (q *Queries) GetActivePricingSchedules(ctx context.Context, arg GetActivePricingSchedulesParams) ([]GetActivePricingSchedulesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivePricingSchedules, arg.At, pq.Array(arg.ProductIds), arg.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivePricingSchedulesRow{}
+	for rows.Next() {
+		var i GetActivePricingSchedulesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Kind,
+			&i.Multiplier,
+			&i.StartTime,
+			&i.EndTime,
+			&i.ProductID,
 		); err != nil {
 			return nil, err
 		}
@@ -738,225 +1804,4063 @@ func (q *Queries) GetNearbyAvailableCourierProducts(ctx context.Context, arg Get
 	return items, nil
 }
 
-const getProductByID = `-- name: GetProductByID :one
-SELECT id, icon, name, weight_class FROM products
-WHERE id = $1
-LIMIT 1
+const getCourierAssignedTrip = `-- name: GetCourierAssignedTrip :one
+SELECT id, verified, status, location, points, user_id, product_id, trip_id, created_at, updated_at, rating, rating_count, tier, vehicle_id, location_updated_at, fleet_id FROM couriers
+WHERE id = $1 AND trip_id = null
+LIMIT 1
+`
+
+func (q *Queries) GetCourierAssignedTrip(ctx context.Context, id uuid.UUID) (Courier, error) {
+	row := q.db.QueryRowContext(ctx, getCourierAssignedTrip, id)
+	var i Courier
+	err := row.Scan(
+		&i.ID,
+		&i.Verified,
+		&i.Status,
+		&i.Location,
+		&i.Points,
+		&i.UserID,
+		&i.ProductID,
+		&i.TripID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rating,
+		&i.RatingCount,
+		&i.Tier,
+		&i.VehicleID,
+		&i.LocationUpdatedAt,
+		&i.FleetID,
+	)
+	return i, err
+}
+
+const getCourierAvatar = `-- name: GetCourierAvatar :one
+SELECT id, uri FROM uploads
+WHERE courier_id = $1 AND type = 'DP'
+`
+
+type GetCourierAvatarRow struct {
+	ID  uuid.UUID `json:"id"`
+	Uri string    `json:"uri"`
+}
+
+func (q *Queries) GetCourierAvatar(ctx context.Context, courierID uuid.NullUUID) (GetCourierAvatarRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierAvatar, courierID)
+	var i GetCourierAvatarRow
+	err := row.Scan(&i.ID, &i.Uri)
+	return i, err
+}
+
+const getCourierByID = `-- name: GetCourierByID :one
+SELECT id, trip_id, product_id, vehicle_id, fleet_id, user_id, rating, points, tier, ST_AsGeoJSON(location) AS location FROM
+couriers
+WHERE id = $1
+LIMIT 1
+`
+
+type GetCourierByIDRow struct {
+	ID        uuid.UUID       `json:"id"`
+	TripID    uuid.NullUUID   `json:"trip_id"`
+	ProductID uuid.NullUUID   `json:"product_id"`
+	VehicleID uuid.NullUUID   `json:"vehicle_id"`
+	FleetID   uuid.NullUUID   `json:"fleet_id"`
+	UserID    uuid.NullUUID   `json:"user_id"`
+	Rating    sql.NullFloat64 `json:"rating"`
+	Points    int32           `json:"points"`
+	Tier      string          `json:"tier"`
+	Location  interface{}     `json:"location"`
+}
+
+func (q *Queries) GetCourierByID(ctx context.Context, id uuid.UUID) (GetCourierByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierByID, id)
+	var i GetCourierByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.ProductID,
+		&i.VehicleID,
+		&i.FleetID,
+		&i.UserID,
+		&i.Rating,
+		&i.Points,
+		&i.Tier,
+		&i.Location,
+	)
+	return i, err
+}
+
+const getCourierByPhone = `-- name: GetCourierByPhone :one
+SELECT c.id, c.fleet_id FROM couriers c
+JOIN users u ON u.id = c.user_id
+WHERE u.phone = $1
+LIMIT 1
+`
+
+type GetCourierByPhoneRow struct {
+	ID      uuid.UUID     `json:"id"`
+	FleetID uuid.NullUUID `json:"fleet_id"`
+}
+
+func (q *Queries) GetCourierByPhone(ctx context.Context, phone string) (GetCourierByPhoneRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierByPhone, phone)
+	var i GetCourierByPhoneRow
+	err := row.Scan(&i.ID, &i.FleetID)
+	return i, err
+}
+
+const getCourierByUserID = `-- name: GetCourierByUserID :one
+SELECT id, user_id, product_id, vehicle_id, rating, points, tier, ST_AsGeoJSON(location) AS location FROM
+couriers
+WHERE user_id = $1
+LIMIT 1
+`
+
+type GetCourierByUserIDRow struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.NullUUID   `json:"user_id"`
+	ProductID uuid.NullUUID   `json:"product_id"`
+	VehicleID uuid.NullUUID   `json:"vehicle_id"`
+	Rating    sql.NullFloat64 `json:"rating"`
+	Points    int32           `json:"points"`
+	Tier      string          `json:"tier"`
+	Location  interface{}     `json:"location"`
+}
+
+func (q *Queries) GetCourierByUserID(ctx context.Context, userID uuid.NullUUID) (GetCourierByUserIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierByUserID, userID)
+	var i GetCourierByUserIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.VehicleID,
+		&i.Rating,
+		&i.Points,
+		&i.Tier,
+		&i.Location,
+	)
+	return i, err
+}
+
+const getCourierCompletedTrips = `-- name: GetCourierCompletedTrips :one
+SELECT COUNT(*) FROM trips
+WHERE courier_id = $1 AND status = 'COMPLETE'
+`
+
+func (q *Queries) GetCourierCompletedTrips(ctx context.Context, courierID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getCourierCompletedTrips, courierID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getCourierEarningsBetween = `-- name: GetCourierEarningsBetween :one
+SELECT COALESCE(SUM(e.amount), 0)::integer AS earned FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1 AND a.kind = 'EARNINGS' AND t.kind IN ('TRIP_FARE', 'COMMISSION')
+AND e.created_at >= $2 AND e.created_at < $3
+`
+
+type GetCourierEarningsBetweenParams struct {
+	UserID  uuid.NullUUID `json:"user_id"`
+	StartAt time.Time     `json:"start_at"`
+	EndAt   time.Time     `json:"end_at"`
+}
+
+func (q *Queries) GetCourierEarningsBetween(ctx context.Context, arg GetCourierEarningsBetweenParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getCourierEarningsBetween, arg.UserID, arg.StartAt, arg.EndAt)
+	var earned int32
+	err := row.Scan(&earned)
+	return earned, err
+}
+
+const getCourierEligibility = `-- name: GetCourierEligibility :one
+SELECT c.id, c.status, c.verified, c.vehicle_id, v.status AS vehicle_status, (v.fleet_id IS NULL OR v.fleet_approved_at IS NOT NULL)::BOOLEAN AS fleet_approved, c.location_updated_at FROM couriers c
+LEFT JOIN vehicles v ON v.id = c.vehicle_id
+WHERE c.user_id = $1
+LIMIT 1
+`
+
+type GetCourierEligibilityRow struct {
+	ID                uuid.UUID      `json:"id"`
+	Status            string         `json:"status"`
+	Verified          sql.NullBool   `json:"verified"`
+	VehicleID         uuid.NullUUID  `json:"vehicle_id"`
+	VehicleStatus     sql.NullString `json:"vehicle_status"`
+	FleetApproved     bool           `json:"fleet_approved"`
+	LocationUpdatedAt sql.NullTime   `json:"location_updated_at"`
+}
+
+func (q *Queries) GetCourierEligibility(ctx context.Context, userID uuid.NullUUID) (GetCourierEligibilityRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierEligibility, userID)
+	var i GetCourierEligibilityRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Verified,
+		&i.VehicleID,
+		&i.VehicleStatus,
+		&i.FleetApproved,
+		&i.LocationUpdatedAt,
+	)
+	return i, err
+}
+
+const getCourierFleetInvitationForUpdate = `-- name: GetCourierFleetInvitationForUpdate :one
+SELECT i.id, i.fleet_id, i.courier_id, i.invited_by, i.status, i.responded_at, i.created_at, i.updated_at FROM fleet_invitations i
+JOIN couriers c ON c.id = i.courier_id
+WHERE i.id = $1 AND c.user_id = $2
+LIMIT 1
+FOR UPDATE OF i
+`
+
+type GetCourierFleetInvitationForUpdateParams struct {
+	ID     uuid.UUID     `json:"id"`
+	UserID uuid.NullUUID `json:"user_id"`
+}
+
+func (q *Queries) GetCourierFleetInvitationForUpdate(ctx context.Context, arg GetCourierFleetInvitationForUpdateParams) (FleetInvitation, error) {
+	row := q.db.QueryRowContext(ctx, getCourierFleetInvitationForUpdate, arg.ID, arg.UserID)
+	var i FleetInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.FleetID,
+		&i.CourierID,
+		&i.InvitedBy,
+		&i.Status,
+		&i.RespondedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCourierFleetInvitations = `-- name: GetCourierFleetInvitations :many
+SELECT i.id, i.fleet_id, f.name AS fleet_name, f.rider_share, i.status, i.created_at FROM fleet_invitations i
+JOIN fleets f ON f.id = i.fleet_id
+JOIN couriers c ON c.id = i.courier_id
+WHERE c.user_id = $1 AND i.status = 'PENDING'
+ORDER BY i.created_at DESC
+`
+
+type GetCourierFleetInvitationsRow struct {
+	ID         uuid.UUID `json:"id"`
+	FleetID    uuid.UUID `json:"fleet_id"`
+	FleetName  string    `json:"fleet_name"`
+	RiderShare int32     `json:"rider_share"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetCourierFleetInvitations(ctx context.Context, userID uuid.NullUUID) ([]GetCourierFleetInvitationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierFleetInvitations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCourierFleetInvitationsRow{}
+	for rows.Next() {
+		var i GetCourierFleetInvitationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FleetID,
+			&i.FleetName,
+			&i.RiderShare,
+			&i.Status,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierLocation = `-- name: GetCourierLocation :one
+SELECT ST_AsGeoJSON(location) AS location FROM
+couriers
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCourierLocation(ctx context.Context, id uuid.UUID) (interface{}, error) {
+	row := q.db.QueryRowContext(ctx, getCourierLocation, id)
+	var location interface{}
+	err := row.Scan(&location)
+	return location, err
+}
+
+const getCourierNearPickupPoint = `-- name: GetCourierNearPickupPoint :many
+SELECT id, product_id FROM couriers
+WHERE id = ANY($1::uuid[]) AND status = 'ONLINE' AND verified = 'true'
+`
+
+type GetCourierNearPickupPointRow struct {
+	ID        uuid.UUID     `json:"id"`
+	ProductID uuid.NullUUID `json:"product_id"`
+}
+
+func (q *Queries) GetCourierNearPickupPoint(ctx context.Context, ids []uuid.UUID) ([]GetCourierNearPickupPointRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierNearPickupPoint, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCourierNearPickupPointRow{}
+	for rows.Next() {
+		var i GetCourierNearPickupPointRow
+		if err := rows.Scan(&i.ID, &i.ProductID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierOnboarding = `-- name: GetCourierOnboarding :one
+SELECT c.id, c.product_id, c.vehicle_id, c.fleet_id, c.trip_id, c.status, c.verified, v.status AS vehicle_status, (v.fleet_id IS NULL OR v.fleet_approved_at IS NOT NULL)::BOOLEAN AS fleet_approved FROM couriers c
+LEFT JOIN vehicles v ON v.id = c.vehicle_id
+WHERE c.id = $1
+LIMIT 1
+`
+
+type GetCourierOnboardingRow struct {
+	ID            uuid.UUID      `json:"id"`
+	ProductID     uuid.NullUUID  `json:"product_id"`
+	VehicleID     uuid.NullUUID  `json:"vehicle_id"`
+	FleetID       uuid.NullUUID  `json:"fleet_id"`
+	TripID        uuid.NullUUID  `json:"trip_id"`
+	Status        string         `json:"status"`
+	Verified      sql.NullBool   `json:"verified"`
+	VehicleStatus sql.NullString `json:"vehicle_status"`
+	FleetApproved bool           `json:"fleet_approved"`
+}
+
+func (q *Queries) GetCourierOnboarding(ctx context.Context, id uuid.UUID) (GetCourierOnboardingRow, error) {
+	row := q.db.QueryRowContext(ctx, getCourierOnboarding, id)
+	var i GetCourierOnboardingRow
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.VehicleID,
+		&i.FleetID,
+		&i.TripID,
+		&i.Status,
+		&i.Verified,
+		&i.VehicleStatus,
+		&i.FleetApproved,
+	)
+	return i, err
+}
+
+const getCourierOnlineSecondsBetween = `-- name: GetCourierOnlineSecondsBetween :one
+WITH spans AS (
+  SELECT to_status, created_at AS started_at,
+    LEAD(created_at, 1, $1::timestamp) OVER (ORDER BY created_at) AS ended_at
+  FROM courier_status_transitions t
+  WHERE t.courier_id = $3 AND t.created_at < $1::timestamp
+  AND t.created_at >= COALESCE((
+    SELECT MAX(p.created_at) FROM courier_status_transitions p
+    WHERE p.courier_id = $3 AND p.created_at <= $2::timestamp
+  ), $2::timestamp)
+)
+SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (
+  LEAST(ended_at, $1::timestamp) - GREATEST(started_at, $2::timestamp)
+))), 0)::bigint AS online_seconds FROM spans
+WHERE to_status IN ('ONLINE', 'BUSY') AND ended_at > $2::timestamp
+`
+
+type GetCourierOnlineSecondsBetweenParams struct {
+	EndAt     time.Time `json:"end_at"`
+	StartAt   time.Time `json:"start_at"`
+	CourierID uuid.UUID `json:"courier_id"`
+}
+
+func (q *Queries) GetCourierOnlineSecondsBetween(ctx context.Context, arg GetCourierOnlineSecondsBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getCourierOnlineSecondsBetween, arg.EndAt, arg.StartAt, arg.CourierID)
+	var online_seconds int64
+	err := row.Scan(&online_seconds)
+	return online_seconds, err
+}
+
+const getCourierPoints = `-- name: GetCourierPoints :many
+SELECT id, courier_id, trip_id, reason, points, created_at FROM courier_points
+WHERE courier_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type GetCourierPointsParams struct {
+	CourierID uuid.UUID `json:"courier_id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) GetCourierPoints(ctx context.Context, arg GetCourierPointsParams) ([]CourierPoint, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierPoints, arg.CourierID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CourierPoint{}
+	for rows.Next() {
+		var i CourierPoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourierID,
+			&i.TripID,
+			&i.Reason,
+			&i.Points,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierQuests = `-- name: GetCourierQuests :many
+SELECT q.id, q.name, q.description, q.target_trips, q.reward, q.zone_id, q.product_id, q.tier, q.starts_at, q.ends_at, q.active, q.created_at, q.updated_at, COALESCE(p.trips, 0)::integer AS progress, p.completed_at FROM quests q
+LEFT JOIN quest_progress p ON p.quest_id = q.id AND p.courier_id = $1
+WHERE q.active AND q.ends_at > NOW()
+AND (q.product_id IS NULL OR q.product_id = $2)
+AND (q.tier IS NULL OR q.tier = $3)
+ORDER BY q.ends_at
+`
+
+type GetCourierQuestsParams struct {
+	CourierID uuid.UUID      `json:"courier_id"`
+	ProductID uuid.NullUUID  `json:"product_id"`
+	Tier      sql.NullString `json:"tier"`
+}
+
+type GetCourierQuestsRow struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	TargetTrips int32          `json:"target_trips"`
+	Reward      int32          `json:"reward"`
+	ZoneID      uuid.NullUUID  `json:"zone_id"`
+	ProductID   uuid.NullUUID  `json:"product_id"`
+	Tier        sql.NullString `json:"tier"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+	Active      bool           `json:"active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Progress    int32          `json:"progress"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+}
+
+func (q *Queries) GetCourierQuests(ctx context.Context, arg GetCourierQuestsParams) ([]GetCourierQuestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierQuests, arg.CourierID, arg.ProductID, arg.Tier)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCourierQuestsRow{}
+	for rows.Next() {
+		var i GetCourierQuestsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.TargetTrips,
+			&i.Reward,
+			&i.ZoneID,
+			&i.ProductID,
+			&i.Tier,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Progress,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierShiftBookings = `-- name: GetCourierShiftBookings :many
+SELECT b.id, b.shift_id, b.courier_id, b.status, b.checked_in_at, b.cancelled_at, b.earned, b.guarantee_paid, b.settled_at, b.created_at, b.updated_at, s.id, s.zone_id, s.starts_at, s.ends_at, s.capacity, s.guarantee, s.created_at, s.updated_at, z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings o
+  WHERE o.shift_id = s.id AND o.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+JOIN zones z ON z.id = s.zone_id
+WHERE b.courier_id = $1
+ORDER BY s.starts_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetCourierShiftBookingsParams struct {
+	CourierID uuid.UUID `json:"courier_id"`
+	Limit     int32     `json:"limit"`
+	Offset    int32     `json:"offset"`
+}
+
+type GetCourierShiftBookingsRow struct {
+	ShiftBooking ShiftBooking `json:"shift_booking"`
+	Shift        Shift        `json:"shift"`
+	ZoneName     string       `json:"zone_name"`
+	Booked       int32        `json:"booked"`
+}
+
+func (q *Queries) GetCourierShiftBookings(ctx context.Context, arg GetCourierShiftBookingsParams) ([]GetCourierShiftBookingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierShiftBookings, arg.CourierID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCourierShiftBookingsRow{}
+	for rows.Next() {
+		var i GetCourierShiftBookingsRow
+		if err := rows.Scan(
+			&i.ShiftBooking.ID,
+			&i.ShiftBooking.ShiftID,
+			&i.ShiftBooking.CourierID,
+			&i.ShiftBooking.Status,
+			&i.ShiftBooking.CheckedInAt,
+			&i.ShiftBooking.CancelledAt,
+			&i.ShiftBooking.Earned,
+			&i.ShiftBooking.GuaranteePaid,
+			&i.ShiftBooking.SettledAt,
+			&i.ShiftBooking.CreatedAt,
+			&i.ShiftBooking.UpdatedAt,
+			&i.Shift.ID,
+			&i.Shift.ZoneID,
+			&i.Shift.StartsAt,
+			&i.Shift.EndsAt,
+			&i.Shift.Capacity,
+			&i.Shift.Guarantee,
+			&i.Shift.CreatedAt,
+			&i.Shift.UpdatedAt,
+			&i.ZoneName,
+			&i.Booked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierStatus = `-- name: GetCourierStatus :one
+SELECT status FROM
+couriers
+WHERE user_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCourierStatus(ctx context.Context, userID uuid.NullUUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCourierStatus, userID)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getCourierStatusForUpdate = `-- name: GetCourierStatusForUpdate :one
+SELECT status FROM couriers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetCourierStatusForUpdate(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCourierStatusForUpdate, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getCourierTrip = `-- name: GetCourierTrip :one
+SELECT id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id FROM trips
+WHERE id = $1 AND courier_id = $2
+LIMIT 1
+`
+
+type GetCourierTripParams struct {
+	ID        uuid.UUID     `json:"id"`
+	CourierID uuid.NullUUID `json:"courier_id"`
+}
+
+func (q *Queries) GetCourierTrip(ctx context.Context, arg GetCourierTripParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, getCourierTrip, arg.ID, arg.CourierID)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.StartLocation,
+		&i.EndLocation,
+		&i.ConfirmedPickup,
+		&i.CourierID,
+		&i.UserID,
+		&i.ProductID,
+		&i.Cost,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
+	)
+	return i, err
+}
+
+const getCourierTripID = `-- name: GetCourierTripID :one
+SELECT trip_id FROM couriers
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCourierTripID(ctx context.Context, id uuid.UUID) (uuid.NullUUID, error) {
+	row := q.db.QueryRowContext(ctx, getCourierTripID, id)
+	var trip_id uuid.NullUUID
+	err := row.Scan(&trip_id)
+	return trip_id, err
+}
+
+const getCourierUpload = `-- name: GetCourierUpload :one
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM
+uploads
+WHERE courier_id = $1 AND type = $2
+LIMIT 1
+`
+
+type GetCourierUploadParams struct {
+	CourierID uuid.NullUUID `json:"courier_id"`
+	Type      string        `json:"type"`
+}
+
+func (q *Queries) GetCourierUpload(ctx context.Context, arg GetCourierUploadParams) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, getCourierUpload, arg.CourierID, arg.Type)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Uri,
+		&i.Verification,
+		&i.CourierID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+	)
+	return i, err
+}
+
+const getCourierUploads = `-- name: GetCourierUploads :many
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM uploads
+WHERE courier_id = $1
+`
+
+func (q *Queries) GetCourierUploads(ctx context.Context, courierID uuid.NullUUID) ([]Upload, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierUploads, courierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Upload{}
+	for rows.Next() {
+		var i Upload
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Uri,
+			&i.Verification,
+			&i.CourierID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourierVehicles = `-- name: GetCourierVehicles :many
+SELECT id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at FROM vehicles
+WHERE courier_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetCourierVehicles(ctx context.Context, courierID uuid.UUID) ([]Vehicle, error) {
+	rows, err := q.db.QueryContext(ctx, getCourierVehicles, courierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Vehicle{}
+	for rows.Next() {
+		var i Vehicle
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourierID,
+			&i.ProductID,
+			&i.Type,
+			&i.Plate,
+			&i.Make,
+			&i.Colour,
+			&i.Capacity,
+			&i.Status,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FleetID,
+			&i.FleetApprovedBy,
+			&i.FleetApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCouriersHoldingCash = `-- name: GetCouriersHoldingCash :many
+SELECT c.id AS courier_id, u.id AS user_id, u.first_name, u.last_name, u.phone, -a.balance AS cash_held, COALESCE(e.balance, 0)::integer AS earnings, a.updated_at FROM ledger_accounts a
+JOIN users u ON u.id = a.user_id
+JOIN couriers c ON c.user_id = u.id
+LEFT JOIN ledger_accounts e ON e.user_id = u.id AND e.kind = 'EARNINGS'
+WHERE a.kind = 'CASH_HELD' AND -a.balance >= $1
+ORDER BY a.balance
+`
+
+type GetCouriersHoldingCashRow struct {
+	CourierID uuid.UUID `json:"courier_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Phone     string    `json:"phone"`
+	CashHeld  int32     `json:"cash_held"`
+	Earnings  int32     `json:"earnings"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) GetCouriersHoldingCash(ctx context.Context, minimum int32) ([]GetCouriersHoldingCashRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCouriersHoldingCash, minimum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCouriersHoldingCashRow{}
+	for rows.Next() {
+		var i GetCouriersHoldingCashRow
+		if err := rows.Scan(
+			&i.CourierID,
+			&i.UserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Phone,
+			&i.CashHeld,
+			&i.Earnings,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDuePayoutVerifications = `-- name: GetDuePayoutVerifications :many
+SELECT id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id FROM payouts
+WHERE status = 'VERIFYING' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at
+`
+
+func (q *Queries) GetDuePayoutVerifications(ctx context.Context) ([]Payout, error) {
+	rows, err := q.db.QueryContext(ctx, getDuePayoutVerifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payout{}
+	for rows.Next() {
+		var i Payout
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourierID,
+			&i.UserID,
+			&i.Amount,
+			&i.Phone,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.OriginatorConversationID,
+			&i.ConversationID,
+			&i.Receipt,
+			&i.ResultCode,
+			&i.ResultDesc,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StatusConversationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDuePayouts = `-- name: GetDuePayouts :many
+SELECT id FROM payouts
+WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at
+`
+
+func (q *Queries) GetDuePayouts(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getDuePayouts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEligibleQuests = `-- name: GetEligibleQuests :many
+SELECT id, name, description, target_trips, reward, zone_id, product_id, tier, starts_at, ends_at, active, created_at, updated_at FROM quests
+WHERE active AND starts_at <= $1 AND ends_at > $1
+AND (product_id IS NULL OR product_id = $2)
+AND (zone_id IS NULL OR zone_id = $3)
+AND (tier IS NULL OR tier = $4)
+`
+
+type GetEligibleQuestsParams struct {
+	At        time.Time      `json:"at"`
+	ProductID uuid.NullUUID  `json:"product_id"`
+	ZoneID    uuid.NullUUID  `json:"zone_id"`
+	Tier      sql.NullString `json:"tier"`
+}
+
+func (q *Queries) GetEligibleQuests(ctx context.Context, arg GetEligibleQuestsParams) ([]Quest, error) {
+	rows, err := q.db.QueryContext(ctx, getEligibleQuests,
+		arg.At,
+		arg.ProductID,
+		arg.ZoneID,
+		arg.Tier,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Quest{}
+	for rows.Next() {
+		var i Quest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.TargetTrips,
+			&i.Reward,
+			&i.ZoneID,
+			&i.ProductID,
+			&i.Tier,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEndedShiftBookings = `-- name: GetEndedShiftBookings :many
+SELECT b.id FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+WHERE b.status IN ('BOOKED', 'CHECKED_IN') AND s.ends_at <= $1
+ORDER BY s.ends_at
+`
+
+func (q *Queries) GetEndedShiftBookings(ctx context.Context, endsAt time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getEndedShiftBookings, endsAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiringCourierUploads = `-- name: GetExpiringCourierUploads :many
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM uploads
+WHERE courier_id IS NOT NULL AND verification = 'VERIFIED' AND expiry_warned_at IS NULL
+AND expires_at > $1 AND expires_at <= $2
+ORDER BY expires_at
+`
+
+type GetExpiringCourierUploadsParams struct {
+	Now        sql.NullTime `json:"now"`
+	WarnBefore sql.NullTime `json:"warn_before"`
+}
+
+func (q *Queries) GetExpiringCourierUploads(ctx context.Context, arg GetExpiringCourierUploadsParams) ([]Upload, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiringCourierUploads, arg.Now, arg.WarnBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Upload{}
+	for rows.Next() {
+		var i Upload
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Uri,
+			&i.Verification,
+			&i.CourierID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFailedPayouts = `-- name: GetFailedPayouts :many
+SELECT id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id FROM payouts
+WHERE status = 'FAILED'
+ORDER BY updated_at DESC
+LIMIT $1
+OFFSET $2
+`
+
+type GetFailedPayoutsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) GetFailedPayouts(ctx context.Context, arg GetFailedPayoutsParams) ([]Payout, error) {
+	rows, err := q.db.QueryContext(ctx, getFailedPayouts, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payout{}
+	for rows.Next() {
+		var i Payout
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourierID,
+			&i.UserID,
+			&i.Amount,
+			&i.Phone,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.OriginatorConversationID,
+			&i.ConversationID,
+			&i.Receipt,
+			&i.ResultCode,
+			&i.ResultDesc,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StatusConversationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFleet = `-- name: GetFleet :one
+SELECT id, name, owner_id, rider_share, created_at, updated_at FROM fleets
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetFleet(ctx context.Context, id uuid.UUID) (Fleet, error) {
+	row := q.db.QueryRowContext(ctx, getFleet, id)
+	var i Fleet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.RiderShare,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFleetPendingVehicles = `-- name: GetFleetPendingVehicles :many
+SELECT v.id, v.courier_id, v.product_id, v.type, v.plate, v.make, v.colour, v.capacity, v.status, v.rejection_reason, v.reviewed_by, v.reviewed_at, v.created_at, v.updated_at, v.fleet_id, v.fleet_approved_by, v.fleet_approved_at, c.vehicle_id AS active_vehicle_id FROM vehicles v
+JOIN couriers c ON c.id = v.courier_id
+WHERE v.fleet_id = $1 AND v.fleet_approved_at IS NULL AND v.status <> 'REJECTED'
+ORDER BY v.created_at
+`
+
+type GetFleetPendingVehiclesRow struct {
+	Vehicle         Vehicle       `json:"vehicle"`
+	ActiveVehicleID uuid.NullUUID `json:"active_vehicle_id"`
+}
+
+func (q *Queries) GetFleetPendingVehicles(ctx context.Context, fleetID uuid.NullUUID) ([]GetFleetPendingVehiclesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFleetPendingVehicles, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFleetPendingVehiclesRow{}
+	for rows.Next() {
+		var i GetFleetPendingVehiclesRow
+		if err := rows.Scan(
+			&i.Vehicle.ID,
+			&i.Vehicle.CourierID,
+			&i.Vehicle.ProductID,
+			&i.Vehicle.Type,
+			&i.Vehicle.Plate,
+			&i.Vehicle.Make,
+			&i.Vehicle.Colour,
+			&i.Vehicle.Capacity,
+			&i.Vehicle.Status,
+			&i.Vehicle.RejectionReason,
+			&i.Vehicle.ReviewedBy,
+			&i.Vehicle.ReviewedAt,
+			&i.Vehicle.CreatedAt,
+			&i.Vehicle.UpdatedAt,
+			&i.Vehicle.FleetID,
+			&i.Vehicle.FleetApprovedBy,
+			&i.Vehicle.FleetApprovedAt,
+			&i.ActiveVehicleID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFleetRiders = `-- name: GetFleetRiders :many
+SELECT c.id AS courier_id, c.user_id, u.first_name, u.last_name, u.phone, c.status, ST_AsGeoJSON(c.location) AS location, c.location_updated_at, c.trip_id, c.vehicle_id FROM couriers c
+JOIN users u ON u.id = c.user_id
+WHERE c.fleet_id = $1
+ORDER BY u.first_name, u.last_name
+`
+
+type GetFleetRidersRow struct {
+	CourierID         uuid.UUID     `json:"courier_id"`
+	UserID            uuid.NullUUID `json:"user_id"`
+	FirstName         string        `json:"first_name"`
+	LastName          string        `json:"last_name"`
+	Phone             string        `json:"phone"`
+	Status            string        `json:"status"`
+	Location          interface{}   `json:"location"`
+	LocationUpdatedAt sql.NullTime  `json:"location_updated_at"`
+	TripID            uuid.NullUUID `json:"trip_id"`
+	VehicleID         uuid.NullUUID `json:"vehicle_id"`
+}
+
+func (q *Queries) GetFleetRiders(ctx context.Context, fleetID uuid.NullUUID) ([]GetFleetRidersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFleetRiders, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFleetRidersRow{}
+	for rows.Next() {
+		var i GetFleetRidersRow
+		if err := rows.Scan(
+			&i.CourierID,
+			&i.UserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Phone,
+			&i.Status,
+			&i.Location,
+			&i.LocationUpdatedAt,
+			&i.TripID,
+			&i.VehicleID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFleetTrips = `-- name: GetFleetTrips :many
+SELECT t.id, t.status, t.courier_id, t.user_id, t.cost, t.distance, t.duration, t.discount, t.product_id, t.collect_amount, t.created_at, ST_AsGeoJSON(t.confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(t.start_location) AS start_location, ST_AsGeoJSON(t.end_location) AS end_location FROM trips t
+WHERE t.fleet_id = $1
+ORDER BY t.created_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetFleetTripsParams struct {
+	FleetID uuid.NullUUID `json:"fleet_id"`
+	Limit   int32         `json:"limit"`
+	Offset  int32         `json:"offset"`
+}
+
+type GetFleetTripsRow struct {
+	ID              uuid.UUID     `json:"id"`
+	Status          string        `json:"status"`
+	CourierID       uuid.NullUUID `json:"courier_id"`
+	UserID          uuid.UUID     `json:"user_id"`
+	Cost            int32         `json:"cost"`
+	Distance        int32         `json:"distance"`
+	Duration        int32         `json:"duration"`
+	Discount        int32         `json:"discount"`
+	ProductID       uuid.UUID     `json:"product_id"`
+	CollectAmount   int32         `json:"collect_amount"`
+	CreatedAt       time.Time     `json:"created_at"`
+	ConfirmedPickup interface{}   `json:"confirmed_pickup"`
+	StartLocation   interface{}   `json:"start_location"`
+	EndLocation     interface{}   `json:"end_location"`
+}
+
+func (q *Queries) GetFleetTrips(ctx context.Context, arg GetFleetTripsParams) ([]GetFleetTripsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFleetTrips, arg.FleetID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFleetTripsRow{}
+	for rows.Next() {
+		var i GetFleetTripsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.CourierID,
+			&i.UserID,
+			&i.Cost,
+			&i.Distance,
+			&i.Duration,
+			&i.Discount,
+			&i.ProductID,
+			&i.CollectAmount,
+			&i.CreatedAt,
+			&i.ConfirmedPickup,
+			&i.StartLocation,
+			&i.EndLocation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManagedFleets = `-- name: GetManagedFleets :many
+SELECT f.id, f.name, f.owner_id, f.rider_share, f.created_at, f.updated_at FROM fleets f
+JOIN fleet_managers m ON m.fleet_id = f.id
+WHERE m.user_id = $1
+ORDER BY f.name
+`
+
+func (q *Queries) GetManagedFleets(ctx context.Context, userID uuid.UUID) ([]Fleet, error) {
+	rows, err := q.db.QueryContext(ctx, getManagedFleets, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Fleet{}
+	for rows.Next() {
+		var i Fleet
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.RiderShare,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNearbyAvailableCourierProducts = `-- name: GetNearbyAvailableCourierProducts :many
+SELECT c.id, c.product_id, p.id, p.name, p.description, p.weight_class, p.icon, p.relevance, p.created_at, p.updated_at FROM couriers c
+JOIN products p
+ON ST_DWithin(c.location, $1::geography, $2)
+WHERE c.product_id = p.id AND c.verified = 'true'
+ORDER BY p.relevance ASC
+`
+
+type GetNearbyAvailableCourierProductsParams struct {
+	Point  interface{} `json:"point"`
+	Radius interface{} `json:"radius"`
+}
+
+type GetNearbyAvailableCourierProductsRow struct {
+	ID          uuid.UUID     `json:"id"`
+	ProductID   uuid.NullUUID `json:"product_id"`
+	ID_2        uuid.UUID     `json:"id_2"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	WeightClass int32         `json:"weight_class"`
+	Icon        string        `json:"icon"`
+	Relevance   int32         `json:"relevance"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (q *Queries) GetNearbyAvailableCourierProducts(ctx context.Context, arg GetNearbyAvailableCourierProductsParams) ([]GetNearbyAvailableCourierProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNearbyAvailableCourierProducts, arg.Point, arg.Radius)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetNearbyAvailableCourierProductsRow{}
+	for rows.Next() {
+		var i GetNearbyAvailableCourierProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.ID_2,
+			&i.Name,
+			&i.Description,
+			&i.WeightClass,
+			&i.Icon,
+			&i.Relevance,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPaidTripPaymentForUpdate = `-- name: GetPaidTripPaymentForUpdate :one
+SELECT id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id FROM payments
+WHERE trip_id = $1 AND purpose = 'TRIP' AND status = 'PAID'
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPaidTripPaymentForUpdate(ctx context.Context, tripID uuid.NullUUID) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaidTripPaymentForUpdate, tripID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const getPaymentByCheckoutIDForUpdate = `-- name: GetPaymentByCheckoutIDForUpdate :one
+SELECT id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id FROM payments
+WHERE checkout_request_id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPaymentByCheckoutIDForUpdate(ctx context.Context, checkoutRequestID sql.NullString) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByCheckoutIDForUpdate, checkoutRequestID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const getPaymentByReferenceForUpdate = `-- name: GetPaymentByReferenceForUpdate :one
+SELECT id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id FROM payments
+WHERE reference = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPaymentByReferenceForUpdate(ctx context.Context, reference sql.NullString) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByReferenceForUpdate, reference)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const getPayoutByOriginatorIDForUpdate = `-- name: GetPayoutByOriginatorIDForUpdate :one
+SELECT id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id FROM payouts
+WHERE originator_conversation_id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPayoutByOriginatorIDForUpdate(ctx context.Context, originatorConversationID sql.NullString) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, getPayoutByOriginatorIDForUpdate, originatorConversationID)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.UserID,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatusConversationID,
+	)
+	return i, err
+}
+
+const getPayoutByStatusConversationForUpdate = `-- name: GetPayoutByStatusConversationForUpdate :one
+SELECT id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id FROM payouts
+WHERE status_conversation_id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPayoutByStatusConversationForUpdate(ctx context.Context, statusConversationID sql.NullString) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, getPayoutByStatusConversationForUpdate, statusConversationID)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.UserID,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatusConversationID,
+	)
+	return i, err
+}
+
+const getPayoutCandidates = `-- name: GetPayoutCandidates :many
+SELECT c.id AS courier_id, u.id AS user_id, u.phone FROM ledger_accounts a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN couriers c ON c.user_id = u.id
+WHERE a.kind = 'EARNINGS' AND a.balance >= $1
+AND NOT EXISTS (
+  SELECT 1 FROM payouts p
+  WHERE p.user_id = u.id AND p.status IN ('PENDING', 'PROCESSING', 'VERIFYING')
+)
+`
+
+type GetPayoutCandidatesRow struct {
+	CourierID uuid.NullUUID `json:"courier_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Phone     string        `json:"phone"`
+}
+
+// Couriers and fleet owners alike are paid their earnings
+func (q *Queries) GetPayoutCandidates(ctx context.Context, minimum int32) ([]GetPayoutCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPayoutCandidates, minimum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPayoutCandidatesRow{}
+	for rows.Next() {
+		var i GetPayoutCandidatesRow
+		if err := rows.Scan(&i.CourierID, &i.UserID, &i.Phone); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPayoutForUpdate = `-- name: GetPayoutForUpdate :one
+SELECT id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id FROM payouts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPayoutForUpdate(ctx context.Context, id uuid.UUID) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, getPayoutForUpdate, id)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.UserID,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatusConversationID,
+	)
+	return i, err
+}
+
+const getPendingCourierUploads = `-- name: GetPendingCourierUploads :many
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM uploads
+WHERE courier_id IS NOT NULL AND verification = 'VERIFYING'
+ORDER BY updated_at
+LIMIT $1
+OFFSET $2
+`
+
+type GetPendingCourierUploadsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) GetPendingCourierUploads(ctx context.Context, arg GetPendingCourierUploadsParams) ([]Upload, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingCourierUploads, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Upload{}
+	for rows.Next() {
+		var i Upload
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Uri,
+			&i.Verification,
+			&i.CourierID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RejectionReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingReferral = `-- name: GetPendingReferral :one
+SELECT id, referrer_id, referee_id, kind, status, device_id, rewarded_at, created_at, updated_at FROM referrals
+WHERE referee_id = $1 AND kind = $2 AND status = 'PENDING'
+LIMIT 1
+`
+
+type GetPendingReferralParams struct {
+	RefereeID uuid.UUID `json:"referee_id"`
+	Kind      string    `json:"kind"`
+}
+
+func (q *Queries) GetPendingReferral(ctx context.Context, arg GetPendingReferralParams) (Referral, error) {
+	row := q.db.QueryRowContext(ctx, getPendingReferral, arg.RefereeID, arg.Kind)
+	var i Referral
+	err := row.Scan(
+		&i.ID,
+		&i.ReferrerID,
+		&i.RefereeID,
+		&i.Kind,
+		&i.Status,
+		&i.DeviceID,
+		&i.RewardedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPendingVehicles = `-- name: GetPendingVehicles :many
+SELECT v.id, v.courier_id, v.product_id, v.type, v.plate, v.make, v.colour, v.capacity, v.status, v.rejection_reason, v.reviewed_by, v.reviewed_at, v.created_at, v.updated_at, v.fleet_id, v.fleet_approved_by, v.fleet_approved_at, c.vehicle_id AS active_vehicle_id FROM vehicles v
+JOIN couriers c ON c.id = v.courier_id
+WHERE v.status = 'PENDING'
+ORDER BY v.created_at
+LIMIT $1
+OFFSET $2
+`
+
+type GetPendingVehiclesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type GetPendingVehiclesRow struct {
+	Vehicle         Vehicle       `json:"vehicle"`
+	ActiveVehicleID uuid.NullUUID `json:"active_vehicle_id"`
+}
+
+func (q *Queries) GetPendingVehicles(ctx context.Context, arg GetPendingVehiclesParams) ([]GetPendingVehiclesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingVehicles, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPendingVehiclesRow{}
+	for rows.Next() {
+		var i GetPendingVehiclesRow
+		if err := rows.Scan(
+			&i.Vehicle.ID,
+			&i.Vehicle.CourierID,
+			&i.Vehicle.ProductID,
+			&i.Vehicle.Type,
+			&i.Vehicle.Plate,
+			&i.Vehicle.Make,
+			&i.Vehicle.Colour,
+			&i.Vehicle.Capacity,
+			&i.Vehicle.Status,
+			&i.Vehicle.RejectionReason,
+			&i.Vehicle.ReviewedBy,
+			&i.Vehicle.ReviewedAt,
+			&i.Vehicle.CreatedAt,
+			&i.Vehicle.UpdatedAt,
+			&i.Vehicle.FleetID,
+			&i.Vehicle.FleetApprovedBy,
+			&i.Vehicle.FleetApprovedAt,
+			&i.ActiveVehicleID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlatformLedgerAccount = `-- name: GetPlatformLedgerAccount :one
+SELECT id, user_id, kind, balance, created_at, updated_at FROM ledger_accounts
+WHERE user_id IS NULL AND kind = $1
+LIMIT 1
+`
+
+func (q *Queries) GetPlatformLedgerAccount(ctx context.Context, kind string) (LedgerAccount, error) {
+	row := q.db.QueryRowContext(ctx, getPlatformLedgerAccount, kind)
+	var i LedgerAccount
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Balance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getProductByID = `-- name: GetProductByID :one
+SELECT id, icon, name, weight_class FROM products
+WHERE id = $1
+LIMIT 1
+`
+
+type GetProductByIDRow struct {
+	ID          uuid.UUID `json:"id"`
+	Icon        string    `json:"icon"`
+	Name        string    `json:"name"`
+	WeightClass int32     `json:"weight_class"`
+}
+
+func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductByID, id)
+	var i GetProductByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Icon,
+		&i.Name,
+		&i.WeightClass,
+	)
+	return i, err
+}
+
+const getProductDocuments = `-- name: GetProductDocuments :many
+SELECT type FROM product_documents
+WHERE product_id = $1
+ORDER BY position
+`
+
+func (q *Queries) GetProductDocuments(ctx context.Context, productID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getProductDocuments, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var type_ string
+		if err := rows.Scan(&type_); err != nil {
+			return nil, err
+		}
+		items = append(items, type_)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProducts = `-- name: GetProducts :many
+SELECT id, name, description, weight_class, icon, relevance, created_at, updated_at FROM products
+ORDER BY relevance
+`
+
+func (q *Queries) GetProducts(ctx context.Context) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.WeightClass,
+			&i.Icon,
+			&i.Relevance,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPromotionByCode = `-- name: GetPromotionByCode :one
+SELECT id, code, description, discount_type, discount_value, max_discount, per_user_limit, budget, budget_used, first_trip_only, funded_by, product_id, zone_id, starts_at, ends_at, active, created_at, updated_at FROM promotions
+WHERE code = $1
+LIMIT 1
+`
+
+func (q *Queries) GetPromotionByCode(ctx context.Context, code string) (Promotion, error) {
+	row := q.db.QueryRowContext(ctx, getPromotionByCode, code)
+	var i Promotion
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MaxDiscount,
+		&i.PerUserLimit,
+		&i.Budget,
+		&i.BudgetUsed,
+		&i.FirstTripOnly,
+		&i.FundedBy,
+		&i.ProductID,
+		&i.ZoneID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPromotionForUpdate = `-- name: GetPromotionForUpdate :one
+SELECT id, code, description, discount_type, discount_value, max_discount, per_user_limit, budget, budget_used, first_trip_only, funded_by, product_id, zone_id, starts_at, ends_at, active, created_at, updated_at FROM promotions
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPromotionForUpdate(ctx context.Context, id uuid.UUID) (Promotion, error) {
+	row := q.db.QueryRowContext(ctx, getPromotionForUpdate, id)
+	var i Promotion
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MaxDiscount,
+		&i.PerUserLimit,
+		&i.Budget,
+		&i.BudgetUsed,
+		&i.FirstTripOnly,
+		&i.FundedBy,
+		&i.ProductID,
+		&i.ZoneID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT id, ip, user_agent, phone, created_at, updated_at FROM sessions
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, getSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Ip,
+		&i.UserAgent,
+		&i.Phone,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getShiftBooking = `-- name: GetShiftBooking :one
+SELECT b.id, b.shift_id, b.courier_id, b.status, b.checked_in_at, b.cancelled_at, b.earned, b.guarantee_paid, b.settled_at, b.created_at, b.updated_at, s.id, s.zone_id, s.starts_at, s.ends_at, s.capacity, s.guarantee, s.created_at, s.updated_at, z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings o
+  WHERE o.shift_id = s.id AND o.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shift_bookings b
+JOIN shifts s ON s.id = b.shift_id
+JOIN zones z ON z.id = s.zone_id
+WHERE b.id = $1
+LIMIT 1
+`
+
+type GetShiftBookingRow struct {
+	ShiftBooking ShiftBooking `json:"shift_booking"`
+	Shift        Shift        `json:"shift"`
+	ZoneName     string       `json:"zone_name"`
+	Booked       int32        `json:"booked"`
+}
+
+func (q *Queries) GetShiftBooking(ctx context.Context, id uuid.UUID) (GetShiftBookingRow, error) {
+	row := q.db.QueryRowContext(ctx, getShiftBooking, id)
+	var i GetShiftBookingRow
+	err := row.Scan(
+		&i.ShiftBooking.ID,
+		&i.ShiftBooking.ShiftID,
+		&i.ShiftBooking.CourierID,
+		&i.ShiftBooking.Status,
+		&i.ShiftBooking.CheckedInAt,
+		&i.ShiftBooking.CancelledAt,
+		&i.ShiftBooking.Earned,
+		&i.ShiftBooking.GuaranteePaid,
+		&i.ShiftBooking.SettledAt,
+		&i.ShiftBooking.CreatedAt,
+		&i.ShiftBooking.UpdatedAt,
+		&i.Shift.ID,
+		&i.Shift.ZoneID,
+		&i.Shift.StartsAt,
+		&i.Shift.EndsAt,
+		&i.Shift.Capacity,
+		&i.Shift.Guarantee,
+		&i.Shift.CreatedAt,
+		&i.Shift.UpdatedAt,
+		&i.ZoneName,
+		&i.Booked,
+	)
+	return i, err
+}
+
+const getShiftBookingForUpdate = `-- name: GetShiftBookingForUpdate :one
+SELECT id, shift_id, courier_id, status, checked_in_at, cancelled_at, earned, guarantee_paid, settled_at, created_at, updated_at FROM shift_bookings
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetShiftBookingForUpdate(ctx context.Context, id uuid.UUID) (ShiftBooking, error) {
+	row := q.db.QueryRowContext(ctx, getShiftBookingForUpdate, id)
+	var i ShiftBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ShiftID,
+		&i.CourierID,
+		&i.Status,
+		&i.CheckedInAt,
+		&i.CancelledAt,
+		&i.Earned,
+		&i.GuaranteePaid,
+		&i.SettledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getShiftForUpdate = `-- name: GetShiftForUpdate :one
+SELECT id, zone_id, starts_at, ends_at, capacity, guarantee, created_at, updated_at FROM shifts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetShiftForUpdate(ctx context.Context, id uuid.UUID) (Shift, error) {
+	row := q.db.QueryRowContext(ctx, getShiftForUpdate, id)
+	var i Shift
+	err := row.Scan(
+		&i.ID,
+		&i.ZoneID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Guarantee,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTrip = `-- name: GetTrip :one
+SELECT id, status, courier_id, user_id, cost, distance, actual_distance, duration, promotion_id, discount, discount_funded_by, product_id, collect_amount, collected_amount, created_at, ST_AsGeoJSON(confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(start_location) AS start_location, ST_AsGeoJSON(end_location) AS end_location FROM trips
+WHERE id = $1
+LIMIT 1
+`
+
+type GetTripRow struct {
+	ID               uuid.UUID      `json:"id"`
+	Status           string         `json:"status"`
+	CourierID        uuid.NullUUID  `json:"courier_id"`
+	UserID           uuid.UUID      `json:"user_id"`
+	Cost             int32          `json:"cost"`
+	Distance         int32          `json:"distance"`
+	ActualDistance   sql.NullInt32  `json:"actual_distance"`
+	Duration         int32          `json:"duration"`
+	PromotionID      uuid.NullUUID  `json:"promotion_id"`
+	Discount         int32          `json:"discount"`
+	DiscountFundedBy sql.NullString `json:"discount_funded_by"`
+	ProductID        uuid.UUID      `json:"product_id"`
+	CollectAmount    int32          `json:"collect_amount"`
+	CollectedAmount  sql.NullInt32  `json:"collected_amount"`
+	CreatedAt        time.Time      `json:"created_at"`
+	ConfirmedPickup  interface{}    `json:"confirmed_pickup"`
+	StartLocation    interface{}    `json:"start_location"`
+	EndLocation      interface{}    `json:"end_location"`
+}
+
+func (q *Queries) GetTrip(ctx context.Context, id uuid.UUID) (GetTripRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrip, id)
+	var i GetTripRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.CourierID,
+		&i.UserID,
+		&i.Cost,
+		&i.Distance,
+		&i.ActualDistance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.ProductID,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.CreatedAt,
+		&i.ConfirmedPickup,
+		&i.StartLocation,
+		&i.EndLocation,
+	)
+	return i, err
+}
+
+const getTripDiscountForUpdate = `-- name: GetTripDiscountForUpdate :one
+SELECT discount, discount_funded_by FROM trips
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+type GetTripDiscountForUpdateRow struct {
+	Discount         int32          `json:"discount"`
+	DiscountFundedBy sql.NullString `json:"discount_funded_by"`
+}
+
+func (q *Queries) GetTripDiscountForUpdate(ctx context.Context, id uuid.UUID) (GetTripDiscountForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getTripDiscountForUpdate, id)
+	var i GetTripDiscountForUpdateRow
+	err := row.Scan(&i.Discount, &i.DiscountFundedBy)
+	return i, err
+}
+
+const getTripFleet = `-- name: GetTripFleet :one
+SELECT t.fleet_id, (v.fleet_id IS NOT NULL AND v.fleet_id = t.fleet_id)::BOOLEAN AS fleet_vehicle FROM trips t
+LEFT JOIN vehicles v ON v.id = t.vehicle_id
+WHERE t.id = $1
+LIMIT 1
+`
+
+type GetTripFleetRow struct {
+	FleetID      uuid.NullUUID `json:"fleet_id"`
+	FleetVehicle bool          `json:"fleet_vehicle"`
+}
+
+func (q *Queries) GetTripFleet(ctx context.Context, id uuid.UUID) (GetTripFleetRow, error) {
+	row := q.db.QueryRowContext(ctx, getTripFleet, id)
+	var i GetTripFleetRow
+	err := row.Scan(&i.FleetID, &i.FleetVehicle)
+	return i, err
+}
+
+const getTripHeldAmount = `-- name: GetTripHeldAmount :one
+SELECT COALESCE(SUM(e.amount), 0)::integer AS held FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE t.trip_id = $1 AND a.user_id = $2 AND a.kind = 'HOLD'
+`
+
+type GetTripHeldAmountParams struct {
+	TripID uuid.NullUUID `json:"trip_id"`
+	UserID uuid.NullUUID `json:"user_id"`
+}
+
+func (q *Queries) GetTripHeldAmount(ctx context.Context, arg GetTripHeldAmountParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getTripHeldAmount, arg.TripID, arg.UserID)
+	var held int32
+	err := row.Scan(&held)
+	return held, err
+}
+
+const getTripPath = `-- name: GetTripPath :one
+SELECT COUNT(*)::INTEGER AS points,
+  COALESCE(ST_Length(ST_MakeLine(location::geometry ORDER BY recorded_at)::geography), 0)::INTEGER AS distance,
+  COALESCE(ST_AsEncodedPolyline(ST_MakeLine(location::geometry ORDER BY recorded_at)), '')::TEXT AS polyline,
+  COALESCE(ST_AsGeoJSON(ST_MakeLine(location::geometry ORDER BY recorded_at)), '')::TEXT AS geojson
+FROM courier_locations
+WHERE trip_id = $1 AND recorded_at >= $2
+`
+
+type GetTripPathParams struct {
+	TripID uuid.NullUUID `json:"trip_id"`
+	Since  time.Time     `json:"since"`
+}
+
+type GetTripPathRow struct {
+	Points   int32  `json:"points"`
+	Distance int32  `json:"distance"`
+	Polyline string `json:"polyline"`
+	Geojson  string `json:"geojson"`
+}
+
+func (q *Queries) GetTripPath(ctx context.Context, arg GetTripPathParams) (GetTripPathRow, error) {
+	row := q.db.QueryRowContext(ctx, getTripPath, arg.TripID, arg.Since)
+	var i GetTripPathRow
+	err := row.Scan(
+		&i.Points,
+		&i.Distance,
+		&i.Polyline,
+		&i.Geojson,
+	)
+	return i, err
+}
+
+const getTripPayment = `-- name: GetTripPayment :one
+SELECT id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id FROM payments
+WHERE trip_id = $1 AND purpose = 'TRIP'
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetTripPayment(ctx context.Context, tripID uuid.NullUUID) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getTripPayment, tripID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const getTripRatings = `-- name: GetTripRatings :many
+SELECT id, trip_id, target, rater_id, ratee_id, rating, tags, comment, created_at FROM trip_ratings
+WHERE trip_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetTripRatings(ctx context.Context, tripID uuid.UUID) ([]TripRating, error) {
+	rows, err := q.db.QueryContext(ctx, getTripRatings, tripID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TripRating{}
+	for rows.Next() {
+		var i TripRating
+		if err := rows.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.Target,
+			&i.RaterID,
+			&i.RateeID,
+			&i.Rating,
+			pq.Array(&i.Tags),
+			&i.Comment,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTripRecipient = `-- name: GetTripRecipient :one
+SELECT id, name, building, unit, phone, trip_note, trip_id, created_at, updated_at FROM recipients
+WHERE trip_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTripRecipient(ctx context.Context, tripID uuid.NullUUID) (Recipient, error) {
+	row := q.db.QueryRowContext(ctx, getTripRecipient, tripID)
+	var i Recipient
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Building,
+		&i.Unit,
+		&i.Phone,
+		&i.TripNote,
+		&i.TripID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTripTimeline = `-- name: GetTripTimeline :one
+SELECT id, courier_id, duration, assigned_at, arrived_at, picked_up_at, completed_at FROM trips
+WHERE id = $1
+LIMIT 1
+`
+
+type GetTripTimelineRow struct {
+	ID          uuid.UUID     `json:"id"`
+	CourierID   uuid.NullUUID `json:"courier_id"`
+	Duration    int32         `json:"duration"`
+	AssignedAt  sql.NullTime  `json:"assigned_at"`
+	ArrivedAt   sql.NullTime  `json:"arrived_at"`
+	PickedUpAt  sql.NullTime  `json:"picked_up_at"`
+	CompletedAt sql.NullTime  `json:"completed_at"`
+}
+
+func (q *Queries) GetTripTimeline(ctx context.Context, id uuid.UUID) (GetTripTimelineRow, error) {
+	row := q.db.QueryRowContext(ctx, getTripTimeline, id)
+	var i GetTripTimelineRow
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.Duration,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getUnpaidEarningsEntries = `-- name: GetUnpaidEarningsEntries :many
+SELECT e.id, e.amount FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE e.account_id = $1 AND (e.created_at < $2 OR e.amount < 0)
+AND t.kind IN ('TRIP_FARE', 'COMMISSION', 'FLEET_SHARE', 'TIP', 'BONUS', 'PENALTY', 'COD_SETTLEMENT', 'COD_REMITTANCE')
+AND NOT EXISTS (
+  SELECT 1 FROM payout_entries pe
+  WHERE pe.entry_id = e.id
+)
+`
+
+type GetUnpaidEarningsEntriesParams struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Cutoff    time.Time `json:"cutoff"`
+}
+
+type GetUnpaidEarningsEntriesRow struct {
+	ID     uuid.UUID `json:"id"`
+	Amount int32     `json:"amount"`
+}
+
+// Earnings settled before the cutoff and every deduction so far, so
+// deductions after the cutoff come out of this payout and no other
+func (q *Queries) GetUnpaidEarningsEntries(ctx context.Context, arg GetUnpaidEarningsEntriesParams) ([]GetUnpaidEarningsEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnpaidEarningsEntries, arg.AccountID, arg.Cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUnpaidEarningsEntriesRow{}
+	for rows.Next() {
+		var i GetUnpaidEarningsEntriesRow
+		if err := rows.Scan(&i.ID, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUpcomingShifts = `-- name: GetUpcomingShifts :many
+SELECT s.id, s.zone_id, s.starts_at, s.ends_at, s.capacity, s.guarantee, s.created_at, s.updated_at, z.name AS zone_name, (
+  SELECT COUNT(*) FROM shift_bookings b
+  WHERE b.shift_id = s.id AND b.status IN ('BOOKED', 'CHECKED_IN')
+)::integer AS booked FROM shifts s
+JOIN zones z ON z.id = s.zone_id
+WHERE s.starts_at > $1 AND ($2::uuid IS NULL OR s.zone_id = $2)
+ORDER BY s.starts_at
+`
+
+type GetUpcomingShiftsParams struct {
+	Now    time.Time     `json:"now"`
+	ZoneID uuid.NullUUID `json:"zone_id"`
+}
+
+type GetUpcomingShiftsRow struct {
+	Shift    Shift  `json:"shift"`
+	ZoneName string `json:"zone_name"`
+	Booked   int32  `json:"booked"`
+}
+
+func (q *Queries) GetUpcomingShifts(ctx context.Context, arg GetUpcomingShiftsParams) ([]GetUpcomingShiftsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUpcomingShifts, arg.Now, arg.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUpcomingShiftsRow{}
+	for rows.Next() {
+		var i GetUpcomingShiftsRow
+		if err := rows.Scan(
+			&i.Shift.ID,
+			&i.Shift.ZoneID,
+			&i.Shift.StartsAt,
+			&i.Shift.EndsAt,
+			&i.Shift.Capacity,
+			&i.Shift.Guarantee,
+			&i.Shift.CreatedAt,
+			&i.Shift.UpdatedAt,
+			&i.ZoneName,
+			&i.Booked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUploadForUpdate = `-- name: GetUploadForUpdate :one
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM uploads
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetUploadForUpdate(ctx context.Context, id uuid.UUID) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, getUploadForUpdate, id)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Uri,
+		&i.Verification,
+		&i.CourierID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+	)
+	return i, err
+}
+
+const getUserByReferralCode = `-- name: GetUserByReferralCode :one
+SELECT id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count FROM users
+WHERE referral_code = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByReferralCode(ctx context.Context, referralCode sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByReferralCode, referralCode)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.Onboarding,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const getUserLedgerAccount = `-- name: GetUserLedgerAccount :one
+SELECT id, user_id, kind, balance, created_at, updated_at FROM ledger_accounts
+WHERE user_id = $1 AND kind = $2
+LIMIT 1
+`
+
+type GetUserLedgerAccountParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Kind   string        `json:"kind"`
+}
+
+func (q *Queries) GetUserLedgerAccount(ctx context.Context, arg GetUserLedgerAccountParams) (LedgerAccount, error) {
+	row := q.db.QueryRowContext(ctx, getUserLedgerAccount, arg.UserID, arg.Kind)
+	var i LedgerAccount
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Balance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserLedgerAccounts = `-- name: GetUserLedgerAccounts :many
+SELECT id, user_id, kind, balance, created_at, updated_at FROM ledger_accounts
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserLedgerAccounts(ctx context.Context, userID uuid.NullUUID) ([]LedgerAccount, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLedgerAccounts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LedgerAccount{}
+	for rows.Next() {
+		var i LedgerAccount
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Balance,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLedgerEntriesBetween = `-- name: GetUserLedgerEntriesBetween :many
+SELECT e.id, e.amount, e.created_at, t.kind, t.trip_id FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1 AND a.kind = $2 AND e.created_at >= $3 AND e.created_at < $4
+ORDER BY e.created_at
+`
+
+type GetUserLedgerEntriesBetweenParams struct {
+	UserID  uuid.NullUUID `json:"user_id"`
+	Kind    string        `json:"kind"`
+	StartAt time.Time     `json:"start_at"`
+	EndAt   time.Time     `json:"end_at"`
+}
+
+type GetUserLedgerEntriesBetweenRow struct {
+	ID        uuid.UUID     `json:"id"`
+	Amount    int32         `json:"amount"`
+	CreatedAt time.Time     `json:"created_at"`
+	Kind      string        `json:"kind"`
+	TripID    uuid.NullUUID `json:"trip_id"`
+}
+
+func (q *Queries) GetUserLedgerEntriesBetween(ctx context.Context, arg GetUserLedgerEntriesBetweenParams) ([]GetUserLedgerEntriesBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLedgerEntriesBetween,
+		arg.UserID,
+		arg.Kind,
+		arg.StartAt,
+		arg.EndAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserLedgerEntriesBetweenRow{}
+	for rows.Next() {
+		var i GetUserLedgerEntriesBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.TripID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLedgerStatement = `-- name: GetUserLedgerStatement :many
+SELECT e.id, e.amount, e.balance, e.created_at, a.kind AS account, t.kind, t.description, t.trip_id FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE a.user_id = $1
+ORDER BY e.created_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetUserLedgerStatementParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Limit  int32         `json:"limit"`
+	Offset int32         `json:"offset"`
+}
+
+type GetUserLedgerStatementRow struct {
+	ID          uuid.UUID     `json:"id"`
+	Amount      int32         `json:"amount"`
+	Balance     int32         `json:"balance"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Account     string        `json:"account"`
+	Kind        string        `json:"kind"`
+	Description string        `json:"description"`
+	TripID      uuid.NullUUID `json:"trip_id"`
+}
+
+func (q *Queries) GetUserLedgerStatement(ctx context.Context, arg GetUserLedgerStatementParams) ([]GetUserLedgerStatementRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLedgerStatement, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserLedgerStatementRow{}
+	for rows.Next() {
+		var i GetUserLedgerStatementRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Balance,
+			&i.CreatedAt,
+			&i.Account,
+			&i.Kind,
+			&i.Description,
+			&i.TripID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserPaymentCard = `-- name: GetUserPaymentCard :one
+SELECT id, user_id, email, authorization_code, signature, card_type, last4, exp_month, exp_year, bank, created_at, updated_at FROM payment_cards
+WHERE id = $1 AND user_id = $2
+LIMIT 1
+`
+
+type GetUserPaymentCardParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUserPaymentCard(ctx context.Context, arg GetUserPaymentCardParams) (PaymentCard, error) {
+	row := q.db.QueryRowContext(ctx, getUserPaymentCard, arg.ID, arg.UserID)
+	var i PaymentCard
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.AuthorizationCode,
+		&i.Signature,
+		&i.CardType,
+		&i.Last4,
+		&i.ExpMonth,
+		&i.ExpYear,
+		&i.Bank,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserPaymentCards = `-- name: GetUserPaymentCards :many
+SELECT id, user_id, email, authorization_code, signature, card_type, last4, exp_month, exp_year, bank, created_at, updated_at FROM payment_cards
+WHERE user_id = $1
+ORDER BY updated_at DESC
+`
+
+func (q *Queries) GetUserPaymentCards(ctx context.Context, userID uuid.UUID) ([]PaymentCard, error) {
+	rows, err := q.db.QueryContext(ctx, getUserPaymentCards, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PaymentCard{}
+	for rows.Next() {
+		var i PaymentCard
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Email,
+			&i.AuthorizationCode,
+			&i.Signature,
+			&i.CardType,
+			&i.Last4,
+			&i.ExpMonth,
+			&i.ExpYear,
+			&i.Bank,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserReferral = `-- name: GetUserReferral :one
+SELECT id, referrer_id, referee_id, kind, status, device_id, rewarded_at, created_at, updated_at FROM referrals
+WHERE referee_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserReferral(ctx context.Context, refereeID uuid.UUID) (Referral, error) {
+	row := q.db.QueryRowContext(ctx, getUserReferral, refereeID)
+	var i Referral
+	err := row.Scan(
+		&i.ID,
+		&i.ReferrerID,
+		&i.RefereeID,
+		&i.Kind,
+		&i.Status,
+		&i.DeviceID,
+		&i.RewardedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserUpload = `-- name: GetUserUpload :one
+SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at FROM uploads
+WHERE user_id = $1 AND type = $2
+LIMIT 1
+`
+
+type GetUserUploadParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Type   string        `json:"type"`
+}
+
+func (q *Queries) GetUserUpload(ctx context.Context, arg GetUserUploadParams) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, getUserUpload, arg.UserID, arg.Type)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Uri,
+		&i.Verification,
+		&i.CourierID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+	)
+	return i, err
+}
+
+const getVehicle = `-- name: GetVehicle :one
+SELECT id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at FROM vehicles
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetVehicle(ctx context.Context, id uuid.UUID) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, getVehicle, id)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.ProductID,
+		&i.Type,
+		&i.Plate,
+		&i.Make,
+		&i.Colour,
+		&i.Capacity,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FleetID,
+		&i.FleetApprovedBy,
+		&i.FleetApprovedAt,
+	)
+	return i, err
+}
+
+const getVehicleForUpdate = `-- name: GetVehicleForUpdate :one
+SELECT id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at FROM vehicles
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetVehicleForUpdate(ctx context.Context, id uuid.UUID) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, getVehicleForUpdate, id)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.ProductID,
+		&i.Type,
+		&i.Plate,
+		&i.Make,
+		&i.Colour,
+		&i.Capacity,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FleetID,
+		&i.FleetApprovedBy,
+		&i.FleetApprovedAt,
+	)
+	return i, err
+}
+
+const getZoneByPoint = `-- name: GetZoneByPoint :one
+SELECT id, name FROM zones
+WHERE ST_Covers(boundary, $1::geography)
+LIMIT 1
+`
+
+type GetZoneByPointRow struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (q *Queries) GetZoneByPoint(ctx context.Context, point interface{}) (GetZoneByPointRow, error) {
+	row := q.db.QueryRowContext(ctx, getZoneByPoint, point)
+	var i GetZoneByPointRow
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const getZoneName = `-- name: GetZoneName :one
+SELECT name FROM zones
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetZoneName(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getZoneName, id)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
+const hasOpenTripPayment = `-- name: HasOpenTripPayment :one
+SELECT EXISTS (
+  SELECT 1 FROM payments
+  WHERE trip_id = $1 AND purpose = $2 AND status IN ('PENDING', 'PAID')
+)
+`
+
+type HasOpenTripPaymentParams struct {
+	TripID  uuid.NullUUID `json:"trip_id"`
+	Purpose string        `json:"purpose"`
+}
+
+func (q *Queries) HasOpenTripPayment(ctx context.Context, arg HasOpenTripPaymentParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasOpenTripPayment, arg.TripID, arg.Purpose)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isCourier = `-- name: IsCourier :one
+SELECT verified FROM
+couriers
+WHERE user_id = $1
+LIMIT 1
+`
+
+func (q *Queries) IsCourier(ctx context.Context, userID uuid.NullUUID) (sql.NullBool, error) {
+	row := q.db.QueryRowContext(ctx, isCourier, userID)
+	var verified sql.NullBool
+	err := row.Scan(&verified)
+	return verified, err
+}
+
+const isCourierInZone = `-- name: IsCourierInZone :one
+SELECT EXISTS (
+  SELECT 1 FROM couriers c
+  JOIN zones z ON ST_Covers(z.boundary, c.location)
+  WHERE c.id = $1 AND z.id = $2 AND c.location_updated_at >= $3
+)
+`
+
+type IsCourierInZoneParams struct {
+	CourierID    uuid.UUID    `json:"courier_id"`
+	ZoneID       uuid.UUID    `json:"zone_id"`
+	LocatedAfter sql.NullTime `json:"located_after"`
+}
+
+func (q *Queries) IsCourierInZone(ctx context.Context, arg IsCourierInZoneParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isCourierInZone, arg.CourierID, arg.ZoneID, arg.LocatedAfter)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isDeviceReferred = `-- name: IsDeviceReferred :one
+SELECT EXISTS (
+  SELECT 1 FROM referrals
+  WHERE device_id = $1 AND status != 'REJECTED'
+)
+`
+
+func (q *Queries) IsDeviceReferred(ctx context.Context, deviceID sql.NullString) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isDeviceReferred, deviceID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isFleetManager = `-- name: IsFleetManager :one
+SELECT EXISTS (
+  SELECT 1 FROM fleet_managers
+  WHERE fleet_id = $1 AND user_id = $2
+)
+`
+
+type IsFleetManagerParams struct {
+	FleetID uuid.UUID `json:"fleet_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) IsFleetManager(ctx context.Context, arg IsFleetManagerParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isFleetManager, arg.FleetID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isPlateRegistered = `-- name: IsPlateRegistered :one
+SELECT EXISTS (
+  SELECT 1 FROM vehicles
+  WHERE plate = $1 AND status <> 'REJECTED'
+)
+`
+
+func (q *Queries) IsPlateRegistered(ctx context.Context, plate string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isPlateRegistered, plate)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isPublicHoliday = `-- name: IsPublicHoliday :one
+SELECT EXISTS (
+  SELECT 1 FROM public_holidays
+  WHERE day = $1::date
+)
+`
+
+func (q *Queries) IsPublicHoliday(ctx context.Context, day time.Time) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isPublicHoliday, day)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isUserAdmin = `-- name: IsUserAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) IsUserAdmin(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isUserAdmin, id)
+	var is_admin bool
+	err := row.Scan(&is_admin)
+	return is_admin, err
+}
+
+const isUserOnboarding = `-- name: IsUserOnboarding :one
+SELECT onboarding FROM
+users
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) IsUserOnboarding(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isUserOnboarding, id)
+	var onboarding bool
+	err := row.Scan(&onboarding)
+	return onboarding, err
+}
+
+const lockLedgerAccounts = `-- name: LockLedgerAccounts :many
+SELECT id, user_id, kind, balance, created_at, updated_at FROM ledger_accounts
+WHERE id = ANY($1::uuid[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockLedgerAccounts(ctx context.Context, ids []uuid.UUID) ([]LedgerAccount, error) {
+	rows, err := q.db.QueryContext(ctx, lockLedgerAccounts, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LedgerAccount{}
+	for rows.Next() {
+		var i LedgerAccount
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Balance,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ownsFleet = `-- name: OwnsFleet :one
+SELECT EXISTS (
+  SELECT 1 FROM fleets
+  WHERE owner_id = $1
+)
+`
+
+func (q *Queries) OwnsFleet(ctx context.Context, ownerID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, ownsFleet, ownerID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const recordTripDistances = `-- name: RecordTripDistances :execrows
+UPDATE trips t
+SET actual_distance = COALESCE((
+  SELECT ST_Length(ST_MakeLine(l.location::geometry ORDER BY l.recorded_at)::geography)
+  FROM courier_locations l
+  WHERE l.trip_id = t.id AND l.recorded_at >= t.created_at
+), 0), updated_at = NOW()
+WHERE t.status = 'COMPLETE' AND (
+  (t.actual_distance IS NULL AND t.completed_at >= $1 AND t.completed_at < $2)
+  -- Late points change the distance of trips already measured
+  OR t.id = ANY($3::uuid[])
+)
+`
+
+type RecordTripDistancesParams struct {
+	CompletedAfter  sql.NullTime `json:"completed_after"`
+	CompletedBefore sql.NullTime `json:"completed_before"`
+	TripIds         []uuid.UUID  `json:"trip_ids"`
+}
+
+func (q *Queries) RecordTripDistances(ctx context.Context, arg RecordTripDistancesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordTripDistances, arg.CompletedAfter, arg.CompletedBefore, pq.Array(arg.TripIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const refundPayment = `-- name: RefundPayment :one
+UPDATE payments
+SET status = $1, refunded_amount = refunded_amount + $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
+`
+
+type RefundPaymentParams struct {
+	Status         string    `json:"status"`
+	RefundedAmount int32     `json:"refunded_amount"`
+	ID             uuid.UUID `json:"id"`
+}
+
+func (q *Queries) RefundPayment(ctx context.Context, arg RefundPaymentParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, refundPayment, arg.Status, arg.RefundedAmount, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const respondFleetInvitation = `-- name: RespondFleetInvitation :one
+UPDATE fleet_invitations
+SET status = $1, responded_at = NOW(), updated_at = NOW()
+WHERE id = $2
+RETURNING id, fleet_id, courier_id, invited_by, status, responded_at, created_at, updated_at
+`
+
+type RespondFleetInvitationParams struct {
+	Status string    `json:"status"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) RespondFleetInvitation(ctx context.Context, arg RespondFleetInvitationParams) (FleetInvitation, error) {
+	row := q.db.QueryRowContext(ctx, respondFleetInvitation, arg.Status, arg.ID)
+	var i FleetInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.FleetID,
+		&i.CourierID,
+		&i.InvitedBy,
+		&i.Status,
+		&i.RespondedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const reviewUpload = `-- name: ReviewUpload :one
+UPDATE uploads
+SET verification = $1, rejection_reason = $2, reviewed_by = $3, expires_at = $4, expiry_warned_at = NULL, reviewed_at = NOW(), updated_at = NOW()
+WHERE id = $5
+RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at
+`
+
+type ReviewUploadParams struct {
+	Verification    string         `json:"verification"`
+	RejectionReason sql.NullString `json:"rejection_reason"`
+	ReviewedBy      uuid.NullUUID  `json:"reviewed_by"`
+	ExpiresAt       sql.NullTime   `json:"expires_at"`
+	ID              uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) ReviewUpload(ctx context.Context, arg ReviewUploadParams) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, reviewUpload,
+		arg.Verification,
+		arg.RejectionReason,
+		arg.ReviewedBy,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Uri,
+		&i.Verification,
+		&i.CourierID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+	)
+	return i, err
+}
+
+const reviewVehicle = `-- name: ReviewVehicle :one
+UPDATE vehicles
+SET status = $1, rejection_reason = $2, reviewed_by = $3, reviewed_at = NOW(), updated_at = NOW()
+WHERE id = $4
+RETURNING id, courier_id, product_id, type, plate, make, colour, capacity, status, rejection_reason, reviewed_by, reviewed_at, created_at, updated_at, fleet_id, fleet_approved_by, fleet_approved_at
+`
+
+type ReviewVehicleParams struct {
+	Status          string         `json:"status"`
+	RejectionReason sql.NullString `json:"rejection_reason"`
+	ReviewedBy      uuid.NullUUID  `json:"reviewed_by"`
+	ID              uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) ReviewVehicle(ctx context.Context, arg ReviewVehicleParams) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, reviewVehicle,
+		arg.Status,
+		arg.RejectionReason,
+		arg.ReviewedBy,
+		arg.ID,
+	)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.CourierID,
+		&i.ProductID,
+		&i.Type,
+		&i.Plate,
+		&i.Make,
+		&i.Colour,
+		&i.Capacity,
+		&i.Status,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FleetID,
+		&i.FleetApprovedBy,
+		&i.FleetApprovedAt,
+	)
+	return i, err
+}
+
+const rewardReferral = `-- name: RewardReferral :one
+UPDATE referrals
+SET status = 'REWARDED', rewarded_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'PENDING'
+RETURNING id, referrer_id, referee_id, kind, status, device_id, rewarded_at, created_at, updated_at
+`
+
+func (q *Queries) RewardReferral(ctx context.Context, id uuid.UUID) (Referral, error) {
+	row := q.db.QueryRowContext(ctx, rewardReferral, id)
+	var i Referral
+	err := row.Scan(
+		&i.ID,
+		&i.ReferrerID,
+		&i.RefereeID,
+		&i.Kind,
+		&i.Status,
+		&i.DeviceID,
+		&i.RewardedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const savePaymentCard = `-- name: SavePaymentCard :one
+INSERT INTO payment_cards (
+  user_id, email, authorization_code, signature, card_type, last4, exp_month, exp_year, bank
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (user_id, signature) DO UPDATE
+SET authorization_code = EXCLUDED.authorization_code, exp_month = EXCLUDED.exp_month, exp_year = EXCLUDED.exp_year, updated_at = NOW()
+RETURNING id, user_id, email, authorization_code, signature, card_type, last4, exp_month, exp_year, bank, created_at, updated_at
+`
+
+type SavePaymentCardParams struct {
+	UserID            uuid.UUID      `json:"user_id"`
+	Email             string         `json:"email"`
+	AuthorizationCode string         `json:"authorization_code"`
+	Signature         string         `json:"signature"`
+	CardType          string         `json:"card_type"`
+	Last4             string         `json:"last4"`
+	ExpMonth          string         `json:"exp_month"`
+	ExpYear           string         `json:"exp_year"`
+	Bank              sql.NullString `json:"bank"`
+}
+
+func (q *Queries) SavePaymentCard(ctx context.Context, arg SavePaymentCardParams) (PaymentCard, error) {
+	row := q.db.QueryRowContext(ctx, savePaymentCard,
+		arg.UserID,
+		arg.Email,
+		arg.AuthorizationCode,
+		arg.Signature,
+		arg.CardType,
+		arg.Last4,
+		arg.ExpMonth,
+		arg.ExpYear,
+		arg.Bank,
+	)
+	var i PaymentCard
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.AuthorizationCode,
+		&i.Signature,
+		&i.CardType,
+		&i.Last4,
+		&i.ExpMonth,
+		&i.ExpYear,
+		&i.Bank,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setCourierFleet = `-- name: SetCourierFleet :one
+UPDATE couriers
+SET fleet_id = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id
+`
+
+type SetCourierFleetParams struct {
+	FleetID uuid.NullUUID `json:"fleet_id"`
+	ID      uuid.UUID     `json:"id"`
+}
+
+func (q *Queries) SetCourierFleet(ctx context.Context, arg SetCourierFleetParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, setCourierFleet, arg.FleetID, arg.ID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setCourierRating = `-- name: SetCourierRating :exec
+UPDATE couriers
+SET rating = (
+  SELECT AVG(r.rating)::DOUBLE PRECISION FROM (
+    SELECT rating FROM trip_ratings
+    WHERE ratee_id = $1 AND target = 'COURIER'
+    ORDER BY created_at DESC
+    LIMIT $2
+  ) r
+), rating_count = rating_count + 1, updated_at = NOW()
+WHERE user_id = $1
+`
+
+type SetCourierRatingParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	WindowSize int32     `json:"window_size"`
+}
+
+func (q *Queries) SetCourierRating(ctx context.Context, arg SetCourierRatingParams) error {
+	_, err := q.db.ExecContext(ctx, setCourierRating, arg.UserID, arg.WindowSize)
+	return err
+}
+
+const setCourierStatus = `-- name: SetCourierStatus :one
+UPDATE couriers
+SET status = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id
+`
+
+type SetCourierStatusParams struct {
+	Status string    `json:"status"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetCourierStatus(ctx context.Context, arg SetCourierStatusParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, setCourierStatus, arg.Status, arg.ID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setCourierTier = `-- name: SetCourierTier :exec
+UPDATE couriers
+SET tier = $1
+WHERE id = $2
+`
+
+type SetCourierTierParams struct {
+	Tier string    `json:"tier"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetCourierTier(ctx context.Context, arg SetCourierTierParams) error {
+	_, err := q.db.ExecContext(ctx, setCourierTier, arg.Tier, arg.ID)
+	return err
+}
+
+const setCourierVehicle = `-- name: SetCourierVehicle :one
+UPDATE couriers
+SET vehicle_id = $1, product_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id
+`
+
+type SetCourierVehicleParams struct {
+	VehicleID uuid.NullUUID `json:"vehicle_id"`
+	ProductID uuid.NullUUID `json:"product_id"`
+	ID        uuid.UUID     `json:"id"`
+}
+
+func (q *Queries) SetCourierVehicle(ctx context.Context, arg SetCourierVehicleParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, setCourierVehicle, arg.VehicleID, arg.ProductID, arg.ID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setCourierVerified = `-- name: SetCourierVerified :one
+UPDATE couriers
+SET verified = $1,
+  status = CASE
+    WHEN $1::boolean AND status = 'ONBOARDING' THEN 'OFFLINE'
+    WHEN NOT $1::boolean THEN 'ONBOARDING'
+    ELSE status
+  END,
+  updated_at = NOW()
+WHERE id = $2
+RETURNING id, verified, status, location, points, user_id, product_id, trip_id, created_at, updated_at, rating, rating_count, tier, vehicle_id, location_updated_at, fleet_id
+`
+
+type SetCourierVerifiedParams struct {
+	Verified sql.NullBool `json:"verified"`
+	ID       uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) SetCourierVerified(ctx context.Context, arg SetCourierVerifiedParams) (Courier, error) {
+	row := q.db.QueryRowContext(ctx, setCourierVerified, arg.Verified, arg.ID)
+	var i Courier
+	err := row.Scan(
+		&i.ID,
+		&i.Verified,
+		&i.Status,
+		&i.Location,
+		&i.Points,
+		&i.UserID,
+		&i.ProductID,
+		&i.TripID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Rating,
+		&i.RatingCount,
+		&i.Tier,
+		&i.VehicleID,
+		&i.LocationUpdatedAt,
+		&i.FleetID,
+	)
+	return i, err
+}
+
+const setFleetRiderShare = `-- name: SetFleetRiderShare :one
+UPDATE fleets
+SET rider_share = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id, name, owner_id, rider_share, created_at, updated_at
+`
+
+type SetFleetRiderShareParams struct {
+	RiderShare int32     `json:"rider_share"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetFleetRiderShare(ctx context.Context, arg SetFleetRiderShareParams) (Fleet, error) {
+	row := q.db.QueryRowContext(ctx, setFleetRiderShare, arg.RiderShare, arg.ID)
+	var i Fleet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerID,
+		&i.RiderShare,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setOnboardingStatus = `-- name: SetOnboardingStatus :one
+UPDATE users
+SET onboarding = $1
+WHERE phone = $2
+RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count
+`
+
+type SetOnboardingStatusParams struct {
+	Onboarding bool   `json:"onboarding"`
+	Phone      string `json:"phone"`
+}
+
+func (q *Queries) SetOnboardingStatus(ctx context.Context, arg SetOnboardingStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setOnboardingStatus, arg.Onboarding, arg.Phone)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.Onboarding,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const setPaymentCard = `-- name: SetPaymentCard :exec
+UPDATE payments
+SET card_id = $1, updated_at = NOW()
+WHERE id = $2
+`
+
+type SetPaymentCardParams struct {
+	CardID uuid.NullUUID `json:"card_id"`
+	ID     uuid.UUID     `json:"id"`
+}
+
+func (q *Queries) SetPaymentCard(ctx context.Context, arg SetPaymentCardParams) error {
+	_, err := q.db.ExecContext(ctx, setPaymentCard, arg.CardID, arg.ID)
+	return err
+}
+
+const setPaymentCheckout = `-- name: SetPaymentCheckout :one
+UPDATE payments
+SET merchant_request_id = $1, checkout_request_id = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
+`
+
+type SetPaymentCheckoutParams struct {
+	MerchantRequestID sql.NullString `json:"merchant_request_id"`
+	CheckoutRequestID sql.NullString `json:"checkout_request_id"`
+	ID                uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPaymentCheckout(ctx context.Context, arg SetPaymentCheckoutParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, setPaymentCheckout, arg.MerchantRequestID, arg.CheckoutRequestID, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const setPaymentReference = `-- name: SetPaymentReference :one
+UPDATE payments
+SET reference = $1, authorization_url = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
+`
+
+type SetPaymentReferenceParams struct {
+	Reference        sql.NullString `json:"reference"`
+	AuthorizationUrl sql.NullString `json:"authorization_url"`
+	ID               uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPaymentReference(ctx context.Context, arg SetPaymentReferenceParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, setPaymentReference, arg.Reference, arg.AuthorizationUrl, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const setPaymentResult = `-- name: SetPaymentResult :one
+UPDATE payments
+SET status = $1, receipt = $2, result_code = $3, result_desc = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
+`
+
+type SetPaymentResultParams struct {
+	Status     string         `json:"status"`
+	Receipt    sql.NullString `json:"receipt"`
+	ResultCode sql.NullInt32  `json:"result_code"`
+	ResultDesc sql.NullString `json:"result_desc"`
+	ID         uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPaymentResult(ctx context.Context, arg SetPaymentResultParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, setPaymentResult,
+		arg.Status,
+		arg.Receipt,
+		arg.ResultCode,
+		arg.ResultDesc,
+		arg.ID,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
+	)
+	return i, err
+}
+
+const setPaymentStatus = `-- name: SetPaymentStatus :one
+UPDATE payments
+SET status = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING id, trip_id, user_id, provider, amount, phone, status, merchant_request_id, checkout_request_id, receipt, result_code, result_desc, created_at, updated_at, reference, authorization_url, refunded_amount, purpose, card_id
 `
 
-type GetProductByIDRow struct {
-	ID          uuid.UUID `json:"id"`
-	Icon        string    `json:"icon"`
-	Name        string    `json:"name"`
-	WeightClass int32     `json:"weight_class"`
+type SetPaymentStatusParams struct {
+	Status string    `json:"status"`
+	ID     uuid.UUID `json:"id"`
 }
 
-func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getProductByID, id)
-	var i GetProductByIDRow
+func (q *Queries) SetPaymentStatus(ctx context.Context, arg SetPaymentStatusParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, setPaymentStatus, arg.Status, arg.ID)
+	var i Payment
 	err := row.Scan(
 		&i.ID,
-		&i.Icon,
-		&i.Name,
-		&i.WeightClass,
+		&i.TripID,
+		&i.UserID,
+		&i.Provider,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.MerchantRequestID,
+		&i.CheckoutRequestID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Reference,
+		&i.AuthorizationUrl,
+		&i.RefundedAmount,
+		&i.Purpose,
+		&i.CardID,
 	)
 	return i, err
 }
 
-const getSession = `-- name: GetSession :one
-SELECT id, ip, user_agent, phone, created_at, updated_at FROM sessions
-WHERE id = $1
-LIMIT 1
+const setPayoutConversation = `-- name: SetPayoutConversation :exec
+UPDATE payouts
+SET conversation_id = $1, updated_at = NOW()
+WHERE id = $2
 `
 
-func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
-	row := q.db.QueryRowContext(ctx, getSession, id)
-	var i Session
+type SetPayoutConversationParams struct {
+	ConversationID sql.NullString `json:"conversation_id"`
+	ID             uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPayoutConversation(ctx context.Context, arg SetPayoutConversationParams) error {
+	_, err := q.db.ExecContext(ctx, setPayoutConversation, arg.ConversationID, arg.ID)
+	return err
+}
+
+const setPayoutResult = `-- name: SetPayoutResult :one
+UPDATE payouts
+SET status = $1, attempts = $2, receipt = $3, result_code = $4, result_desc = $5, updated_at = NOW()
+WHERE id = $6
+RETURNING id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id
+`
+
+type SetPayoutResultParams struct {
+	Status     string         `json:"status"`
+	Attempts   int32          `json:"attempts"`
+	Receipt    sql.NullString `json:"receipt"`
+	ResultCode sql.NullInt32  `json:"result_code"`
+	ResultDesc sql.NullString `json:"result_desc"`
+	ID         uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPayoutResult(ctx context.Context, arg SetPayoutResultParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, setPayoutResult,
+		arg.Status,
+		arg.Attempts,
+		arg.Receipt,
+		arg.ResultCode,
+		arg.ResultDesc,
+		arg.ID,
+	)
+	var i Payout
 	err := row.Scan(
 		&i.ID,
-		&i.Ip,
-		&i.UserAgent,
+		&i.CourierID,
+		&i.UserID,
+		&i.Amount,
 		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StatusConversationID,
 	)
 	return i, err
 }
 
-const getTrip = `-- name: GetTrip :one
-SELECT id, status, courier_id, cost, product_id, ST_AsGeoJSON(confirmed_pickup) AS confirmed_pickup, ST_AsGeoJSON(start_location) AS start_location, ST_AsGeoJSON(end_location) AS end_location FROM trips
-WHERE id = $1
-LIMIT 1
+const setPayoutRetry = `-- name: SetPayoutRetry :one
+UPDATE payouts
+SET status = 'PENDING', attempts = $1, next_attempt_at = $2, result_code = $3, result_desc = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id
 `
 
-type GetTripRow struct {
-	ID              uuid.UUID     `json:"id"`
-	Status          string        `json:"status"`
-	CourierID       uuid.NullUUID `json:"courier_id"`
-	Cost            int32         `json:"cost"`
-	ProductID       uuid.UUID     `json:"product_id"`
-	ConfirmedPickup interface{}   `json:"confirmed_pickup"`
-	StartLocation   interface{}   `json:"start_location"`
-	EndLocation     interface{}   `json:"end_location"`
+type SetPayoutRetryParams struct {
+	Attempts      int32          `json:"attempts"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	ResultCode    sql.NullInt32  `json:"result_code"`
+	ResultDesc    sql.NullString `json:"result_desc"`
+	ID            uuid.UUID      `json:"id"`
 }
 
-func (q *Queries) GetTrip(ctx context.Context, id uuid.UUID) (GetTripRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrip, id)
-	var i GetTripRow
+func (q *Queries) SetPayoutRetry(ctx context.Context, arg SetPayoutRetryParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, setPayoutRetry,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.ResultCode,
+		arg.ResultDesc,
+		arg.ID,
+	)
+	var i Payout
 	err := row.Scan(
 		&i.ID,
-		&i.Status,
 		&i.CourierID,
-		&i.Cost,
-		&i.ProductID,
-		&i.ConfirmedPickup,
-		&i.StartLocation,
-		&i.EndLocation,
+		&i.UserID,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatusConversationID,
 	)
 	return i, err
 }
 
-const getTripRecipient = `-- name: GetTripRecipient :one
-SELECT id, name, building, unit, phone, trip_note, trip_id, created_at, updated_at FROM recipients
-WHERE trip_id = $1
-LIMIT 1
+const setPayoutStatusConversation = `-- name: SetPayoutStatusConversation :exec
+UPDATE payouts
+SET status_conversation_id = $1, next_attempt_at = $2, updated_at = NOW()
+WHERE id = $3
 `
 
-func (q *Queries) GetTripRecipient(ctx context.Context, tripID uuid.NullUUID) (Recipient, error) {
-	row := q.db.QueryRowContext(ctx, getTripRecipient, tripID)
-	var i Recipient
+type SetPayoutStatusConversationParams struct {
+	StatusConversationID sql.NullString `json:"status_conversation_id"`
+	NextAttemptAt        time.Time      `json:"next_attempt_at"`
+	ID                   uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPayoutStatusConversation(ctx context.Context, arg SetPayoutStatusConversationParams) error {
+	_, err := q.db.ExecContext(ctx, setPayoutStatusConversation, arg.StatusConversationID, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const setPayoutSubmitted = `-- name: SetPayoutSubmitted :one
+UPDATE payouts
+SET status = 'PROCESSING', attempts = attempts + 1, originator_conversation_id = $1, conversation_id = NULL, updated_at = NOW()
+WHERE id = $2
+RETURNING id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id
+`
+
+type SetPayoutSubmittedParams struct {
+	OriginatorConversationID sql.NullString `json:"originator_conversation_id"`
+	ID                       uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetPayoutSubmitted(ctx context.Context, arg SetPayoutSubmittedParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, setPayoutSubmitted, arg.OriginatorConversationID, arg.ID)
+	var i Payout
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.Building,
-		&i.Unit,
+		&i.CourierID,
+		&i.UserID,
+		&i.Amount,
 		&i.Phone,
-		&i.TripNote,
-		&i.TripID,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StatusConversationID,
 	)
 	return i, err
 }
 
-const getUserUpload = `-- name: GetUserUpload :one
-SELECT id, type, uri, verification, courier_id, user_id, created_at, updated_at FROM uploads
-WHERE user_id = $1 AND type = $2
-LIMIT 1
+const setPayoutVerifying = `-- name: SetPayoutVerifying :one
+UPDATE payouts
+SET status = 'VERIFYING', next_attempt_at = $1, result_code = $2, result_desc = $3, updated_at = NOW()
+WHERE id = $4
+RETURNING id, courier_id, user_id, amount, phone, status, attempts, next_attempt_at, originator_conversation_id, conversation_id, receipt, result_code, result_desc, created_at, updated_at, status_conversation_id
 `
 
-type GetUserUploadParams struct {
-	UserID uuid.NullUUID `json:"user_id"`
-	Type   string        `json:"type"`
+type SetPayoutVerifyingParams struct {
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	ResultCode    sql.NullInt32  `json:"result_code"`
+	ResultDesc    sql.NullString `json:"result_desc"`
+	ID            uuid.UUID      `json:"id"`
 }
 
-func (q *Queries) GetUserUpload(ctx context.Context, arg GetUserUploadParams) (Upload, error) {
-	row := q.db.QueryRowContext(ctx, getUserUpload, arg.UserID, arg.Type)
-	var i Upload
+func (q *Queries) SetPayoutVerifying(ctx context.Context, arg SetPayoutVerifyingParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, setPayoutVerifying,
+		arg.NextAttemptAt,
+		arg.ResultCode,
+		arg.ResultDesc,
+		arg.ID,
+	)
+	var i Payout
 	err := row.Scan(
 		&i.ID,
-		&i.Type,
-		&i.Uri,
-		&i.Verification,
 		&i.CourierID,
 		&i.UserID,
+		&i.Amount,
+		&i.Phone,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.OriginatorConversationID,
+		&i.ConversationID,
+		&i.Receipt,
+		&i.ResultCode,
+		&i.ResultDesc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StatusConversationID,
 	)
 	return i, err
 }
 
-const isCourier = `-- name: IsCourier :one
-SELECT verified FROM
-couriers
-WHERE user_id = $1
-LIMIT 1
+const setQuestCompleted = `-- name: SetQuestCompleted :one
+UPDATE quest_progress
+SET completed_at = NOW(), updated_at = NOW()
+WHERE quest_id = $1 AND courier_id = $2 AND completed_at IS NULL
+RETURNING quest_id, courier_id, trips, completed_at, created_at, updated_at
 `
 
-func (q *Queries) IsCourier(ctx context.Context, userID uuid.NullUUID) (sql.NullBool, error) {
-	row := q.db.QueryRowContext(ctx, isCourier, userID)
-	var verified sql.NullBool
-	err := row.Scan(&verified)
-	return verified, err
+type SetQuestCompletedParams struct {
+	QuestID   uuid.UUID `json:"quest_id"`
+	CourierID uuid.UUID `json:"courier_id"`
 }
 
-const isUserOnboarding = `-- name: IsUserOnboarding :one
-SELECT onboarding FROM
-users
-WHERE id = $1
-LIMIT 1
+func (q *Queries) SetQuestCompleted(ctx context.Context, arg SetQuestCompletedParams) (QuestProgress, error) {
+	row := q.db.QueryRowContext(ctx, setQuestCompleted, arg.QuestID, arg.CourierID)
+	var i QuestProgress
+	err := row.Scan(
+		&i.QuestID,
+		&i.CourierID,
+		&i.Trips,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setStaleCouriersOffline = `-- name: SetStaleCouriersOffline :many
+UPDATE couriers c
+SET status = 'OFFLINE', updated_at = NOW()
+FROM couriers prev
+WHERE c.id = prev.id AND prev.status IN ('ONLINE', 'ON_BREAK')
+AND (prev.location_updated_at IS NULL OR prev.location_updated_at < $1)
+RETURNING c.id, prev.status AS from_status
 `
 
-func (q *Queries) IsUserOnboarding(ctx context.Context, id uuid.UUID) (bool, error) {
-	row := q.db.QueryRowContext(ctx, isUserOnboarding, id)
-	var onboarding bool
-	err := row.Scan(&onboarding)
-	return onboarding, err
+type SetStaleCouriersOfflineRow struct {
+	ID         uuid.UUID `json:"id"`
+	FromStatus string    `json:"from_status"`
 }
 
-const setCourierStatus = `-- name: SetCourierStatus :one
-UPDATE couriers
-SET status = $1
-WHERE user_id = $2
-RETURNING id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at
+func (q *Queries) SetStaleCouriersOffline(ctx context.Context, staleBefore sql.NullTime) ([]SetStaleCouriersOfflineRow, error) {
+	rows, err := q.db.QueryContext(ctx, setStaleCouriersOffline, staleBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SetStaleCouriersOfflineRow{}
+	for rows.Next() {
+		var i SetStaleCouriersOfflineRow
+		if err := rows.Scan(&i.ID, &i.FromStatus); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTripCollectedAmount = `-- name: SetTripCollectedAmount :one
+UPDATE trips
+SET collected_amount = $1, updated_at = NOW()
+WHERE id = $2 AND collected_amount IS NULL
+RETURNING id
 `
 
-type SetCourierStatusParams struct {
-	Status string        `json:"status"`
-	UserID uuid.NullUUID `json:"user_id"`
+type SetTripCollectedAmountParams struct {
+	CollectedAmount sql.NullInt32 `json:"collected_amount"`
+	ID              uuid.UUID     `json:"id"`
 }
 
-func (q *Queries) SetCourierStatus(ctx context.Context, arg SetCourierStatusParams) (Courier, error) {
-	row := q.db.QueryRowContext(ctx, setCourierStatus, arg.Status, arg.UserID)
-	var i Courier
-	err := row.Scan(
-		&i.ID,
-		&i.Verified,
-		&i.Status,
-		&i.Location,
-		&i.Ratings,
-		&i.Points,
-		&i.UserID,
-		&i.ProductID,
-		&i.TripID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) SetTripCollectedAmount(ctx context.Context, arg SetTripCollectedAmountParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, setTripCollectedAmount, arg.CollectedAmount, arg.ID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
-const setOnboardingStatus = `-- name: SetOnboardingStatus :one
-UPDATE users
-SET onboarding = $1
-WHERE phone = $2
-RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at
+const setTripDiscount = `-- name: SetTripDiscount :one
+UPDATE trips
+SET discount = $1, discount_funded_by = $2
+WHERE id = $3
+RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id
 `
 
-type SetOnboardingStatusParams struct {
-	Onboarding bool   `json:"onboarding"`
-	Phone      string `json:"phone"`
+type SetTripDiscountParams struct {
+	Discount         int32          `json:"discount"`
+	DiscountFundedBy sql.NullString `json:"discount_funded_by"`
+	ID               uuid.UUID      `json:"id"`
 }
 
-func (q *Queries) SetOnboardingStatus(ctx context.Context, arg SetOnboardingStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, setOnboardingStatus, arg.Onboarding, arg.Phone)
-	var i User
+func (q *Queries) SetTripDiscount(ctx context.Context, arg SetTripDiscountParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, setTripDiscount, arg.Discount, arg.DiscountFundedBy, arg.ID)
+	var i Trip
 	err := row.Scan(
 		&i.ID,
-		&i.FirstName,
-		&i.LastName,
-		&i.Phone,
-		&i.Onboarding,
+		&i.StartLocation,
+		&i.EndLocation,
+		&i.ConfirmedPickup,
+		&i.CourierID,
+		&i.UserID,
+		&i.ProductID,
+		&i.Cost,
+		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
 	)
 	return i, err
 }
 
 const setTripStatus = `-- name: SetTripStatus :one
 UPDATE trips
-SET status = $1
+SET status = $1,
+  assigned_at = CASE WHEN $1 = 'COURIER_ASSIGNED' THEN NOW() ELSE assigned_at END,
+  arrived_at = CASE WHEN $1 = 'COURIER_ARRIVING' THEN COALESCE(arrived_at, NOW()) ELSE arrived_at END,
+  picked_up_at = CASE WHEN $1 = 'COURIER_EN_ROUTE' THEN COALESCE(picked_up_at, NOW()) ELSE picked_up_at END,
+  completed_at = CASE WHEN $1 = 'COMPLETE' THEN COALESCE(completed_at, NOW()) ELSE completed_at END
 WHERE id = $2
-RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at
+RETURNING id, start_location, end_location, confirmed_pickup, courier_id, user_id, product_id, cost, status, created_at, updated_at, distance, duration, promotion_id, discount, discount_funded_by, collect_amount, collected_amount, assigned_at, arrived_at, picked_up_at, completed_at, actual_distance, fleet_id, vehicle_id
 `
 
 type SetTripStatusParams struct {
@@ -979,35 +5883,135 @@ func (q *Queries) SetTripStatus(ctx context.Context, arg SetTripStatusParams) (T
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Distance,
+		&i.Duration,
+		&i.PromotionID,
+		&i.Discount,
+		&i.DiscountFundedBy,
+		&i.CollectAmount,
+		&i.CollectedAmount,
+		&i.AssignedAt,
+		&i.ArrivedAt,
+		&i.PickedUpAt,
+		&i.CompletedAt,
+		&i.ActualDistance,
+		&i.FleetID,
+		&i.VehicleID,
 	)
 	return i, err
 }
 
-const trackCourierLocation = `-- name: TrackCourierLocation :one
-UPDATE couriers
-SET location = $2
-WHERE user_id = $1
-RETURNING id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at
+const setUploadExpiryWarned = `-- name: SetUploadExpiryWarned :exec
+UPDATE uploads
+SET expiry_warned_at = $1
+WHERE id = $2
 `
 
-type TrackCourierLocationParams struct {
-	UserID   uuid.NullUUID `json:"user_id"`
-	Location interface{}   `json:"location"`
+type SetUploadExpiryWarnedParams struct {
+	ExpiryWarnedAt sql.NullTime `json:"expiry_warned_at"`
+	ID             uuid.UUID    `json:"id"`
 }
 
-func (q *Queries) TrackCourierLocation(ctx context.Context, arg TrackCourierLocationParams) (Courier, error) {
-	row := q.db.QueryRowContext(ctx, trackCourierLocation, arg.UserID, arg.Location)
-	var i Courier
+func (q *Queries) SetUploadExpiryWarned(ctx context.Context, arg SetUploadExpiryWarnedParams) error {
+	_, err := q.db.ExecContext(ctx, setUploadExpiryWarned, arg.ExpiryWarnedAt, arg.ID)
+	return err
+}
+
+const setUserRating = `-- name: SetUserRating :exec
+UPDATE users
+SET rating = (
+  SELECT AVG(r.rating)::DOUBLE PRECISION FROM (
+    SELECT rating FROM trip_ratings
+    WHERE ratee_id = $1 AND target = 'SENDER'
+    ORDER BY created_at DESC
+    LIMIT $2
+  ) r
+), rating_count = rating_count + 1, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetUserRatingParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	WindowSize int32     `json:"window_size"`
+}
+
+func (q *Queries) SetUserRating(ctx context.Context, arg SetUserRatingParams) error {
+	_, err := q.db.ExecContext(ctx, setUserRating, arg.UserID, arg.WindowSize)
+	return err
+}
+
+const settleShiftBooking = `-- name: SettleShiftBooking :one
+UPDATE shift_bookings
+SET status = $1, earned = $2, guarantee_paid = $3, settled_at = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING id, shift_id, courier_id, status, checked_in_at, cancelled_at, earned, guarantee_paid, settled_at, created_at, updated_at
+`
+
+type SettleShiftBookingParams struct {
+	Status        string       `json:"status"`
+	Earned        int32        `json:"earned"`
+	GuaranteePaid int32        `json:"guarantee_paid"`
+	SettledAt     sql.NullTime `json:"settled_at"`
+	ID            uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) SettleShiftBooking(ctx context.Context, arg SettleShiftBookingParams) (ShiftBooking, error) {
+	row := q.db.QueryRowContext(ctx, settleShiftBooking,
+		arg.Status,
+		arg.Earned,
+		arg.GuaranteePaid,
+		arg.SettledAt,
+		arg.ID,
+	)
+	var i ShiftBooking
 	err := row.Scan(
 		&i.ID,
-		&i.Verified,
+		&i.ShiftID,
+		&i.CourierID,
 		&i.Status,
-		&i.Location,
-		&i.Ratings,
-		&i.Points,
-		&i.UserID,
+		&i.CheckedInAt,
+		&i.CancelledAt,
+		&i.Earned,
+		&i.GuaranteePaid,
+		&i.SettledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const spendPromotionBudget = `-- name: SpendPromotionBudget :one
+UPDATE promotions
+SET budget_used = budget_used + $2
+WHERE id = $1
+RETURNING id, code, description, discount_type, discount_value, max_discount, per_user_limit, budget, budget_used, first_trip_only, funded_by, product_id, zone_id, starts_at, ends_at, active, created_at, updated_at
+`
+
+type SpendPromotionBudgetParams struct {
+	ID     uuid.UUID `json:"id"`
+	Amount int32     `json:"amount"`
+}
+
+func (q *Queries) SpendPromotionBudget(ctx context.Context, arg SpendPromotionBudgetParams) (Promotion, error) {
+	row := q.db.QueryRowContext(ctx, spendPromotionBudget, arg.ID, arg.Amount)
+	var i Promotion
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MaxDiscount,
+		&i.PerUserLimit,
+		&i.Budget,
+		&i.BudgetUsed,
+		&i.FirstTripOnly,
+		&i.FundedBy,
 		&i.ProductID,
-		&i.TripID,
+		&i.ZoneID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -1018,7 +6022,7 @@ const unassignCourierTrip = `-- name: UnassignCourierTrip :one
 UPDATE couriers
 SET trip_id = null
 WHERE id = $1
-RETURNING id, verified, status, location, ratings, points, user_id, product_id, trip_id, created_at, updated_at
+RETURNING id, verified, status, location, points, user_id, product_id, trip_id, created_at, updated_at, rating, rating_count, tier, vehicle_id, location_updated_at, fleet_id
 `
 
 func (q *Queries) UnassignCourierTrip(ctx context.Context, id uuid.UUID) (Courier, error) {
@@ -1029,22 +6033,46 @@ func (q *Queries) UnassignCourierTrip(ctx context.Context, id uuid.UUID) (Courie
 		&i.Verified,
 		&i.Status,
 		&i.Location,
-		&i.Ratings,
 		&i.Points,
 		&i.UserID,
 		&i.ProductID,
 		&i.TripID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Rating,
+		&i.RatingCount,
+		&i.Tier,
+		&i.VehicleID,
+		&i.LocationUpdatedAt,
+		&i.FleetID,
 	)
 	return i, err
 }
 
+const updateLedgerAccountBalance = `-- name: UpdateLedgerAccountBalance :one
+UPDATE ledger_accounts
+SET balance = balance + $2, updated_at = NOW()
+WHERE id = $1
+RETURNING balance
+`
+
+type UpdateLedgerAccountBalanceParams struct {
+	ID     uuid.UUID `json:"id"`
+	Amount int32     `json:"amount"`
+}
+
+func (q *Queries) UpdateLedgerAccountBalance(ctx context.Context, arg UpdateLedgerAccountBalanceParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, updateLedgerAccountBalance, arg.ID, arg.Amount)
+	var balance int32
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const updateUpload = `-- name: UpdateUpload :one
 UPDATE uploads
-SET uri = COALESCE($2, uri), verification = COALESCE($3, verification)
+SET uri = COALESCE($2, uri), verification = COALESCE($3, verification), updated_at = NOW()
 WHERE id = $1
-RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at
+RETURNING id, type, uri, verification, courier_id, user_id, created_at, updated_at, rejection_reason, reviewed_by, reviewed_at, expires_at, expiry_warned_at
 `
 
 type UpdateUploadParams struct {
@@ -1065,6 +6093,11 @@ func (q *Queries) UpdateUpload(ctx context.Context, arg UpdateUploadParams) (Upl
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RejectionReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
 	)
 	return i, err
 }
@@ -1073,7 +6106,7 @@ const updateUserName = `-- name: UpdateUserName :one
 UPDATE users
 SET first_name = COALESCE($1, first_name), last_name = COALESCE($2, last_name)
 WHERE phone = $3
-RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at
+RETURNING id, first_name, last_name, phone, onboarding, created_at, updated_at, referral_code, device_id, is_admin, rating, rating_count
 `
 
 type UpdateUserNameParams struct {
@@ -1093,6 +6126,11 @@ func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams)
 		&i.Onboarding,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReferralCode,
+		&i.DeviceID,
+		&i.IsAdmin,
+		&i.Rating,
+		&i.RatingCount,
 	)
 	return i, err
 }
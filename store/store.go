@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -16,14 +17,16 @@ import (
 
 var (
 	log = internal.GetLogger()
+	db  *sql.DB
 )
 
 func InitializeStorage() (*sqlc.Queries, error) {
-	db, err := sql.Open(config.Config.Database.Rdbms.Env.Driver, config.Config.Database.Rdbms.Uri)
+	conn, err := sql.Open(config.Config.Database.Rdbms.Env.Driver, config.Config.Database.Rdbms.Uri)
 	if err != nil {
 		log.WithError(err).Errorf("open database connection")
 		return nil, err
 	}
+	db = conn
 
 	db.Exec(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %q;", "uuid-ossp"))
 	db.Exec("CREATE EXTENSION IF NOT EXISTS postgis;")
@@ -47,6 +50,29 @@ func InitializeStorage() (*sqlc.Queries, error) {
 	return dB, nil
 }
 
+// WithTx - run queries in a single database transaction
+func WithTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		log.WithError(err).Errorf("begin database transaction")
+		return err
+	}
+
+	if err := fn(sqlStore.New(tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.WithError(rollbackErr).Errorf("rollback database transaction")
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.WithError(err).Errorf("commit database transaction")
+		return err
+	}
+
+	return nil
+}
+
 // runDbMigration - setup database tables
 func runDatabaseMigration(db *sql.DB) error {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})